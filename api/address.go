@@ -8,6 +8,8 @@ import (
 type AddressResponse struct {
 	Balance          map[string]*big.Int `json:"balance"`
 	TransactionCount uint64              `json:"transaction_count"`
+	RewardAddress    types.Address       `json:"reward_address"`
+	CompoundRewards  bool                `json:"compound_rewards"`
 }
 
 func Address(address types.Address, height int) (*AddressResponse, error) {
@@ -19,6 +21,8 @@ func Address(address types.Address, height int) (*AddressResponse, error) {
 	response := AddressResponse{
 		Balance:          make(map[string]*big.Int),
 		TransactionCount: cState.GetNonce(address),
+		RewardAddress:    cState.GetRewardAddress(address),
+		CompoundRewards:  cState.IsCompoundRewards(address),
 	}
 
 	balances := cState.GetBalances(address)
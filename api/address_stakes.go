@@ -0,0 +1,64 @@
+package api
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"math/big"
+)
+
+type AddressStake struct {
+	PubKey   types.Pubkey     `json:"pub_key"`
+	Coin     types.CoinSymbol `json:"coin"`
+	Value    string           `json:"value"`
+	BipValue string           `json:"bip_value"`
+}
+
+type AddressFrozenFund struct {
+	Height       uint64           `json:"height"`
+	CandidateKey types.Pubkey     `json:"candidate_key"`
+	Coin         types.CoinSymbol `json:"coin"`
+	Value        string           `json:"value"`
+}
+
+type AddressStakesResponse struct {
+	Stakes        []AddressStake      `json:"stakes"`
+	TotalBipValue string              `json:"total_bip_value"`
+	FrozenFunds   []AddressFrozenFund `json:"frozen_funds"`
+}
+
+func AddressStakes(address types.Address, height int) (*AddressStakesResponse, error) {
+	cState, err := GetStateForHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	stakes := cState.GetStakesOfAddress(address)
+	frozenFunds := cState.GetFrozenFundsOfAddress(address)
+
+	response := AddressStakesResponse{
+		Stakes:      make([]AddressStake, len(stakes)),
+		FrozenFunds: make([]AddressFrozenFund, len(frozenFunds)),
+	}
+
+	total := big.NewInt(0)
+	for i, stake := range stakes {
+		response.Stakes[i] = AddressStake{
+			PubKey:   stake.PubKey,
+			Coin:     stake.Coin,
+			Value:    stake.Value.String(),
+			BipValue: stake.BipValue.String(),
+		}
+		total.Add(total, stake.BipValue)
+	}
+	response.TotalBipValue = total.String()
+
+	for i, fund := range frozenFunds {
+		response.FrozenFunds[i] = AddressFrozenFund{
+			Height:       fund.Height,
+			CandidateKey: fund.CandidateKey,
+			Coin:         fund.Coin,
+			Value:        fund.Value.String(),
+		}
+	}
+
+	return &response, nil
+}
@@ -35,8 +35,10 @@ var Routes = map[string]*rpcserver.RPCFunc{
 	"candidates":             rpcserver.NewRPCFunc(Candidates, "height,include_stakes"),
 	"candidate":              rpcserver.NewRPCFunc(Candidate, "pub_key,height"),
 	"validators":             rpcserver.NewRPCFunc(Validators, "height"),
+	"validator_set":          rpcserver.NewRPCFunc(ValidatorSet, "height"),
 	"address":                rpcserver.NewRPCFunc(Address, "address,height"),
 	"addresses":              rpcserver.NewRPCFunc(Addresses, "addresses,height"),
+	"address_stakes":         rpcserver.NewRPCFunc(AddressStakes, "address,height"),
 	"send_transaction":       rpcserver.NewRPCFunc(SendTransaction, "tx"),
 	"transaction":            rpcserver.NewRPCFunc(Transaction, "hash"),
 	"transactions":           rpcserver.NewRPCFunc(Transactions, "query,page,perPage"),
@@ -53,6 +55,13 @@ var Routes = map[string]*rpcserver.RPCFunc{
 	"min_gas_price":          rpcserver.NewRPCFunc(MinGasPrice, ""),
 	"genesis":                rpcserver.NewRPCFunc(Genesis, ""),
 	"missed_blocks":          rpcserver.NewRPCFunc(MissedBlocks, "pub_key,height"),
+	"params":                 rpcserver.NewRPCFunc(Params, "height"),
+	"proposals":              rpcserver.NewRPCFunc(Proposals, "height"),
+	"slashes":                rpcserver.NewRPCFunc(Slashes, "pub_key,address"),
+	"frozen_funds":           rpcserver.NewRPCFunc(FrozenFunds, "pub_key,address,height"),
+	"validator_stats":        rpcserver.NewRPCFunc(ValidatorStats, "pub_key"),
+	"rewards":                rpcserver.NewRPCFunc(Rewards, "address,recipient,pub_key,role,from,to"),
+	"candidate_apr":          rpcserver.NewRPCFunc(CandidateAPR, "pub_key"),
 }
 
 func RunAPI(b *minter.Blockchain, tmRPC *rpc.Local, cfg *config.Config) {
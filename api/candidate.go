@@ -14,18 +14,85 @@ type Stake struct {
 	BipValue string           `json:"bip_value"`
 }
 
+type CommissionChange struct {
+	Commission uint   `json:"commission"`
+	Height     uint64 `json:"height"`
+}
+
+type CandidateProfile struct {
+	Moniker         string `json:"moniker"`
+	Website         string `json:"website"`
+	Description     string `json:"description"`
+	SecurityContact string `json:"security_contact"`
+	LogoHash        string `json:"logo_hash"`
+}
+
+func makeResponseCandidateProfile(cState *state.StateDB, pubkey types.Pubkey) *CandidateProfile {
+	profile := cState.GetCandidateProfile(pubkey)
+	if profile == nil {
+		return nil
+	}
+
+	return &CandidateProfile{
+		Moniker:         profile.Moniker,
+		Website:         profile.Website,
+		Description:     profile.Description,
+		SecurityContact: profile.SecurityContact,
+		LogoHash:        profile.LogoHash,
+	}
+}
+
+type CandidateJail struct {
+	JailedUntil uint64       `json:"jailed_until"`
+	Tombstoned  bool         `json:"tombstoned"`
+	History     []JailRecord `json:"history"`
+}
+
+type JailRecord struct {
+	Height      uint64 `json:"height"`
+	JailedUntil uint64 `json:"jailed_until"`
+	Reason      byte   `json:"reason"`
+}
+
+func makeResponseCandidateJail(cState *state.StateDB, pubkey types.Pubkey) *CandidateJail {
+	jail := cState.GetCandidateJail(pubkey)
+	if jail == nil {
+		return nil
+	}
+
+	response := &CandidateJail{
+		JailedUntil: jail.JailedUntil,
+		Tombstoned:  jail.Tombstoned,
+		History:     make([]JailRecord, len(jail.History)),
+	}
+
+	for i, record := range jail.History {
+		response.History[i] = JailRecord{
+			Height:      record.Height,
+			JailedUntil: record.JailedUntil,
+			Reason:      record.Reason,
+		}
+	}
+
+	return response
+}
+
 type CandidateResponse struct {
-	RewardAddress  types.Address `json:"reward_address"`
-	OwnerAddress   types.Address `json:"owner_address"`
-	TotalStake     *big.Int      `json:"total_stake"`
-	PubKey         types.Pubkey  `json:"pub_key"`
-	Commission     uint          `json:"commission"`
-	Stakes         []Stake       `json:"stakes,omitempty"`
-	CreatedAtBlock uint          `json:"created_at_block"`
-	Status         byte          `json:"status"`
+	RewardAddress     types.Address     `json:"reward_address"`
+	OwnerAddress      types.Address     `json:"owner_address"`
+	TotalStake        *big.Int          `json:"total_stake"`
+	PubKey            types.Pubkey      `json:"pub_key"`
+	Commission        uint              `json:"commission"`
+	PendingCommission *CommissionChange `json:"pending_commission,omitempty"`
+	PendingPubKey     types.Pubkey      `json:"pending_pub_key,omitempty"`
+	Stakes            []Stake           `json:"stakes,omitempty"`
+	CreatedAtBlock    uint              `json:"created_at_block"`
+	Status            byte              `json:"status"`
+	Profile           *CandidateProfile `json:"profile,omitempty"`
+	Jail              *CandidateJail    `json:"jail,omitempty"`
 }
 
-func makeResponseCandidate(c state.Candidate, includeStakes bool) CandidateResponse {
+func makeResponseCandidate(cState *state.StateDB, c state.Candidate, includeStakes bool) CandidateResponse {
 	candidate := CandidateResponse{
 		RewardAddress:  c.RewardAddress,
 		OwnerAddress:   c.OwnerAddress,
@@ -34,6 +101,19 @@ func makeResponseCandidate(c state.Candidate, includeStakes bool) CandidateRespo
 		Commission:     c.Commission,
 		CreatedAtBlock: c.CreatedAtBlock,
 		Status:         c.Status,
+		Profile:        makeResponseCandidateProfile(cState, c.PubKey),
+		Jail:           makeResponseCandidateJail(cState, c.PubKey),
+	}
+
+	if change := cState.GetCandidateCommissionChange(c.PubKey); change != nil {
+		candidate.PendingCommission = &CommissionChange{
+			Commission: change.Commission,
+			Height:     change.Height,
+		}
+	}
+
+	if change := cState.GetCandidatePubKeyChange(c.PubKey); change != nil {
+		candidate.PendingPubKey = change.NewPubKey
 	}
 
 	if includeStakes {
@@ -62,6 +142,6 @@ func Candidate(pubkey []byte, height int) (*CandidateResponse, error) {
 		return nil, rpctypes.RPCError{Code: 404, Message: "Candidate not found"}
 	}
 
-	response := makeResponseCandidate(*candidate, true)
+	response := makeResponseCandidate(cState, *candidate, true)
 	return &response, nil
 }
@@ -10,7 +10,7 @@ func Candidates(height int, includeStakes bool) (*[]CandidateResponse, error) {
 
 	result := make([]CandidateResponse, len(candidates))
 	for i, candidate := range candidates {
-		result[i] = makeResponseCandidate(candidate, includeStakes)
+		result[i] = makeResponseCandidate(cState, candidate, includeStakes)
 	}
 
 	return &result, nil
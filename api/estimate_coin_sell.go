@@ -3,7 +3,6 @@ package api
 import (
 	"fmt"
 	"github.com/MinterTeam/minter-go-node/core/commissions"
-	"github.com/MinterTeam/minter-go-node/core/transaction"
 	"github.com/MinterTeam/minter-go-node/core/types"
 	"github.com/MinterTeam/minter-go-node/formula"
 	"github.com/MinterTeam/minter-go-node/rpc/lib/types"
@@ -41,7 +40,7 @@ func EstimateCoinSell(
 	}
 
 	commissionInBaseCoin := big.NewInt(commissions.ConvertTx)
-	commissionInBaseCoin.Mul(commissionInBaseCoin, transaction.CommissionMultiplier)
+	commissionInBaseCoin.Mul(commissionInBaseCoin, cState.GetParams().CommissionMultiplier)
 	commission := big.NewInt(0).Set(commissionInBaseCoin)
 
 	if coinToSell != types.GetBaseCoin() {
@@ -2,7 +2,6 @@ package api
 
 import (
 	"github.com/MinterTeam/minter-go-node/core/commissions"
-	"github.com/MinterTeam/minter-go-node/core/transaction"
 	"github.com/MinterTeam/minter-go-node/core/types"
 	"github.com/MinterTeam/minter-go-node/formula"
 	"github.com/MinterTeam/minter-go-node/rpc/lib/types"
@@ -43,7 +42,7 @@ func EstimateCoinSellAll(
 	}
 
 	commissionInBaseCoin := big.NewInt(commissions.ConvertTx)
-	commissionInBaseCoin.Mul(commissionInBaseCoin, transaction.CommissionMultiplier)
+	commissionInBaseCoin.Mul(commissionInBaseCoin, cState.GetParams().CommissionMultiplier)
 	commission := big.NewInt(0).Set(commissionInBaseCoin)
 
 	switch {
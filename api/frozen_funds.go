@@ -0,0 +1,77 @@
+package api
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rpc/lib/types"
+	"time"
+)
+
+// defaultBlockTime is used to estimate release time if time of recent blocks is unknown
+const defaultBlockTime = 5 * time.Second
+
+// blocksTimeDeltaCount is a number of blocks time delta is measured for
+const blocksTimeDeltaCount = 3
+
+type FrozenFundResponse struct {
+	Height               uint64           `json:"height"`
+	Address              types.Address    `json:"address"`
+	CandidateKey         types.Pubkey     `json:"candidate_key"`
+	Coin                 types.CoinSymbol `json:"coin"`
+	Value                string           `json:"value"`
+	EstimatedReleaseTime time.Time        `json:"estimated_release_time"`
+}
+
+// FrozenFunds returns pending frozen funds of given address and/or candidate with their release schedule
+func FrozenFunds(pubkey []byte, address types.Address, height int) (*[]FrozenFundResponse, error) {
+	cState, err := GetStateForHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	var funds []state.PendingFrozenFund
+	switch {
+	case len(pubkey) > 0:
+		funds = cState.GetFrozenFundsOfCandidate(pubkey)
+	case address != (types.Address{}):
+		funds = cState.GetFrozenFundsOfAddress(address)
+	default:
+		return nil, rpctypes.RPCError{Code: 400, Message: "Either pub_key or address should be provided"}
+	}
+
+	currentHeight := blockchain.Height()
+	blockTime := averageBlockTime(currentHeight)
+	now := time.Now().UTC()
+
+	response := make([]FrozenFundResponse, 0, len(funds))
+	for _, fund := range funds {
+		if address != (types.Address{}) && fund.Address != address {
+			continue
+		}
+
+		releaseTime := now
+		if fund.Height > currentHeight {
+			releaseTime = now.Add(time.Duration(fund.Height-currentHeight) * blockTime)
+		}
+
+		response = append(response, FrozenFundResponse{
+			Height:               fund.Height,
+			Address:              fund.Address,
+			CandidateKey:         fund.CandidateKey,
+			Coin:                 fund.Coin,
+			Value:                fund.Value.String(),
+			EstimatedReleaseTime: releaseTime,
+		})
+	}
+
+	return &response, nil
+}
+
+func averageBlockTime(height uint64) time.Duration {
+	delta, err := blockchain.GetBlocksTimeDelta(height, blocksTimeDeltaCount)
+	if err != nil || delta <= 0 {
+		return defaultBlockTime
+	}
+
+	return time.Duration(delta) * time.Second / blocksTimeDeltaCount
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+)
+
+type ParamChange struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type ProposalVote struct {
+	Voter  types.Address `json:"voter"`
+	Option byte          `json:"option"`
+}
+
+type UpgradePlan struct {
+	Name   string `json:"name"`
+	Height uint64 `json:"height"`
+	Info   string `json:"info"`
+}
+
+type ProposalResponse struct {
+	ID              uint64         `json:"id"`
+	Proposer        types.Address  `json:"proposer"`
+	Changes         []ParamChange  `json:"changes"`
+	SubmitHeight    uint64         `json:"submit_height"`
+	VotingEndHeight uint64         `json:"voting_end_height"`
+	Votes           []ProposalVote `json:"votes"`
+	Upgrade         *UpgradePlan   `json:"upgrade,omitempty"`
+}
+
+func makeResponseUpgradePlan(plan state.UpgradePlan) *UpgradePlan {
+	return &UpgradePlan{
+		Name:   plan.Name,
+		Height: plan.Height,
+		Info:   plan.Info,
+	}
+}
+
+func Params(height int) (*state.Params, error) {
+	cState, err := GetStateForHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	params := cState.GetParams()
+	return &params, nil
+}
+
+func Proposals(height int) (*[]ProposalResponse, error) {
+	cState, err := GetStateForHeight(height)
+	if err != nil {
+		return nil, err
+	}
+
+	proposals := cState.GetProposals()
+
+	response := make([]ProposalResponse, len(proposals))
+	for i, proposal := range proposals {
+		response[i] = ProposalResponse{
+			ID:              proposal.ID,
+			Proposer:        proposal.Proposer,
+			Changes:         make([]ParamChange, len(proposal.Changes)),
+			SubmitHeight:    proposal.SubmitHeight,
+			VotingEndHeight: proposal.VotingEndHeight,
+			Votes:           make([]ProposalVote, len(proposal.Votes)),
+		}
+
+		for j, change := range proposal.Changes {
+			response[i].Changes[j] = ParamChange{
+				Name:  change.Name,
+				Value: change.Value.String(),
+			}
+		}
+
+		for _, plan := range proposal.Upgrade {
+			response[i].Upgrade = makeResponseUpgradePlan(plan)
+		}
+
+		for j, vote := range proposal.Votes {
+			response[i].Votes[j] = ProposalVote{
+				Voter:  vote.Voter,
+				Option: vote.Option,
+			}
+		}
+	}
+
+	return &response, nil
+}
@@ -0,0 +1,203 @@
+package api
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/eventsdb/events"
+	"github.com/MinterTeam/minter-go-node/rpc/lib/types"
+	"math/big"
+	"sort"
+	"time"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	defaultRewardsDays = 30
+	aprDays            = 30
+)
+
+type DayRewards struct {
+	Date   string `json:"date"`
+	Amount string `json:"amount"`
+}
+
+type RewardsResponse struct {
+	Total string       `json:"total"`
+	Days  []DayRewards `json:"days"`
+}
+
+type CandidateAPRResponse struct {
+	PubKey       types.Pubkey `json:"pub_key"`
+	Days         uint64       `json:"days"`
+	Rewards      string       `json:"rewards"`
+	TotalStake   string       `json:"total_stake"`
+	APR          string       `json:"apr"`
+	DelegatorAPR string       `json:"delegator_apr"`
+}
+
+// Rewards returns daily totals of rewards earned by given address, received by given recipient, paid for stakes
+// of given validator or to given role. Dates are in YYYY-MM-DD format, last 30 days are returned by default.
+func Rewards(address types.Address, recipient types.Address, pubkey []byte, role string, from string,
+	to string) (*RewardsResponse, error) {
+	fromDay, toDay, err := parseDaysRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	edb := eventsdb.GetCurrent()
+
+	var rewards []eventsdb.DayRewards
+	switch {
+	case address != (types.Address{}):
+		rewards = edb.AddressRewards(address, fromDay, toDay)
+	case recipient != (types.Address{}):
+		rewards = edb.RecipientRewards(recipient, fromDay, toDay)
+	case len(pubkey) > 0:
+		rewards = sumDayRewards(
+			edb.ValidatorRewards(pubkey, events.RoleValidator, fromDay, toDay),
+			edb.ValidatorRewards(pubkey, events.RoleDelegator, fromDay, toDay),
+		)
+	case role != "":
+		r, err := parseRole(role)
+		if err != nil {
+			return nil, err
+		}
+		rewards = edb.RoleRewards(r, fromDay, toDay)
+	default:
+		return nil, rpctypes.RPCError{Code: 400, Message: "Either address, recipient, pub_key or role should be provided"}
+	}
+
+	total := big.NewInt(0)
+	response := &RewardsResponse{
+		Days: make([]DayRewards, len(rewards)),
+	}
+
+	for i, reward := range rewards {
+		total.Add(total, reward.Amount)
+		response.Days[i] = DayRewards{
+			Date:   time.Unix(int64(reward.Day)*24*60*60, 0).UTC().Format(dateLayout),
+			Amount: reward.Amount.String(),
+		}
+	}
+	response.Total = total.String()
+
+	return response, nil
+}
+
+// CandidateAPR estimates annual percentage rate of candidate's stake from rewards paid during last 30 days
+func CandidateAPR(pubkey []byte) (*CandidateAPRResponse, error) {
+	cState := blockchain.CurrentState()
+
+	candidate := cState.GetStateCandidate(pubkey)
+	if candidate == nil {
+		return nil, rpctypes.RPCError{Code: 404, Message: "Candidate not found"}
+	}
+
+	edb := eventsdb.GetCurrent()
+
+	toDay := eventsdb.DayOf(time.Now())
+	fromDay := toDay - aprDays + 1
+
+	validatorRewards := sumRewards(edb.ValidatorRewards(pubkey, events.RoleValidator, fromDay, toDay))
+	delegatorRewards := sumRewards(edb.ValidatorRewards(pubkey, events.RoleDelegator, fromDay, toDay))
+	rewards := big.NewInt(0).Add(validatorRewards, delegatorRewards)
+
+	return &CandidateAPRResponse{
+		PubKey:       pubkey,
+		Days:         aprDays,
+		Rewards:      rewards.String(),
+		TotalStake:   candidate.TotalBipStake.String(),
+		APR:          calcAPR(rewards, candidate.TotalBipStake),
+		DelegatorAPR: calcAPR(delegatorRewards, candidate.TotalBipStake),
+	}, nil
+}
+
+// calcAPR returns annual percentage rate with two decimal places
+func calcAPR(rewards *big.Int, stake *big.Int) string {
+	if stake == nil || stake.Sign() == 0 {
+		return "0.00"
+	}
+
+	hundredths := big.NewInt(0).Mul(rewards, big.NewInt(365*100*100))
+	hundredths.Div(hundredths, big.NewInt(aprDays))
+	hundredths.Div(hundredths, stake)
+
+	integer, fraction := big.NewInt(0).DivMod(hundredths, big.NewInt(100), big.NewInt(0))
+	return fmt.Sprintf("%s.%02d", integer, fraction.Int64())
+}
+
+func parseDaysRange(from string, to string) (uint64, uint64, error) {
+	toDay := eventsdb.DayOf(time.Now())
+	if to != "" {
+		t, err := time.Parse(dateLayout, to)
+		if err != nil {
+			return 0, 0, rpctypes.RPCError{Code: 400, Message: "Invalid to date", Data: err.Error()}
+		}
+		toDay = eventsdb.DayOf(t)
+	}
+
+	fromDay := toDay - defaultRewardsDays + 1
+	if from != "" {
+		t, err := time.Parse(dateLayout, from)
+		if err != nil {
+			return 0, 0, rpctypes.RPCError{Code: 400, Message: "Invalid from date", Data: err.Error()}
+		}
+		fromDay = eventsdb.DayOf(t)
+	}
+
+	if fromDay > toDay {
+		return 0, 0, rpctypes.RPCError{Code: 400, Message: "From date should not be after to date"}
+	}
+
+	return fromDay, toDay, nil
+}
+
+func parseRole(role string) (events.Role, error) {
+	for _, r := range []events.Role{events.RoleValidator, events.RoleDelegator, events.RoleDAO, events.RoleDevelopers} {
+		if r.String() == role {
+			return r, nil
+		}
+	}
+
+	return 0, rpctypes.RPCError{Code: 400, Message: fmt.Sprintf("Unknown role %s", role)}
+}
+
+func sumRewards(rewards []eventsdb.DayRewards) *big.Int {
+	total := big.NewInt(0)
+	for _, reward := range rewards {
+		total.Add(total, reward.Amount)
+	}
+
+	return total
+}
+
+// sumDayRewards merges daily rewards lists which are sorted by day
+func sumDayRewards(lists ...[]eventsdb.DayRewards) []eventsdb.DayRewards {
+	totals := map[uint64]*big.Int{}
+	var days []uint64
+	for _, list := range lists {
+		for _, reward := range list {
+			if _, has := totals[reward.Day]; !has {
+				totals[reward.Day] = big.NewInt(0)
+				days = append(days, reward.Day)
+			}
+			totals[reward.Day].Add(totals[reward.Day], reward.Amount)
+		}
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i] < days[j]
+	})
+
+	result := make([]eventsdb.DayRewards, len(days))
+	for i, day := range days {
+		result[i] = eventsdb.DayRewards{
+			Day:    day,
+			Amount: totals[day],
+		}
+	}
+
+	return result
+}
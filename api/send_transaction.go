@@ -1,11 +1,21 @@
 package api
 
 import (
+	"github.com/MinterTeam/minter-go-node/core/code"
 	"github.com/MinterTeam/minter-go-node/rpc/lib/types"
 	"github.com/tendermint/tendermint/rpc/core/types"
 )
 
 func SendTransaction(tx []byte) (*core_types.ResultBroadcastTx, error) {
+	// stateless checks are done in the worker pool of the node, so invalid transactions are rejected before they
+	// wait for the lock of mempool
+	if response := blockchain.PrevalidateTxs([][]byte{tx})[0]; response.Code != code.OK {
+		return nil, rpctypes.TxError{
+			Code: response.Code,
+			Log:  response.Log,
+		}
+	}
+
 	result, err := client.BroadcastTxSync(tx)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/eventsdb/events"
+	"github.com/MinterTeam/minter-go-node/rpc/lib/types"
+	"math/big"
+)
+
+type SlashResponse struct {
+	Height          uint64        `json:"height"`
+	Address         types.Address `json:"address"`
+	Amount          string        `json:"amount"`
+	Coin            string        `json:"coin"`
+	ValidatorPubKey types.Pubkey  `json:"validator_pub_key"`
+	Reason          string        `json:"reason"`
+	EvidenceHeight  uint64        `json:"evidence_height"`
+}
+
+// Slashes returns slash history of given validator and/or delegator
+func Slashes(pubkey []byte, address types.Address) (*[]SlashResponse, error) {
+	edb := eventsdb.GetCurrent()
+
+	var heights []uint64
+	switch {
+	case len(pubkey) > 0:
+		heights = edb.ValidatorSlashHeights(pubkey)
+	case address != (types.Address{}):
+		heights = edb.AddressSlashHeights(address)
+	default:
+		return nil, rpctypes.RPCError{Code: 400, Message: "Either pub_key or address should be provided"}
+	}
+
+	response := make([]SlashResponse, 0)
+	for _, height := range heights {
+		for _, event := range edb.LoadEvents(height) {
+			slash, ok := event.(events.SlashEvent)
+			if !ok {
+				continue
+			}
+
+			if len(pubkey) > 0 && !bytes.Equal(slash.ValidatorPubKey, pubkey) {
+				continue
+			}
+
+			if address != (types.Address{}) && slash.Address != address {
+				continue
+			}
+
+			response = append(response, SlashResponse{
+				Height:          height,
+				Address:         slash.Address,
+				Amount:          big.NewInt(0).SetBytes(slash.Amount).String(),
+				Coin:            slash.Coin.String(),
+				ValidatorPubKey: slash.ValidatorPubKey,
+				Reason:          slash.Reason.String(),
+				EvidenceHeight:  slash.EvidenceHeight,
+			})
+		}
+	}
+
+	return &response, nil
+}
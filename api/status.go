@@ -14,6 +14,8 @@ type StatusResponse struct {
 	LatestBlockHeight int64                    `json:"latest_block_height"`
 	LatestBlockTime   time.Time                `json:"latest_block_time"`
 	StateHistory      string                   `json:"state_history"`
+	UpgradePlan       *UpgradePlan             `json:"upgrade_plan"`
+	UpgradeRequired   bool                     `json:"upgrade_required"`
 	TmStatus          *core_types.ResultStatus `json:"tm_status"`
 }
 
@@ -28,6 +30,16 @@ func Status() (*StatusResponse, error) {
 		stateHistory = "on"
 	}
 
+	var upgradePlan *UpgradePlan
+	if plan := blockchain.CurrentState().GetUpgradePlan(); plan != nil {
+		upgradePlan = makeResponseUpgradePlan(*plan)
+	}
+
+	upgradeRequired := blockchain.UpgradeRequired()
+	if upgradeRequired != nil {
+		upgradePlan = makeResponseUpgradePlan(*upgradeRequired)
+	}
+
 	return &StatusResponse{
 		MinterVersion:     version.Version,
 		LatestBlockHash:   fmt.Sprintf("%X", result.SyncInfo.LatestBlockHash),
@@ -35,6 +47,8 @@ func Status() (*StatusResponse, error) {
 		LatestBlockHeight: result.SyncInfo.LatestBlockHeight,
 		LatestBlockTime:   result.SyncInfo.LatestBlockTime,
 		StateHistory:      stateHistory,
+		UpgradePlan:       upgradePlan,
+		UpgradeRequired:   upgradeRequired != nil,
 		TmStatus:          result,
 	}, nil
 }
@@ -79,6 +79,24 @@ func encodeTxData(decodedTx *transaction.Transaction) ([]byte, error) {
 		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.MultisendData))
 	case transaction.TypeEditCandidate:
 		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.EditCandidateData))
+	case transaction.TypeEditCommission:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.EditCommissionData))
+	case transaction.TypeRedelegate:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.RedelegateData))
+	case transaction.TypeSetCompoundRewards:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.SetCompoundRewardsData))
+	case transaction.TypeSetRewardAddress:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.SetRewardAddressData))
+	case transaction.TypeEditCandidateProfile:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.EditCandidateProfileData))
+	case transaction.TypeSubmitProposal:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.SubmitProposalData))
+	case transaction.TypeVoteProposal:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.VoteProposalData))
+	case transaction.TypeUnjail:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.UnjailData))
+	case transaction.TypeEditCandidatePubKey:
+		return cdc.MarshalJSON(decodedTx.GetDecodedData().(*transaction.EditCandidatePubKeyData))
 	}
 
 	return nil, rpctypes.RPCError{Code: 500, Message: "unknown tx type"}
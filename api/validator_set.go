@@ -0,0 +1,56 @@
+package api
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rpc/lib/types"
+)
+
+type ValidatorPowerChange struct {
+	PubKey   types.Pubkey `json:"pub_key"`
+	OldPower int64        `json:"old_power"`
+	NewPower int64        `json:"new_power"`
+}
+
+type ValidatorSetResponse struct {
+	Height      uint64                 `json:"height"`
+	ActiveSince uint64                 `json:"active_since"`
+	Validators  []ValidatorResponse    `json:"validators"`
+	Changes     []ValidatorPowerChange `json:"changes"`
+}
+
+// ValidatorSet returns validator set and voting powers at given height from node's own history,
+// so it does not depend on keep_state_history
+func ValidatorSet(height uint64) (*ValidatorSetResponse, error) {
+	if height == 0 {
+		height = blockchain.Height()
+	}
+
+	set, changes := blockchain.GetValidatorSet(height)
+	if set == nil {
+		return nil, rpctypes.RPCError{Code: 404, Message: "Validator set history is not available for given height"}
+	}
+
+	response := &ValidatorSetResponse{
+		Height:      height,
+		ActiveSince: set.Height,
+		Validators:  make([]ValidatorResponse, len(set.Validators)),
+		Changes:     make([]ValidatorPowerChange, len(changes)),
+	}
+
+	for i, val := range set.Validators {
+		response.Validators[i] = ValidatorResponse{
+			Pubkey:      val.PubKey.Data,
+			VotingPower: val.Power,
+		}
+	}
+
+	for i, change := range changes {
+		response.Changes[i] = ValidatorPowerChange{
+			PubKey:   change.PubKey,
+			OldPower: change.OldPower,
+			NewPower: change.NewPower,
+		}
+	}
+
+	return response, nil
+}
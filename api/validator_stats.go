@@ -0,0 +1,65 @@
+package api
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rpc/lib/types"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+type ValidatorWindowStats struct {
+	Window   uint64 `json:"window"`
+	Blocks   uint64 `json:"blocks"`
+	Signed   uint64 `json:"signed"`
+	Missed   uint64 `json:"missed"`
+	Proposed uint64 `json:"proposed"`
+	Uptime   string `json:"uptime"`
+}
+
+type ValidatorStatsResponse struct {
+	PubKey types.Pubkey           `json:"pub_key"`
+	Stats  []ValidatorWindowStats `json:"stats"`
+}
+
+func ValidatorStats(pubkey []byte) (*ValidatorStatsResponse, error) {
+	if len(pubkey) != 32 {
+		return nil, rpctypes.RPCError{Code: 400, Message: "Incorrect PubKey"}
+	}
+
+	var validatorPubKey ed25519.PubKeyEd25519
+	copy(validatorPubKey[:], pubkey)
+
+	var address [20]byte
+	copy(address[:], validatorPubKey.Address().Bytes())
+
+	stats := blockchain.GetValidatorStats(address)
+
+	response := &ValidatorStatsResponse{
+		PubKey: pubkey,
+		Stats:  make([]ValidatorWindowStats, len(stats)),
+	}
+
+	for i, s := range stats {
+		uptime := "0.00"
+		if total := s.Signed + s.Missed; total > 0 {
+			uptime = formatPercent(s.Signed, total)
+		}
+
+		response.Stats[i] = ValidatorWindowStats{
+			Window:   s.Window,
+			Blocks:   s.Blocks,
+			Signed:   s.Signed,
+			Missed:   s.Missed,
+			Proposed: s.Proposed,
+			Uptime:   uptime,
+		}
+	}
+
+	return response, nil
+}
+
+// formatPercent returns value/total in percents with two decimal places
+func formatPercent(value, total uint64) string {
+	hundredths := value * 10000 / total
+	return fmt.Sprintf("%d.%02d", hundredths/100, hundredths%100)
+}
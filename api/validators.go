@@ -5,8 +5,9 @@ import (
 )
 
 type ValidatorResponse struct {
-	Pubkey      types.Pubkey `json:"pub_key"`
-	VotingPower int64        `json:"voting_power"`
+	Pubkey      types.Pubkey      `json:"pub_key"`
+	VotingPower int64             `json:"voting_power"`
+	Profile     *CandidateProfile `json:"profile,omitempty"`
 }
 
 type ResponseValidators []ValidatorResponse
@@ -22,12 +23,20 @@ func Validators(height uint64) (*ResponseValidators, error) {
 		return nil, err
 	}
 
+	// state of given height may be pruned, profiles are omitted in this case
+	cState, _ := GetStateForHeight(int(height))
+
 	responseValidators := make(ResponseValidators, len(tmVals.Validators))
 	for i, val := range tmVals.Validators {
+		pubkey := types.Pubkey(val.PubKey.Bytes()[5:])
 		responseValidators[i] = ValidatorResponse{
-			Pubkey:      val.PubKey.Bytes()[5:],
+			Pubkey:      pubkey,
 			VotingPower: val.VotingPower,
 		}
+
+		if cState != nil {
+			responseValidators[i].Profile = makeResponseCandidateProfile(cState, pubkey)
+		}
 	}
 
 	return &responseValidators, nil
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/storage"
+	"github.com/spf13/cobra"
+	"os"
+	"text/tabwriter"
+)
+
+var DB = &cobra.Command{
+	Use:   "db",
+	Short: "Manage databases of the node",
+}
+
+var DBStats = &cobra.Command{
+	Use:   "stats",
+	Short: "Show size and number of keys of state, app and events stores of stopped node",
+	RunE:  dbStats,
+}
+
+func init() {
+	DB.AddCommand(DBStats)
+}
+
+func dbStats(cmd *cobra.Command, args []string) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STORE\tBACKEND\tKEYS\tSIZE\tPATH")
+
+	for _, store := range storage.Stores {
+		stats, err := storage.GetStats(cfg, store)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case stats.Path == "":
+			fmt.Fprintf(w, "%s\t%s\t-\t-\tin memory\n", stats.Store, stats.Backend)
+		case !stats.Exists:
+			fmt.Fprintf(w, "%s\t%s\t-\t-\t%s (not created)\n", stats.Store, stats.Backend, stats.Path)
+		default:
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", stats.Store, stats.Backend, stats.Keys, formatSize(stats.Size),
+				stats.Path)
+		}
+	}
+
+	return w.Flush()
+}
+
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/export"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/spf13/cobra"
+	"github.com/tendermint/tendermint/types"
+	"io"
+	"os"
+	"time"
+)
+
+var Export = &cobra.Command{
+	Use:   "export",
+	Short: "Export state of stopped node to genesis of a new chain",
+	RunE:  exportGenesis,
+}
+
+func init() {
+	Export.Flags().Uint64("height", 0, "height of exported state, should be retained by the node (default is the last height)")
+	Export.Flags().String("chain-id", "", "chain id of the new chain")
+	Export.Flags().String("genesis-time", "", "genesis time of the new chain in RFC3339 format (default is current time)")
+	Export.Flags().String("output", "genesis.json", "path of genesis file")
+	Export.Flags().String("stream", "", "path to keep export stream at (by default it is removed after genesis is written)")
+
+	Export.Flags().Int64("max-bytes", 10000000, "max size of a block in bytes")
+	Export.Flags().Int64("max-gas", 100000, "max gas of a block")
+	Export.Flags().Int64("time-iota-ms", 1000, "minimal time between consecutive blocks in milliseconds")
+	Export.Flags().Int64("evidence-max-age", 1000, "max age of evidence in blocks")
+
+	Export.Flags().Bool("reset-nonces", false, "set nonces of all accounts to zero")
+	Export.Flags().Bool("drop-empty-accounts", false, "drop accounts which have no coins and are not multisig")
+	Export.Flags().Bool("reset-accum-rewards", false, "set accumulated rewards of validators to zero")
+}
+
+func exportGenesis(cmd *cobra.Command, args []string) error {
+	flags := cmd.Flags()
+
+	chainID, _ := flags.GetString("chain-id")
+	if chainID == "" {
+		return fmt.Errorf("chain id of the new chain should be provided, see --chain-id")
+	}
+
+	genesisTime := time.Now()
+	if value, _ := flags.GetString("genesis-time"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("wrong genesis time: %v", err)
+		}
+		genesisTime = t
+	}
+
+	maxBytes, _ := flags.GetInt64("max-bytes")
+	maxGas, _ := flags.GetInt64("max-gas")
+	timeIota, _ := flags.GetInt64("time-iota-ms")
+	evidenceMaxAge, _ := flags.GetInt64("evidence-max-age")
+
+	var transforms []export.Transform
+	if reset, _ := flags.GetBool("reset-nonces"); reset {
+		transforms = append(transforms, export.ResetNonces)
+	}
+	if drop, _ := flags.GetBool("drop-empty-accounts"); drop {
+		transforms = append(transforms, export.DropEmptyAccounts)
+	}
+	if reset, _ := flags.GetBool("reset-accum-rewards"); reset {
+		transforms = append(transforms, export.ResetAccumRewards)
+	}
+
+	dbs, err := openNodeDatabases()
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	lastHeight := dbs.app.GetLastHeight()
+	if lastHeight == 0 {
+		return fmt.Errorf("node has no committed state")
+	}
+
+	height, _ := flags.GetUint64("height")
+	if height == 0 {
+		height = lastHeight
+	} else if height > lastHeight {
+		return fmt.Errorf("node is at height %d, can't export state of height %d", lastHeight, height)
+	}
+
+	currentState, err := state.New(height, dbs.state, true)
+	if err != nil {
+		return fmt.Errorf("can't load state of height %d: %v", height, err)
+	}
+
+	output, _ := flags.GetString("output")
+	streamPath, _ := flags.GetString("stream")
+	if streamPath == "" {
+		streamPath = output + ".jsonl"
+		defer os.Remove(streamPath)
+	}
+
+	stream, err := os.Create(streamPath)
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+
+	if err := export.Write(stream, currentState, height); err != nil {
+		return err
+	}
+
+	if _, err := stream.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// genesis is moved to its place only after export stream is verified
+	genesis, err := os.Create(output + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(genesis.Name())
+	defer genesis.Close()
+
+	appHash := [32]byte{}
+	err = export.WriteGenesis(stream, genesis, types.GenesisDoc{
+		GenesisTime: genesisTime,
+		ChainID:     chainID,
+		ConsensusParams: &types.ConsensusParams{
+			Block: types.BlockParams{
+				MaxBytes:   maxBytes,
+				MaxGas:     maxGas,
+				TimeIotaMs: timeIota,
+			},
+			Evidence: types.EvidenceParams{
+				MaxAge: evidenceMaxAge,
+			},
+			Validator: types.ValidatorParams{
+				PubKeyTypes: []string{types.ABCIPubKeyTypeEd25519},
+			},
+		},
+		AppHash: appHash[:],
+	}, transforms...)
+	if err != nil {
+		return err
+	}
+
+	if err := genesis.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(genesis.Name(), output); err != nil {
+		return err
+	}
+
+	fmt.Printf("State of height %d is exported to %s\n", height, output)
+	return nil
+}
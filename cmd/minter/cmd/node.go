@@ -14,6 +14,7 @@ import (
 	"github.com/tendermint/tendermint/abci/types"
 	bc "github.com/tendermint/tendermint/blockchain"
 	tmCfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
 	"github.com/tendermint/tendermint/libs/common"
 	tmNode "github.com/tendermint/tendermint/node"
 	"github.com/tendermint/tendermint/p2p"
@@ -21,6 +22,7 @@ import (
 	"github.com/tendermint/tendermint/proxy"
 	rpc "github.com/tendermint/tendermint/rpc/client"
 	tmTypes "github.com/tendermint/tendermint/types"
+	"net"
 	"time"
 )
 
@@ -75,7 +77,7 @@ func runNode() error {
 	}
 
 	// Recheck mempool. Currently kind a hack.
-	go recheckMempool(node, cfg)
+	go recheckMempool(app, node, cfg)
 
 	common.TrapSignal(log.With("module", "trap"), func() {
 		// Cleanup
@@ -90,7 +92,7 @@ func runNode() error {
 	select {}
 }
 
-func recheckMempool(node *tmNode.Node, config *config.Config) {
+func recheckMempool(app *minter.Blockchain, node *tmNode.Node, config *config.Config) {
 	ticker := time.NewTicker(time.Minute)
 	mempool := node.Mempool()
 	for {
@@ -99,6 +101,13 @@ func recheckMempool(node *tmNode.Node, config *config.Config) {
 			txs := mempool.ReapMaxTxs(config.Mempool.Size)
 			mempool.Flush()
 
+			// recover signatures of all transactions concurrently, CheckTx takes them from cache
+			raw := make([][]byte, len(txs))
+			for i, tx := range txs {
+				raw[i] = tx
+			}
+			app.PrevalidateTxs(raw)
+
 			for _, tx := range txs {
 				_ = mempool.CheckTx(tx, func(res *types.Response) {})
 			}
@@ -131,9 +140,19 @@ func startTendermintNode(app types.Application, cfg *tmCfg.Config) *tmNode.Node
 		panic(err)
 	}
 
+	privValidator, err := createPrivValidator(cfg)
+	if err != nil {
+		log.Fatal("failed to create private validator", "err", err)
+	}
+
+	// socket based private validator is already listening on priv_validator_laddr,
+	// so tendermint should not try to create another one on the same address
+	nodeCfg := *cfg
+	nodeCfg.PrivValidatorListenAddr = ""
+
 	node, err := tmNode.NewNode(
-		cfg,
-		privval.LoadOrGenFilePV(cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile()),
+		&nodeCfg,
+		privValidator,
 		nodeKey,
 		proxy.NewLocalClientCreator(app),
 		getGenesis,
@@ -155,6 +174,40 @@ func startTendermintNode(app types.Application, cfg *tmCfg.Config) *tmNode.Node
 	return node
 }
 
+// createPrivValidator returns validator's signer. If priv_validator_laddr is set, node listens on it
+// for connection of external signer (see cmd/signer) and validator's key is never touched on this machine.
+// Otherwise key is loaded from (or generated to) priv_validator_key_file.
+func createPrivValidator(cfg *tmCfg.Config) (tmTypes.PrivValidator, error) {
+	if cfg.PrivValidatorListenAddr == "" {
+		return privval.LoadOrGenFilePV(cfg.PrivValidatorKeyFile(), cfg.PrivValidatorStateFile()), nil
+	}
+
+	protocol, address := common.ProtocolAndAddress(cfg.PrivValidatorListenAddr)
+	ln, err := net.Listen(protocol, address)
+	if err != nil {
+		return nil, err
+	}
+
+	var listener net.Listener
+	switch protocol {
+	case "unix":
+		listener = privval.NewUnixListener(ln)
+	case "tcp":
+		listener = privval.NewTCPListener(ln, ed25519.GenPrivKey())
+	default:
+		return nil, fmt.Errorf("wrong priv_validator_laddr: expected either 'tcp' or 'unix' protocol, got %s", protocol)
+	}
+
+	log.Info("Waiting for external signer", "addr", cfg.PrivValidatorListenAddr)
+
+	endpoint := privval.NewSignerValidatorEndpoint(log.With("module", "privval"), listener)
+	if err := endpoint.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start private validator endpoint: %s", err)
+	}
+
+	return endpoint, nil
+}
+
 func getGenesis() (doc *tmTypes.GenesisDoc, e error) {
 	genesisFile := utils.GetMinterHome() + "/config/genesis.json"
 
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"github.com/MinterTeam/minter-go-node/core/minter"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/storage"
+	"github.com/spf13/cobra"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+	bc "github.com/tendermint/tendermint/blockchain"
+	"time"
+)
+
+// blocksTimeDeltaCount is a number of blocks BlocksTimeDelta is calculated over
+const blocksTimeDeltaCount = 3
+
+var Rollback = &cobra.Command{
+	Use:   "rollback",
+	Short: "Revert application state of stopped node to given height",
+	Long: `Revert application state of stopped node to given height. State of the height should be retained,
+i.e. the node should be run with keep_state_history. Tendermint replays blocks above the height on the next start.`,
+	RunE: rollback,
+}
+
+func init() {
+	Rollback.Flags().Uint64("height", 0, "height to revert application state to")
+}
+
+func rollback(cmd *cobra.Command, args []string) error {
+	target, _ := cmd.Flags().GetUint64("height")
+	if target == 0 {
+		return fmt.Errorf("height to revert to should be provided, see --height")
+	}
+
+	dbs, err := openNodeDatabases()
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	height := dbs.app.GetLastHeight()
+	if target >= height {
+		return fmt.Errorf("application is at height %d, can't revert it to height %d", height, target)
+	}
+
+	blockStore := bc.NewBlockStore(dbs.blockStore)
+	if blockStore.Height() < int64(height) {
+		return fmt.Errorf("block store is at height %d while application is at height %d", blockStore.Height(), height)
+	}
+
+	// app hash of a height is saved to the header of the next block
+	next := blockStore.LoadBlockMeta(int64(target) + 1)
+	if next == nil {
+		return fmt.Errorf("block %d is not found in block store", target+1)
+	}
+
+	// history of validator sets is keyed by heights of the chain, which continues from start height of genesis
+	validators := dbs.app.GetValidatorSet(dbs.app.GetStartHeight() + target + minter.ValidatorUpdateDelay)
+	if validators == nil {
+		return fmt.Errorf("history of validator sets does not cover height %d", target)
+	}
+
+	if err := state.Rollback(dbs.state, target, next.Header.AppHash); err != nil {
+		return err
+	}
+
+	rollbackAppDB(dbs.app, blockStore, target, next.Header.AppHash, validators.Validators)
+
+	if !cfg.ValidatorMode && storage.Backend(cfg, storage.Events) != storage.MemoryBackend {
+		eventsDB, err := storage.Open(cfg, storage.Events)
+		if err != nil {
+			return err
+		}
+		eventsdb.NewEventsDB(eventsDB).DeleteEventsAbove(target, func(height uint64) time.Time {
+			return blockStore.LoadBlockMeta(int64(height)).Header.Time
+		})
+		eventsDB.Close()
+	}
+
+	fmt.Printf("Application state is reverted from height %d to height %d, blocks above it will be replayed on start\n",
+		height, target)
+	return nil
+}
+
+// rollbackAppDB makes application database look as it was right after the block of target height was committed
+func rollbackAppDB(appDB *appdb.AppDB, blockStore *bc.BlockStore, target uint64, hash []byte,
+	validators abciTypes.ValidatorUpdates) {
+	appDB.SetLastHeight(target)
+	appDB.SetLastBlockHash(hash)
+
+	appDB.SaveValidators(validators)
+	appDB.DeleteValidatorSetsAbove(appDB.GetStartHeight() + target + minter.ValidatorUpdateDelay)
+
+	// signatures of target block are counted when the next block is committed. Statistics are aggregated by
+	// buckets, so blocks of the bucket of target which precede it are lost as well.
+	appDB.DeleteValidatorStatsSince(target / appdb.ValidatorStatsBucketSize)
+	appDB.SetValidatorStatsHeight(target)
+
+	// Tendermint replays blocks before the node is started, so BlocksTimeDelta can't be calculated from block store
+	delta := func(height uint64) (int, bool) {
+		if int64(height)-blocksTimeDeltaCount-1 < 1 {
+			return 0, false
+		}
+
+		blockA := blockStore.LoadBlockMeta(int64(height) - blocksTimeDeltaCount - 1)
+		blockB := blockStore.LoadBlockMeta(int64(height) - 1)
+
+		return int(blockB.Header.Time.Sub(blockA.Header.Time).Seconds()), true
+	}
+
+	for height := target + 1; height <= uint64(blockStore.Height()); height++ {
+		if value, ok := delta(height); ok {
+			appDB.SaveBlocksTimeDelta(height, value)
+		}
+	}
+
+	if value, ok := delta(target); ok {
+		appDB.SetLastBlocksTimeDelta(target, value)
+	}
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/cmd/utils"
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"github.com/MinterTeam/minter-go-node/core/minter"
+	"github.com/MinterTeam/minter-go-node/core/snapshot"
+	"github.com/MinterTeam/minter-go-node/storage"
+	"github.com/spf13/cobra"
+	bc "github.com/tendermint/tendermint/blockchain"
+	"github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/db"
+	tmNode "github.com/tendermint/tendermint/node"
+	sm "github.com/tendermint/tendermint/state"
+	"strings"
+)
+
+var Snapshot = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Create and restore state snapshots",
+}
+
+var SnapshotCreate = &cobra.Command{
+	Use:   "create",
+	Short: "Create snapshot of the last committed state of stopped node",
+	RunE:  snapshotCreate,
+}
+
+var SnapshotRestore = &cobra.Command{
+	Use:   "restore [file]",
+	Short: "Bootstrap empty node from snapshot verified against trusted app hash",
+	Args:  cobra.ExactArgs(1),
+	RunE:  snapshotRestore,
+}
+
+func init() {
+	SnapshotCreate.Flags().String("output", "", "path of snapshot file (default is $(home-dir)/data/snapshots/snapshot-$(height).bin)")
+	SnapshotRestore.Flags().String("trusted-hash", "", "app hash of snapshot's height obtained from a trusted source")
+
+	Snapshot.AddCommand(SnapshotCreate, SnapshotRestore)
+}
+
+// nodeDatabases are databases of the node snapshots are made of
+type nodeDatabases struct {
+	state      db.DB
+	app        *appdb.AppDB
+	tmState    db.DB
+	blockStore db.DB
+}
+
+func openNodeDatabases() (*nodeDatabases, error) {
+	tmConfig := config.GetTmConfig(cfg)
+
+	tmState, err := tmNode.DefaultDBProvider(&tmNode.DBContext{ID: "state", Config: tmConfig})
+	if err != nil {
+		return nil, err
+	}
+
+	blockStore, err := tmNode.DefaultDBProvider(&tmNode.DBContext{ID: "blockstore", Config: tmConfig})
+	if err != nil {
+		tmState.Close()
+		return nil, err
+	}
+
+	for _, store := range []string{storage.State, storage.App} {
+		if storage.Backend(cfg, store) == storage.MemoryBackend {
+			tmState.Close()
+			blockStore.Close()
+			return nil, fmt.Errorf("%s store is kept in memory and can't be accessed while node is stopped", store)
+		}
+	}
+
+	stateDB, err := storage.Open(cfg, storage.State)
+	if err != nil {
+		tmState.Close()
+		blockStore.Close()
+		return nil, err
+	}
+
+	return &nodeDatabases{
+		state:      stateDB,
+		app:        appdb.NewAppDB(cfg),
+		tmState:    tmState,
+		blockStore: blockStore,
+	}, nil
+}
+
+func (dbs *nodeDatabases) Close() {
+	dbs.state.Close()
+	dbs.app.Close()
+	dbs.tmState.Close()
+	dbs.blockStore.Close()
+}
+
+func snapshotCreate(cmd *cobra.Command, args []string) error {
+	dbs, err := openNodeDatabases()
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	height := dbs.app.GetLastHeight()
+	hash := dbs.app.GetLastBlockHash()
+	if height == 0 {
+		return fmt.Errorf("node has no committed state")
+	}
+
+	tmState := sm.LoadState(dbs.tmState)
+	if tmState.LastBlockHeight != int64(height) || !bytes.Equal(tmState.AppHash, hash) {
+		return fmt.Errorf("tendermint is at height %d while application is at height %d, start the node to sync them",
+			tmState.LastBlockHeight, height)
+	}
+
+	path, _ := cmd.Flags().GetString("output")
+	if path == "" {
+		if err := common.EnsureDir(minter.SnapshotsDir(), 0777); err != nil {
+			return err
+		}
+		path = minter.SnapshotPath(minter.SnapshotsDir(), height)
+	}
+
+	blockStore := bc.NewBlockStore(dbs.blockStore)
+	err = snapshot.Write(path, height, hash, dbs.state.Iterator(nil, nil), dbs.app.DB().Iterator(nil, nil),
+		func() (snapshot.TendermintData, error) {
+			return snapshot.TendermintData{
+				State:      tmState,
+				Block:      blockStore.LoadBlock(int64(height)),
+				SeenCommit: blockStore.LoadSeenCommit(int64(height)),
+			}, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Snapshot of height %d with app hash %X is saved to %s\n", height, hash, path)
+	return nil
+}
+
+func snapshotRestore(cmd *cobra.Command, args []string) error {
+	trustedHash, _ := cmd.Flags().GetString("trusted-hash")
+	hash, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(trustedHash), "0x"))
+	if err != nil || len(hash) == 0 {
+		return fmt.Errorf("trusted app hash should be provided as hex string, see --trusted-hash")
+	}
+
+	for _, dir := range []string{"/config", "/tmdata", "/data"} {
+		if err := common.EnsureDir(utils.GetMinterHome()+dir, 0777); err != nil {
+			return err
+		}
+	}
+
+	genesis, err := getGenesis()
+	if err != nil {
+		return err
+	}
+
+	reader, err := snapshot.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	dbs, err := openNodeDatabases()
+	if err != nil {
+		return err
+	}
+	defer dbs.Close()
+
+	if dbs.app.GetLastHeight() != 0 || bc.LoadBlockStoreStateJSON(dbs.blockStore).Height != 0 {
+		return fmt.Errorf("node already has state, snapshot can be restored only to empty node")
+	}
+
+	err = snapshot.Restore(reader, hash, snapshot.Databases{
+		State:            dbs.state,
+		App:              dbs.app.DB(),
+		TendermintState:  dbs.tmState,
+		TendermintBlocks: dbs.blockStore,
+		ChainID:          genesis.ChainID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Node is restored to height %d\n", reader.Metadata().Height)
+	return nil
+}
@@ -13,6 +13,10 @@ func main() {
 		cmd.RunNode,
 		cmd.ShowNodeId,
 		cmd.ShowValidator,
+		cmd.Snapshot,
+		cmd.Rollback,
+		cmd.Export,
+		cmd.DB,
 		cmd.Version)
 
 	rootCmd.PersistentFlags().StringVar(&utils.MinterHome, "home-dir", "", "base dir (default is $HOME/.minter)")
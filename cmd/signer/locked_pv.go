@@ -0,0 +1,38 @@
+package main
+
+import (
+	"github.com/tendermint/tendermint/crypto"
+	"github.com/tendermint/tendermint/privval"
+	"github.com/tendermint/tendermint/types"
+	"sync"
+)
+
+// lockedPV serializes signing requests of all connected nodes. FilePV checks each request
+// against last sign state and refuses to sign at lower height, round or step, or to sign
+// different data at the same ones, which protects validator from double signing
+type lockedPV struct {
+	lock sync.Mutex
+	pv   *privval.FilePV
+}
+
+func newLockedPV(pv *privval.FilePV) *lockedPV {
+	return &lockedPV{pv: pv}
+}
+
+func (l *lockedPV) GetPubKey() crypto.PubKey {
+	return l.pv.GetPubKey()
+}
+
+func (l *lockedPV) SignVote(chainID string, vote *types.Vote) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.pv.SignVote(chainID, vote)
+}
+
+func (l *lockedPV) SignProposal(chainID string, proposal *types.Proposal) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.pv.SignProposal(chainID, proposal)
+}
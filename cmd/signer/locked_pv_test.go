@@ -0,0 +1,53 @@
+package main
+
+import (
+	"github.com/tendermint/tendermint/privval"
+	"github.com/tendermint/tendermint/types"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLockedPV_DoubleSign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pv := newLockedPV(privval.GenFilePV(filepath.Join(dir, "key.json"), filepath.Join(dir, "state.json")))
+
+	newVote := func(height int64, blockHash byte) *types.Vote {
+		return &types.Vote{
+			Type:             types.PrecommitType,
+			Height:           height,
+			Round:            0,
+			BlockID:          types.BlockID{Hash: []byte{blockHash}},
+			Timestamp:        time.Now(),
+			ValidatorAddress: pv.GetPubKey().Address(),
+		}
+	}
+
+	if err := pv.SignVote("test", newVote(10, 1)); err != nil {
+		t.Fatalf("Failed to sign vote: %s", err)
+	}
+
+	// standby node asks to sign the same vote
+	if err := pv.SignVote("test", newVote(10, 1)); err != nil {
+		t.Fatalf("Failed to sign the same vote: %s", err)
+	}
+
+	if err := pv.SignVote("test", newVote(10, 2)); err == nil {
+		t.Fatalf("Conflicting vote should not be signed")
+	}
+
+	if err := pv.SignVote("test", newVote(9, 1)); err == nil {
+		t.Fatalf("Vote at lower height should not be signed")
+	}
+
+	if err := pv.SignVote("test", newVote(11, 2)); err != nil {
+		t.Fatalf("Failed to sign vote at next height: %s", err)
+	}
+}
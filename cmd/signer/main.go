@@ -0,0 +1,109 @@
+// Signer is a reference external signer for Minter validators.
+//
+// It holds validator's private key and serves signing requests of one or more nodes
+// which listen on priv_validator_laddr, e.g. an active node and its hot-standby replicas.
+// Last sign state (height, round and step) is kept by the signer itself and is shared by
+// all connections, so it never signs conflicting votes or proposals no matter which node asks.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/cmd/utils"
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/privval"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	keyFile   = flag.String("key-file", "", "path to validator's private key (default is $(home-dir)/config/priv_validator.json)")
+	stateFile = flag.String("state-file", "", "path to signer's last sign state (default is $(home-dir)/config/priv_validator_state.json)")
+	nodes     = flag.String("nodes", "", "comma separated priv_validator_laddr of nodes, e.g. tcp://10.0.0.1:26659,tcp://10.0.0.2:26659")
+	chainID   = flag.String("chain-id", config.DefaultNetworkId, "chain id")
+	timeout   = flag.Duration("timeout", 3*time.Second, "read/write timeout of connection to node")
+	retries   = flag.Int("retries", 10, "number of dial attempts before reconnecting to node from scratch")
+)
+
+func main() {
+	flag.StringVar(&utils.MinterHome, "home-dir", "", "base dir (default is $HOME/.minter)")
+	flag.Parse()
+
+	logger := log.NewTMLogger(log.NewSyncWriter(os.Stdout)).With("module", "signer")
+
+	if *keyFile == "" {
+		*keyFile = utils.GetMinterHome() + "/config/priv_validator.json"
+	}
+
+	if *stateFile == "" {
+		*stateFile = utils.GetMinterHome() + "/config/priv_validator_state.json"
+	}
+
+	if *nodes == "" {
+		fmt.Println("No nodes given, see -nodes flag")
+		os.Exit(1)
+	}
+
+	if !common.FileExists(*keyFile) {
+		fmt.Printf("Private validator file %s does not exist\n", *keyFile)
+		os.Exit(1)
+	}
+
+	pv := newLockedPV(privval.LoadFilePV(*keyFile, *stateFile))
+	logger.Info("Loaded validator key", "pubkey", fmt.Sprintf("Mp%x", pv.GetPubKey().Bytes()[5:]))
+
+	for _, addr := range strings.Split(*nodes, ",") {
+		addr = strings.TrimSpace(addr)
+
+		dialer, err := getDialer(addr)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		go serve(logger.With("node", addr), dialer, pv)
+	}
+
+	// last sign state is persisted on each signature, so there is nothing to clean up
+	common.TrapSignal(logger, func() {})
+
+	// Run forever
+	select {}
+}
+
+// serve keeps connection to node alive. Connection is re-established from scratch
+// each time node goes down, so signer may be started before nodes and survive their restarts
+func serve(logger log.Logger, dialer privval.SocketDialer, pv *lockedPV) {
+	for {
+		endpoint := privval.NewSignerServiceEndpoint(logger, *chainID, pv, dialer)
+		privval.SignerServiceEndpointTimeoutReadWrite(*timeout)(endpoint)
+		privval.SignerServiceEndpointConnRetries(*retries)(endpoint)
+
+		if err := endpoint.Start(); err != nil {
+			logger.Error("Failed to connect to node", "err", err)
+			time.Sleep(*timeout)
+			continue
+		}
+
+		logger.Info("Connected to node")
+		<-endpoint.Quit()
+		logger.Info("Disconnected from node")
+	}
+}
+
+func getDialer(addr string) (privval.SocketDialer, error) {
+	protocol, address := common.ProtocolAndAddress(addr)
+
+	switch protocol {
+	case "unix":
+		return privval.DialUnixFn(address), nil
+	case "tcp":
+		return privval.DialTCPFn(address, *timeout, ed25519.GenPrivKey()), nil
+	default:
+		return nil, fmt.Errorf("wrong node address %s: expected either 'tcp' or 'unix' protocol, got %s", addr, protocol)
+	}
+}
@@ -226,6 +226,15 @@ type BaseConfig struct {
 	// Database directory
 	DBPath string `mapstructure:"db_dir"`
 
+	// Database backends and directories of state, app and events stores. Empty backend means db_backend,
+	// empty directory means $(home-dir)/data. Relative directories are resolved against home directory
+	StateDBBackend  string `mapstructure:"state_db_backend"`
+	StateDBPath     string `mapstructure:"state_db_dir"`
+	AppDBBackend    string `mapstructure:"app_db_backend"`
+	AppDBPath       string `mapstructure:"app_db_dir"`
+	EventsDBBackend string `mapstructure:"events_db_backend"`
+	EventsDBPath    string `mapstructure:"events_db_dir"`
+
 	// Address to listen for GUI connections
 	GUIListenAddress string `mapstructure:"gui_listen_addr"`
 
@@ -238,6 +247,24 @@ type BaseConfig struct {
 
 	APISimultaneousRequests int `mapstructure:"api_simultaneous_requests"`
 
+	// Comma separated list of windows (in blocks) to collect validators' signing statistics over
+	ValidatorStatsWindows string `mapstructure:"validator_stats_windows"`
+
+	// Interval (in blocks) of state snapshots, 0 disables snapshots
+	SnapshotInterval uint64 `mapstructure:"snapshot_interval"`
+
+	// Number of recent snapshots to keep
+	SnapshotKeepRecent int `mapstructure:"snapshot_keep_recent"`
+
+	// Interval (in blocks) of invariants checks, 0 disables checks
+	InvariantsCheckPeriod uint64 `mapstructure:"invariants_check_period"`
+
+	// Halt the node if invariants are violated
+	InvariantsHalt bool `mapstructure:"invariants_halt"`
+
+	// Number of workers checking signatures and stateless rules of incoming transactions, 0 means number of CPUs
+	CheckTxWorkers int `mapstructure:"check_tx_workers"`
+
 	LogPath string `mapstructure:"log_path"`
 }
 
@@ -260,6 +287,12 @@ func DefaultBaseConfig() BaseConfig {
 		ValidatorMode:           false,
 		KeepStateHistory:        false,
 		APISimultaneousRequests: 100,
+		ValidatorStatsWindows:   "1000,10000,100000",
+		SnapshotInterval:        0,
+		SnapshotKeepRecent:      2,
+		InvariantsCheckPeriod:   720,
+		InvariantsHalt:          false,
+		CheckTxWorkers:          0,
 		LogPath:                 "stdout",
 		LogFormat:               LogFormatPlain,
 	}
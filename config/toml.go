@@ -82,6 +82,27 @@ keep_state_history = {{ .BaseConfig.KeepStateHistory }}
 # Limit for simultaneous requests to API
 api_simultaneous_requests = {{ .BaseConfig.APISimultaneousRequests }}
 
+# Comma separated list of windows (in blocks) to collect validators' signing statistics over
+validator_stats_windows = "{{ .BaseConfig.ValidatorStatsWindows }}"
+
+# Interval (in blocks) of state snapshots which new nodes can be started from. Snapshots are
+# saved to $(home-dir)/data/snapshots. Set to 0 to disable snapshots
+snapshot_interval = {{ .BaseConfig.SnapshotInterval }}
+
+# Number of recent snapshots to keep
+snapshot_keep_recent = {{ .BaseConfig.SnapshotKeepRecent }}
+
+# Interval (in blocks) of state invariants checks. Set to 1 to check every block (e.g. on testnets)
+# or to 0 to disable checks
+invariants_check_period = {{ .BaseConfig.InvariantsCheckPeriod }}
+
+# Halt the node if state invariants are violated
+invariants_halt = {{ .BaseConfig.InvariantsHalt }}
+
+# Number of workers recovering signatures and checking stateless rules of transactions sent via API
+# and rechecked in mempool. Set to 0 to use number of CPUs
+check_tx_workers = {{ .BaseConfig.CheckTxWorkers }}
+
 # If this node is many blocks behind the tip of the chain, FastSync
 # allows them to catchup quickly by downloading blocks in parallel
 # and verifying their commits
@@ -93,6 +114,17 @@ db_backend = "{{ .BaseConfig.DBBackend }}"
 # Database directory
 db_path = "{{ js .BaseConfig.DBPath }}"
 
+# Database backends and directories of node's stores: state, app (blocks metadata, validators) and
+# events. Empty backend means db_backend, empty directory means $(home-dir)/data. Relative directories
+# are resolved against home directory. memdb backend keeps a store in memory, its data is lost on stop.
+# State and app may be kept in memory only together and with db_backend = "memdb"
+state_db_backend = "{{ .BaseConfig.StateDBBackend }}"
+state_db_dir = "{{ js .BaseConfig.StateDBPath }}"
+app_db_backend = "{{ .BaseConfig.AppDBBackend }}"
+app_db_dir = "{{ js .BaseConfig.AppDBPath }}"
+events_db_backend = "{{ .BaseConfig.EventsDBBackend }}"
+events_db_dir = "{{ js .BaseConfig.EventsDBPath }}"
+
 # Output level for logging, including package level options
 log_level = "{{ .BaseConfig.LogLevel }}"
 
@@ -108,6 +140,10 @@ log_path = "{{ .BaseConfig.LogPath }}"
 priv_validator_key_file = "{{ js .BaseConfig.PrivValidatorKey }}"
 priv_validator_state_file = "{{ js .BaseConfig.PrivValidatorState }}"
 
+# TCP or UNIX socket address for the node to listen on for connections from an external signer.
+# If set, validator's private key is not loaded from priv_validator_key_file, see cmd/signer
+priv_validator_laddr = "{{ .BaseConfig.PrivValidatorListenAddr }}"
+
 # Path to the JSON file containing the private key to use for node authentication in the p2p protocol
 node_key_file = "{{ js .BaseConfig.NodeKey}}"
 
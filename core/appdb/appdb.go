@@ -3,8 +3,8 @@ package appdb
 import (
 	"encoding/binary"
 	"errors"
-	"github.com/MinterTeam/minter-go-node/cmd/utils"
 	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/MinterTeam/minter-go-node/storage"
 	"github.com/tendermint/go-amino"
 	"github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/db"
@@ -15,13 +15,12 @@ var (
 )
 
 const (
-	hashPath           = "hash"
-	heightPath         = "height"
-	startHeightPath    = "startHeight"
-	blockTimeDeltaPath = "blockDelta"
-	validatorsPath     = "validators"
-
-	dbName = "app"
+	hashPath                  = "hash"
+	heightPath                = "height"
+	startHeightPath           = "startHeight"
+	blockTimeDeltaPath        = "blockDelta"
+	blockTimeDeltaHistoryPath = "blockDeltaHistory"
+	validatorsPath            = "validators"
 )
 
 type AppDB struct {
@@ -32,6 +31,11 @@ func (appDB *AppDB) Close() {
 	appDB.db.Close()
 }
 
+// DB returns underlying database
+func (appDB *AppDB) DB() db.DB {
+	return appDB.db
+}
+
 func (appDB *AppDB) GetLastBlockHash() []byte {
 	var hash [32]byte
 
@@ -115,7 +119,7 @@ type LastBlocksTimeDelta struct {
 func (appDB *AppDB) GetLastBlocksTimeDelta(height uint64) (int, error) {
 	result := appDB.db.Get([]byte(blockTimeDeltaPath))
 	if result == nil {
-		return 0, errors.New("no info about LastBlocksTimeDelta is available")
+		return appDB.getBlocksTimeDelta(height)
 	}
 
 	data := LastBlocksTimeDelta{}
@@ -125,12 +129,47 @@ func (appDB *AppDB) GetLastBlocksTimeDelta(height uint64) (int, error) {
 	}
 
 	if data.Height != height {
-		return 0, errors.New("no info about LastBlocksTimeDelta is available")
+		return appDB.getBlocksTimeDelta(height)
 	}
 
 	return data.Delta, nil
 }
 
+// SaveBlocksTimeDelta records BlocksTimeDelta of given height for the case the block is replayed by Tendermint,
+// which makes block store unavailable to the application
+func (appDB *AppDB) SaveBlocksTimeDelta(height uint64, delta int) {
+	data, err := cdc.MarshalBinaryBare(delta)
+	if err != nil {
+		panic(err)
+	}
+
+	appDB.db.Set(getBlocksTimeDeltaKey(height), data)
+}
+
+func (appDB *AppDB) getBlocksTimeDelta(height uint64) (int, error) {
+	result := appDB.db.Get(getBlocksTimeDeltaKey(height))
+	if result == nil {
+		return 0, errors.New("no info about LastBlocksTimeDelta is available")
+	}
+
+	var delta int
+	if err := cdc.UnmarshalBinaryBare(result, &delta); err != nil {
+		panic(err)
+	}
+
+	return delta, nil
+}
+
+func getBlocksTimeDeltaKey(height uint64) []byte {
+	key := make([]byte, 0, len(blockTimeDeltaHistoryPath)+8)
+	key = append(key, blockTimeDeltaHistoryPath...)
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+
+	return append(key, b...)
+}
+
 func (appDB *AppDB) SetLastBlocksTimeDelta(height uint64, delta int) {
 	data, err := cdc.MarshalBinaryBare(LastBlocksTimeDelta{
 		Height: height,
@@ -146,6 +185,13 @@ func (appDB *AppDB) SetLastBlocksTimeDelta(height uint64, delta int) {
 
 func NewAppDB(cfg *config.Config) *AppDB {
 	return &AppDB{
-		db: db.NewDB(dbName, db.DBBackendType(cfg.DBBackend), utils.GetMinterHome()+"/data"),
+		db: storage.MustOpen(cfg, storage.App),
+	}
+}
+
+// NewAppDBFromDB returns application database stored in given database
+func NewAppDBFromDB(database db.DB) *AppDB {
+	return &AppDB{
+		db: database,
 	}
 }
@@ -0,0 +1,113 @@
+package appdb
+
+import (
+	"encoding/binary"
+)
+
+// ValidatorStatsBucketSize is a number of blocks which signing statistics are aggregated by
+const ValidatorStatsBucketSize = 1000
+
+const (
+	validatorStatsPath       = "validatorStats"
+	validatorStatsHeightPath = "validatorStatsHeight"
+)
+
+// ValidatorStats holds signing statistics of a validator
+type ValidatorStats struct {
+	Signed   uint64
+	Missed   uint64
+	Proposed uint64
+}
+
+func (s *ValidatorStats) Add(other ValidatorStats) {
+	s.Signed += other.Signed
+	s.Missed += other.Missed
+	s.Proposed += other.Proposed
+}
+
+// GetValidatorStats returns signing statistics of validator with given tendermint address in given bucket
+func (appDB *AppDB) GetValidatorStats(address [20]byte, bucket uint64) ValidatorStats {
+	result := appDB.db.Get(getValidatorStatsKey(address, bucket))
+	if len(result) == 0 {
+		return ValidatorStats{}
+	}
+
+	var stats ValidatorStats
+	if err := cdc.UnmarshalBinaryBare(result, &stats); err != nil {
+		panic(err)
+	}
+
+	return stats
+}
+
+func (appDB *AppDB) SetValidatorStats(address [20]byte, bucket uint64, stats ValidatorStats) {
+	data, err := cdc.MarshalBinaryBare(stats)
+	if err != nil {
+		panic(err)
+	}
+
+	appDB.db.Set(getValidatorStatsKey(address, bucket), data)
+}
+
+// GetValidatorStatsHeight returns height of the last block which signing statistics are saved for
+func (appDB *AppDB) GetValidatorStatsHeight() uint64 {
+	result := appDB.db.Get([]byte(validatorStatsHeightPath))
+	if len(result) == 0 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(result)
+}
+
+func (appDB *AppDB) SetValidatorStatsHeight(height uint64) {
+	h := make([]byte, 8)
+	binary.BigEndian.PutUint64(h, height)
+	appDB.db.Set([]byte(validatorStatsHeightPath), h)
+}
+
+// PruneValidatorStats removes statistics of all validators which is older than given bucket
+func (appDB *AppDB) PruneValidatorStats(bucket uint64) {
+	appDB.deleteValidatorStats(func(b uint64) bool {
+		return b < bucket
+	})
+}
+
+// DeleteValidatorStatsSince removes statistics of all validators in given bucket and newer ones
+func (appDB *AppDB) DeleteValidatorStatsSince(bucket uint64) {
+	appDB.deleteValidatorStats(func(b uint64) bool {
+		return b >= bucket
+	})
+}
+
+func (appDB *AppDB) deleteValidatorStats(filter func(bucket uint64) bool) {
+	prefix := []byte(validatorStatsPath)
+
+	it := appDB.db.Iterator(prefix, append(append([]byte{}, prefix...), 0xff))
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		key := it.Key()
+		if len(key) != len(prefix)+20+8 {
+			continue
+		}
+
+		if filter(binary.BigEndian.Uint64(key[len(key)-8:])) {
+			keys = append(keys, append([]byte{}, key...))
+		}
+	}
+	it.Close()
+
+	for _, key := range keys {
+		appDB.db.Delete(key)
+	}
+}
+
+func getValidatorStatsKey(address [20]byte, bucket uint64) []byte {
+	key := make([]byte, 0, len(validatorStatsPath)+20+8)
+	key = append(key, validatorStatsPath...)
+	key = append(key, address[:]...)
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, bucket)
+
+	return append(key, b...)
+}
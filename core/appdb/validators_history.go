@@ -0,0 +1,72 @@
+package appdb
+
+import (
+	"encoding/binary"
+	"github.com/tendermint/tendermint/abci/types"
+	"math"
+)
+
+const validatorsHistoryPath = "validatorsHistory"
+
+// ValidatorSet is a set of validators with their voting powers which is active in consensus since Height
+type ValidatorSet struct {
+	Height     uint64
+	Validators types.ValidatorUpdates
+}
+
+// SaveValidatorSet records validator set which becomes active in consensus at given height
+func (appDB *AppDB) SaveValidatorSet(height uint64, vals types.ValidatorUpdates) {
+	data, err := cdc.MarshalBinaryBare(vals)
+	if err != nil {
+		panic(err)
+	}
+
+	appDB.db.Set(getValidatorSetKey(height), data)
+}
+
+// GetValidatorSet returns validator set which is active in consensus at given height.
+// Returns nil if there is no recorded set at or below given height.
+func (appDB *AppDB) GetValidatorSet(height uint64) *ValidatorSet {
+	it := appDB.db.ReverseIterator([]byte(validatorsHistoryPath), getValidatorSetKey(height+1))
+	defer it.Close()
+
+	if !it.Valid() {
+		return nil
+	}
+
+	var vals types.ValidatorUpdates
+	if err := cdc.UnmarshalBinaryBare(it.Value(), &vals); err != nil {
+		panic(err)
+	}
+
+	key := it.Key()
+
+	return &ValidatorSet{
+		Height:     binary.BigEndian.Uint64(key[len(key)-8:]),
+		Validators: vals,
+	}
+}
+
+// DeleteValidatorSetsAbove removes validator sets which become active in consensus above given height
+func (appDB *AppDB) DeleteValidatorSetsAbove(height uint64) {
+	it := appDB.db.Iterator(getValidatorSetKey(height+1), getValidatorSetKey(math.MaxUint64))
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, append([]byte{}, it.Key()...))
+	}
+	it.Close()
+
+	for _, key := range keys {
+		appDB.db.Delete(key)
+	}
+}
+
+func getValidatorSetKey(height uint64) []byte {
+	key := make([]byte, 0, len(validatorsHistoryPath)+8)
+	key = append(key, validatorsHistoryPath...)
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, height)
+
+	return append(key, b...)
+}
@@ -0,0 +1,58 @@
+package appdb
+
+import (
+	"github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+	"testing"
+)
+
+func TestAppDB_GetValidatorSet(t *testing.T) {
+	appDB := &AppDB{db: db.NewMemDB()}
+
+	if appDB.GetValidatorSet(10) != nil {
+		t.Fatalf("Validator set should not exist")
+	}
+
+	appDB.SaveValidatorSet(1, types.ValidatorUpdates{types.Ed25519ValidatorUpdate([]byte{1}, 100)})
+	appDB.SaveValidatorSet(122, types.ValidatorUpdates{types.Ed25519ValidatorUpdate([]byte{1}, 50), types.Ed25519ValidatorUpdate([]byte{2}, 50)})
+
+	cases := []struct {
+		height      uint64
+		activeSince uint64
+		count       int
+	}{
+		{1, 1, 1},
+		{121, 1, 1},
+		{122, 122, 2},
+		{1000, 122, 2},
+	}
+
+	for _, c := range cases {
+		set := appDB.GetValidatorSet(c.height)
+		if set == nil {
+			t.Fatalf("Validator set at height %d not found", c.height)
+		}
+
+		if set.Height != c.activeSince || len(set.Validators) != c.count {
+			t.Fatalf("Wrong validator set at height %d. Expected %d validators since %d, got %d since %d", c.height, c.count, c.activeSince, len(set.Validators), set.Height)
+		}
+	}
+}
+
+func TestAppDB_DeleteValidatorSetsAbove(t *testing.T) {
+	appDB := &AppDB{db: db.NewMemDB()}
+
+	for _, height := range []uint64{1, 50, 100} {
+		appDB.SaveValidatorSet(height, types.ValidatorUpdates{types.Ed25519ValidatorUpdate([]byte{byte(height)}, 100)})
+	}
+
+	appDB.DeleteValidatorSetsAbove(50)
+
+	if set := appDB.GetValidatorSet(1000); set == nil || set.Height != 50 {
+		t.Fatalf("Validator set since 50 should be the last one, got %v", set)
+	}
+
+	if set := appDB.GetValidatorSet(1); set == nil || set.Height != 1 {
+		t.Fatalf("Validator set since 1 should not be deleted, got %v", set)
+	}
+}
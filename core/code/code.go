@@ -30,15 +30,24 @@ const (
 	MinimumValueToBuyReached  uint32 = 303
 
 	// candidate
-	CandidateExists       uint32 = 401
-	WrongCommission       uint32 = 402
-	CandidateNotFound     uint32 = 403
-	StakeNotFound         uint32 = 404
-	InsufficientStake     uint32 = 405
-	IsNotOwnerOfCandidate uint32 = 406
-	IncorrectPubKey       uint32 = 407
-	StakeShouldBePositive uint32 = 408
-	TooLowStake           uint32 = 409
+	CandidateExists         uint32 = 401
+	WrongCommission         uint32 = 402
+	CandidateNotFound       uint32 = 403
+	StakeNotFound           uint32 = 404
+	InsufficientStake       uint32 = 405
+	IsNotOwnerOfCandidate   uint32 = 406
+	IncorrectPubKey         uint32 = 407
+	StakeShouldBePositive   uint32 = 408
+	TooLowStake             uint32 = 409
+	SameCandidate           uint32 = 410
+	InvalidCandidateProfile uint32 = 411
+	CandidateJailed         uint32 = 412
+	CandidateNotJailed      uint32 = 413
+	CandidateTombstoned     uint32 = 414
+	StakeShareExceeded      uint32 = 415
+	SelfStakeTooLow         uint32 = 416
+	PubKeyInUse             uint32 = 417
+	PubKeyChangeLocked      uint32 = 418
 
 	// check
 	CheckInvalidLock uint32 = 501
@@ -54,4 +63,11 @@ const (
 	MultisigNotExists       uint32 = 603
 	IncorrectMultiSignature uint32 = 604
 	TooLargeOwnersList      uint32 = 605
+
+	// governance
+	ProposalNotFound uint32 = 701
+	InvalidProposal  uint32 = 702
+	WrongVoteOption  uint32 = 703
+	NotAVoter        uint32 = 704
+	TooManyProposals uint32 = 705
 )
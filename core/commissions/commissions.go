@@ -12,6 +12,16 @@ const (
 	PayloadByte           int64 = 2
 	ToggleCandidateStatus int64 = 100
 	EditCandidate         int64 = 10000
+	EditCommission        int64 = 10000
+	RedelegateTx          int64 = 400
+	SetCompoundRewards    int64 = 100
+	SetRewardAddress      int64 = 100
+	EditCandidateProfile  int64 = 1000
+	CandidateProfileByte  int64 = 2
+	SubmitProposal        int64 = 100000
+	VoteProposal          int64 = 100
+	Unjail                int64 = 100
+	EditCandidatePubKey   int64 = 10000
 	MultisendDelta        int64 = 5
 	RedeemCheckTx         int64 = SendTx * 3
 )
@@ -0,0 +1,211 @@
+// Package export implements streaming export of the state to a file and its conversion to genesis of a new chain.
+//
+// Export is a newline-delimited JSON stream. Each line is a record with kind and value:
+//
+//	{"kind":"header","value":{"format":"1","height":"100"}}
+//	{"kind":"accounts","value":{"address":"Mx...","balance":[...],"nonce":"1"}}
+//	...
+//	{"kind":"totals","value":{"records":"123","base_coin":"...","coins":[...]}}
+//
+// Values of state records are amino JSON encoded entries of types.AppState and kinds are names of
+// types.AppState fields they belong to. Totals record closes the stream and holds amounts of coins in exported
+// state, so a truncated or modified stream is detected while it is read.
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/MinterTeam/go-amino"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"io"
+	"math/big"
+	"reflect"
+)
+
+// Format is a version of export format
+const Format uint = 1
+
+// Kinds of service records
+const (
+	KindHeader = "header"
+	KindTotals = "totals"
+)
+
+// Header opens export stream
+type Header struct {
+	Format uint   `json:"format"`
+	Height uint64 `json:"height"`
+}
+
+// Totals closes export stream
+type Totals struct {
+	Records  uint64      `json:"records"`
+	BaseCoin *big.Int    `json:"base_coin"`
+	Coins    []CoinTotal `json:"coins"`
+}
+
+// CoinTotal is a volume of custom coin
+type CoinTotal struct {
+	Coin   types.CoinSymbol `json:"coin"`
+	Volume *big.Int         `json:"volume"`
+}
+
+type record struct {
+	Kind  string          `json:"kind"`
+	Value json.RawMessage `json:"value"`
+}
+
+// recordTypes are types of records which amounts of coins are counted of
+var recordTypes = map[string]reflect.Type{
+	state.ExportAccounts:    reflect.TypeOf(types.Account{}),
+	state.ExportCoins:       reflect.TypeOf(types.Coin{}),
+	state.ExportFrozenFunds: reflect.TypeOf(types.FrozenFund{}),
+	state.ExportCandidates:  reflect.TypeOf(types.Candidate{}),
+	state.ExportValidators:  reflect.TypeOf(types.Validator{}),
+}
+
+// Write streams state of given height to w. Volumes of custom coins are checked against their owned amounts
+// before the stream is closed.
+func Write(w io.Writer, s *state.StateDB, height uint64) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	enc.SetEscapeHTML(false)
+
+	write := func(kind string, value interface{}) error {
+		data, err := amino.MarshalJSON(value)
+		if err != nil {
+			return fmt.Errorf("can't encode %s record: %v", kind, err)
+		}
+
+		return enc.Encode(record{Kind: kind, Value: data})
+	}
+
+	if err := write(KindHeader, Header{Format: Format, Height: height}); err != nil {
+		return err
+	}
+
+	totals := state.NewTotals()
+	var count uint64
+	err := s.ExportStream(height, func(kind string, value interface{}) error {
+		totals.AddRecord(kind, value)
+		count++
+
+		return write(kind, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := totals.CheckVolumes(); err != nil {
+		return fmt.Errorf("exported state is inconsistent: %v", err)
+	}
+
+	if err := write(KindTotals, newTotals(count, totals)); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Read reads export stream from r and calls fn for every state record. Amounts of coins are counted while
+// records are read and checked against totals of the stream after the last record.
+func Read(r io.Reader, fn func(kind string, value json.RawMessage) error) (Header, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	var header Header
+	var rec record
+	if err := dec.Decode(&rec); err != nil || rec.Kind != KindHeader {
+		return header, errors.New("export stream should start with header")
+	}
+
+	if err := amino.UnmarshalJSON(rec.Value, &header); err != nil {
+		return header, fmt.Errorf("can't decode header: %v", err)
+	}
+
+	if header.Format != Format {
+		return header, fmt.Errorf("unsupported export format %d", header.Format)
+	}
+
+	totals := state.NewTotals()
+	var count uint64
+	for {
+		rec = record{}
+		if err := dec.Decode(&rec); err == io.EOF {
+			return header, errors.New("export stream is truncated")
+		} else if err != nil {
+			return header, fmt.Errorf("can't read record %d: %v", count+1, err)
+		}
+
+		if rec.Kind == KindTotals {
+			break
+		}
+
+		if t, ok := recordTypes[rec.Kind]; ok {
+			value := reflect.New(t)
+			if err := amino.UnmarshalJSON(rec.Value, value.Interface()); err != nil {
+				return header, fmt.Errorf("can't decode %s record: %v", rec.Kind, err)
+			}
+
+			totals.AddRecord(rec.Kind, value.Elem().Interface())
+		}
+
+		count++
+		if err := fn(rec.Kind, rec.Value); err != nil {
+			return header, err
+		}
+	}
+
+	if dec.More() {
+		return header, errors.New("export stream has records after totals")
+	}
+
+	var expected Totals
+	if err := amino.UnmarshalJSON(rec.Value, &expected); err != nil {
+		return header, fmt.Errorf("can't decode totals: %v", err)
+	}
+
+	if err := totals.CheckVolumes(); err != nil {
+		return header, fmt.Errorf("exported state is inconsistent: %v", err)
+	}
+
+	if !newTotals(count, totals).equal(expected) {
+		return header, errors.New("totals of exported state do not match records of the stream")
+	}
+
+	return header, nil
+}
+
+func (t Totals) equal(other Totals) bool {
+	if t.Records != other.Records || other.BaseCoin == nil || t.BaseCoin.Cmp(other.BaseCoin) != 0 ||
+		len(t.Coins) != len(other.Coins) {
+		return false
+	}
+
+	for i := range t.Coins {
+		if t.Coins[i].Coin != other.Coins[i].Coin || other.Coins[i].Volume == nil ||
+			t.Coins[i].Volume.Cmp(other.Coins[i].Volume) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newTotals(count uint64, totals *state.Totals) Totals {
+	result := Totals{
+		Records:  count,
+		BaseCoin: totals.BaseCoin,
+	}
+
+	for _, coin := range totals.Coins() {
+		result.Coins = append(result.Coins, CoinTotal{
+			Coin:   coin,
+			Volume: totals.Volumes[coin],
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/MinterTeam/go-amino"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/tendermint/tendermint/libs/db"
+	tmTypes "github.com/tendermint/tendermint/types"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func getState(t *testing.T) (*state.StateDB, uint64) {
+	s, err := state.New(0, db.NewMemDB(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	symbol := types.StrToCoinSymbol("TEST")
+	s.CreateCoin(symbol, "TEST NAME", big.NewInt(30), 50, big.NewInt(1000))
+
+	for i := byte(1); i <= 3; i++ {
+		s.AddBalance(types.Address{i}, types.GetBaseCoin(), big.NewInt(100))
+		s.AddBalance(types.Address{i}, symbol, big.NewInt(10))
+	}
+
+	_, version, err := s.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, uint64(version)
+}
+
+func TestWriteRead(t *testing.T) {
+	s, height := getState(t)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, s, height); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{}
+	header, err := Read(bytes.NewReader(buf.Bytes()), func(kind string, value json.RawMessage) error {
+		counts[kind]++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Height != height || counts[state.ExportAccounts] != 3 || counts[state.ExportCoins] != 1 {
+		t.Fatalf("Wrong records are read: %v", counts)
+	}
+
+	// change balance of an account
+	tampered := strings.Replace(buf.String(), `"100"`, `"200"`, 1)
+	if _, err := Read(strings.NewReader(tampered), func(string, json.RawMessage) error { return nil }); err == nil {
+		t.Fatal("Modified stream should not be accepted")
+	}
+
+	// drop totals
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	truncated := strings.Join(lines[:len(lines)-1], "\n")
+	if _, err := Read(strings.NewReader(truncated), func(string, json.RawMessage) error { return nil }); err == nil {
+		t.Fatal("Truncated stream should not be accepted")
+	}
+}
+
+func TestWriteGenesis(t *testing.T) {
+	s, height := getState(t)
+
+	var stream bytes.Buffer
+	if err := Write(&stream, s, height); err != nil {
+		t.Fatal(err)
+	}
+
+	var genesis bytes.Buffer
+	err := WriteGenesis(&stream, &genesis, tmTypes.GenesisDoc{
+		ChainID: "minter-test-network",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := tmTypes.GenesisDocFromJSON(genesis.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.ChainID != "minter-test-network" {
+		t.Fatalf("Wrong chain id %s", doc.ChainID)
+	}
+
+	var appState types.AppState
+	if err := amino.UnmarshalJSON(doc.AppState, &appState); err != nil {
+		t.Fatal(err)
+	}
+
+	expected, _ := amino.MarshalJSON(s.Export(height))
+	got, _ := amino.MarshalJSON(appState)
+	if !bytes.Equal(expected, got) {
+		t.Fatalf("App state of genesis does not match exported state.\nExpected: %s\nGot: %s", expected, got)
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	empty, _ := amino.MarshalJSON(types.Account{
+		Address: types.Address{1},
+		Balance: []types.Balance{{Coin: types.GetBaseCoin(), Value: big.NewInt(0)}},
+		Nonce:   5,
+	})
+
+	if _, keep, err := DropEmptyAccounts(state.ExportAccounts, empty); err != nil || keep {
+		t.Fatalf("Empty account should be dropped")
+	}
+
+	result, keep, err := ResetNonces(state.ExportAccounts, empty)
+	if err != nil || !keep {
+		t.Fatalf("Account should be kept: %v", err)
+	}
+
+	var account types.Account
+	if err := amino.UnmarshalJSON(result, &account); err != nil || account.Nonce != 0 {
+		t.Fatalf("Nonce should be reset, got %d", account.Nonce)
+	}
+
+	validator, _ := amino.MarshalJSON(types.Validator{
+		PubKey:        types.Pubkey{1},
+		TotalBipStake: big.NewInt(100),
+		AccumReward:   big.NewInt(10),
+	})
+
+	result, keep, err = ResetAccumRewards(state.ExportValidators, validator)
+	if err != nil || !keep {
+		t.Fatalf("Validator should be kept: %v", err)
+	}
+
+	var val types.Validator
+	if err := amino.UnmarshalJSON(result, &val); err != nil || val.AccumReward.Sign() != 0 {
+		t.Fatalf("Accumulated reward should be reset, got %s", val.AccumReward)
+	}
+}
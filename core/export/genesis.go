@@ -0,0 +1,147 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	tmAmino "github.com/tendermint/go-amino"
+	cryptoAmino "github.com/tendermint/tendermint/crypto/encoding/amino"
+	tmTypes "github.com/tendermint/tendermint/types"
+	"io"
+)
+
+// appStatePlaceholder marks place of app state in encoded genesis document
+const appStatePlaceholder = "MINTER_EXPORTED_APP_STATE"
+
+// singleKinds are kinds of records which are single values of types.AppState instead of its lists
+var singleKinds = map[string]bool{
+	state.ExportParams:       true,
+	state.ExportUpgradePlan:  true,
+	state.ExportMaxGas:       true,
+	state.ExportStartHeight:  true,
+	state.ExportTotalSlashed: true,
+}
+
+var genesisCdc = tmAmino.NewCodec()
+
+func init() {
+	cryptoAmino.RegisterAmino(genesisCdc)
+}
+
+// WriteGenesis converts export stream read from r to genesis document of a new chain and writes it to w.
+// Fields of doc except app state describe the new chain. App state is written record by record, so exported
+// state is never held in memory as a whole. Nothing should be taken from w if an error is returned, as the
+// error may be detected after most of the document is written. Transforms are applied to every record in
+// given order after the record is counted in totals of the stream.
+func WriteGenesis(r io.Reader, w io.Writer, doc tmTypes.GenesisDoc, transforms ...Transform) error {
+	doc.AppState = json.RawMessage(`"` + appStatePlaceholder + `"`)
+	if err := doc.ValidateAndComplete(); err != nil {
+		return err
+	}
+
+	encoded, err := genesisCdc.MarshalJSONIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	parts := bytes.SplitN(encoded, []byte(`"`+appStatePlaceholder+`"`), 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("can't find app state in genesis document")
+	}
+
+	bw := bufio.NewWriter(w)
+	appState := newAppStateWriter(bw)
+
+	bw.Write(parts[0])
+	_, err = Read(r, func(kind string, value json.RawMessage) error {
+		for _, transform := range transforms {
+			result, keep, err := transform(kind, value)
+			if err != nil || !keep {
+				return err
+			}
+			value = result
+		}
+
+		return appState.write(kind, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := appState.close(); err != nil {
+		return err
+	}
+	bw.Write(parts[1])
+
+	return bw.Flush()
+}
+
+// appStateWriter writes records of export stream as a JSON object of types.AppState. Records of the same list
+// are expected to follow each other, which is how StateDB.ExportStream passes them.
+type appStateWriter struct {
+	w       *bufio.Writer
+	kind    string
+	items   int
+	written map[string]bool
+}
+
+func newAppStateWriter(w *bufio.Writer) *appStateWriter {
+	return &appStateWriter{
+		w:       w,
+		written: map[string]bool{},
+	}
+}
+
+func (a *appStateWriter) write(kind string, value json.RawMessage) error {
+	if kind != a.kind || singleKinds[kind] {
+		if a.written[kind] {
+			return fmt.Errorf("records of kind %s are not grouped", kind)
+		}
+
+		a.closeList()
+		if len(a.written) == 0 {
+			a.w.WriteString("{")
+		} else {
+			a.w.WriteString(",")
+		}
+
+		a.written[kind] = true
+		a.kind = kind
+		a.items = 0
+
+		fmt.Fprintf(a.w, "%q:", kind)
+		if singleKinds[kind] {
+			_, err := a.w.Write(value)
+			return err
+		}
+
+		a.w.WriteString("[")
+	}
+
+	if a.items > 0 {
+		a.w.WriteString(",")
+	}
+	a.items++
+
+	_, err := a.w.Write(value)
+	return err
+}
+
+func (a *appStateWriter) closeList() {
+	if a.kind != "" && !singleKinds[a.kind] {
+		a.w.WriteString("]")
+	}
+}
+
+func (a *appStateWriter) close() error {
+	if len(a.written) == 0 {
+		_, err := a.w.WriteString("{}")
+		return err
+	}
+
+	a.closeList()
+	_, err := a.w.WriteString("}")
+	return err
+}
@@ -0,0 +1,70 @@
+package export
+
+import (
+	"encoding/json"
+	"github.com/MinterTeam/go-amino"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"math/big"
+)
+
+// Transform changes record of export stream while genesis is built from it. Record is dropped if keep is false.
+type Transform func(kind string, value json.RawMessage) (result json.RawMessage, keep bool, err error)
+
+// ResetNonces sets nonces of all accounts to zero
+func ResetNonces(kind string, value json.RawMessage) (json.RawMessage, bool, error) {
+	if kind != state.ExportAccounts {
+		return value, true, nil
+	}
+
+	var account types.Account
+	if err := amino.UnmarshalJSON(value, &account); err != nil {
+		return nil, false, err
+	}
+
+	account.Nonce = 0
+
+	result, err := amino.MarshalJSON(account)
+	return result, true, err
+}
+
+// DropEmptyAccounts drops accounts which have no coins and are not multisig
+func DropEmptyAccounts(kind string, value json.RawMessage) (json.RawMessage, bool, error) {
+	if kind != state.ExportAccounts {
+		return value, true, nil
+	}
+
+	var account types.Account
+	if err := amino.UnmarshalJSON(value, &account); err != nil {
+		return nil, false, err
+	}
+
+	if account.MultisigData != nil {
+		return value, true, nil
+	}
+
+	for _, balance := range account.Balance {
+		if balance.Value.Sign() > 0 {
+			return value, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// ResetAccumRewards sets accumulated rewards of all validators to zero
+func ResetAccumRewards(kind string, value json.RawMessage) (json.RawMessage, bool, error) {
+	if kind != state.ExportValidators {
+		return value, true, nil
+	}
+
+	var validator types.Validator
+	if err := amino.UnmarshalJSON(value, &validator); err != nil {
+		return nil, false, err
+	}
+
+	validator.AccumReward = big.NewInt(0)
+
+	result, err := amino.MarshalJSON(validator)
+	return result, true, err
+}
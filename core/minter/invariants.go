@@ -0,0 +1,77 @@
+package minter
+
+import (
+	"github.com/MinterTeam/go-amino"
+	"github.com/MinterTeam/minter-go-node/cmd/utils"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/log"
+	tmTypes "github.com/tendermint/tendermint/types"
+	"math/big"
+)
+
+// checkInvariants checks invariants of the state every invariantsCheckPeriod blocks and logs their violations.
+// Node halts on violations only if halting is enabled in config, critical violations are marked in logs.
+func (app *Blockchain) checkInvariants(height uint64) {
+	if app.invariantsCheckPeriod == 0 || height%app.invariantsCheckPeriod != 0 {
+		return
+	}
+
+	logger := log.With("module", "invariants")
+
+	genesisAlloc, err := app.getGenesisAlloc()
+	if err != nil {
+		logger.Error("Can't load genesis", "err", err, "height", height)
+		return
+	}
+
+	report, err := state.NewForCheckFromDeliver(app.stateCheck).CheckInvariants(genesisAlloc)
+	if err != nil {
+		logger.Error("Can't check invariants", "err", err, "height", height)
+		return
+	}
+
+	for _, violation := range report.Violations {
+		ctx := []interface{}{"invariant", violation.Invariant, "height", report.Height}
+		if violation.Coin != (types.CoinSymbol{}) {
+			ctx = append(ctx, "coin", violation.Coin.String())
+		}
+		if violation.Address != nil {
+			ctx = append(ctx, "address", violation.Address.String())
+		}
+		if len(violation.PubKey) > 0 {
+			ctx = append(ctx, "pubkey", violation.PubKey.String())
+		}
+		if violation.Critical {
+			ctx = append(ctx, "critical", true)
+		}
+
+		logger.Error("Invariant violated: "+violation.Message, ctx...)
+	}
+
+	if app.invariantsHalt && report.Failed() {
+		logger.Error("STATE INVARIANTS ARE VIOLATED. Block processing is halted", "height", height)
+		app.halt()
+	}
+}
+
+// getGenesisAlloc returns base coin allocated in genesis. It is read from genesis file once if the node was
+// not started from genesis in this run.
+func (app *Blockchain) getGenesisAlloc() (*big.Int, error) {
+	if app.genesisAlloc != nil {
+		return app.genesisAlloc, nil
+	}
+
+	genesis, err := tmTypes.GenesisDocFromFile(utils.GetMinterHome() + "/config/genesis.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var genesisState types.AppState
+	if err := amino.UnmarshalJSON(genesis.AppState, &genesisState); err != nil {
+		return nil, err
+	}
+
+	app.genesisAlloc = state.GenesisAlloc(genesisState)
+	return app.genesisAlloc, nil
+}
@@ -2,10 +2,11 @@ package minter
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/MinterTeam/go-amino"
-	"github.com/MinterTeam/minter-go-node/cmd/utils"
 	"github.com/MinterTeam/minter-go-node/config"
 	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"github.com/MinterTeam/minter-go-node/core/code"
 	"github.com/MinterTeam/minter-go-node/core/rewards"
 	"github.com/MinterTeam/minter-go-node/core/state"
 	"github.com/MinterTeam/minter-go-node/core/transaction"
@@ -14,6 +15,7 @@ import (
 	"github.com/MinterTeam/minter-go-node/eventsdb"
 	"github.com/MinterTeam/minter-go-node/eventsdb/events"
 	"github.com/MinterTeam/minter-go-node/log"
+	"github.com/MinterTeam/minter-go-node/storage"
 	"github.com/MinterTeam/minter-go-node/version"
 	"github.com/danil-lashin/tendermint/rpc/lib/types"
 	abciTypes "github.com/tendermint/tendermint/abci/types"
@@ -56,12 +58,29 @@ type Blockchain struct {
 	rewards            *big.Int // Rewards pool
 	validatorsStatuses map[[20]byte]int8
 
+	// signing statistics of validators in current block and windows to report them over. Signatures of current
+	// block belong to the previous one.
+	validatorStats        map[[20]byte]*appdb.ValidatorStats
+	blockProposer         *[20]byte
+	validatorStatsWindows []uint64
+
 	// local rpc client for Tendermint
 	tmNode *tmNode.Node
 
 	// currentMempool is responsive for prevent sending multiple transactions from one address in one block
 	currentMempool sync.Map
 
+	snapshotInterval   uint64
+	snapshotKeepRecent int
+	snapshotInProgress uint32
+
+	invariantsCheckPeriod uint64
+	invariantsHalt        bool
+	genesisAlloc          *big.Int // base coin allocated in genesis, loaded on first check of invariants
+
+	// prevalidator runs stateless checks of transactions concurrently before they reach CheckTx
+	prevalidator *transaction.Prevalidator
+
 	lock    sync.RWMutex
 	wg      sync.WaitGroup // wg is used for graceful node shutdown
 	stopped uint32
@@ -69,22 +88,32 @@ type Blockchain struct {
 
 // Creates Minter Blockchain instance, should be only called once
 func NewMinterBlockchain(cfg *config.Config) *Blockchain {
-	dbType := db.DBBackendType(cfg.DBBackend)
-	ldb := db.NewDB("state", dbType, utils.GetMinterHome()+"/data")
+	ldb := storage.MustOpen(cfg, storage.State)
 
 	// Initiate Application DB. Used for persisting data like current block, validators, etc.
 	applicationDB := appdb.NewAppDB(cfg)
 
+	statsWindows, err := parseValidatorStatsWindows(cfg.ValidatorStatsWindows)
+	if err != nil {
+		panic(err)
+	}
+
 	blockchain = &Blockchain{
-		stateDB:             ldb,
-		appDB:               applicationDB,
-		height:              applicationDB.GetLastHeight(),
-		lastCommittedHeight: applicationDB.GetLastHeight(),
-		currentMempool:      sync.Map{},
+		stateDB:               ldb,
+		appDB:                 applicationDB,
+		height:                applicationDB.GetLastHeight(),
+		lastCommittedHeight:   applicationDB.GetLastHeight(),
+		currentMempool:        sync.Map{},
+		validatorStats:        map[[20]byte]*appdb.ValidatorStats{},
+		validatorStatsWindows: statsWindows,
+		snapshotInterval:      cfg.SnapshotInterval,
+		snapshotKeepRecent:    cfg.SnapshotKeepRecent,
+		invariantsCheckPeriod: cfg.InvariantsCheckPeriod,
+		invariantsHalt:        cfg.InvariantsHalt,
+		prevalidator:          transaction.NewPrevalidator(cfg.CheckTxWorkers),
 	}
 
 	// Set stateDeliver and stateCheck
-	var err error
 	blockchain.stateDeliver, err = state.New(blockchain.height, blockchain.stateDB, cfg.KeepStateHistory)
 	if err != nil {
 		panic(err)
@@ -107,6 +136,7 @@ func (app *Blockchain) InitChain(req abciTypes.RequestInitChain) abciTypes.Respo
 	}
 
 	app.stateDeliver.Import(genesisState)
+	app.genesisAlloc = state.GenesisAlloc(genesisState)
 
 	totalPower := big.NewInt(0)
 	for _, val := range genesisState.Validators {
@@ -131,6 +161,7 @@ func (app *Blockchain) InitChain(req abciTypes.RequestInitChain) abciTypes.Respo
 
 	app.appDB.SetStartHeight(genesisState.StartHeight)
 	app.appDB.SaveValidators(vals)
+	app.appDB.SaveValidatorSet(genesisState.StartHeight+1, vals)
 	rewards.SetStartHeight(genesisState.StartHeight)
 	validators.SetStartHeight(genesisState.StartHeight)
 
@@ -147,12 +178,12 @@ func (app *Blockchain) BeginBlock(req abciTypes.RequestBeginBlock) abciTypes.Res
 	}
 
 	height := uint64(req.Header.Height)
+
+	// apply software upgrade or halt if running binary does not know it
+	app.applyUpgradePlan(height)
+
 	// Check invariants
-	if height%720 == 0 {
-		if err := state.NewForCheckFromDeliver(app.stateCheck).CheckForInvariants(); err != nil {
-			log.With("module", "invariants").Error("Invariants error", "msg", err.Error(), "height", app.height)
-		}
-	}
+	app.checkInvariants(height)
 
 	// compute max gas
 	app.updateBlocksTimeDelta(height, 3)
@@ -162,13 +193,19 @@ func (app *Blockchain) BeginBlock(req abciTypes.RequestBeginBlock) abciTypes.Res
 	atomic.StoreUint64(&app.height, height)
 	app.rewards = big.NewInt(0)
 
+	eventsdb.GetCurrent().SetBlockTime(height, req.Header.Time)
+
 	// clear absent candidates
 	app.validatorsStatuses = map[[20]byte]int8{}
 
 	// give penalty to absent validators
 	for _, v := range req.LastCommitInfo.Votes {
-		var address [20]byte
-		copy(address[:], v.Validator.Address)
+		var voter [20]byte
+		copy(voter[:], v.Validator.Address)
+
+		// tendermint keeps replaced public key in validator set for ValidatorUpdateDelay blocks, so votes
+		// may come from the retired key of candidate
+		address := app.stateDeliver.ResolveEvidenceAddress(voter)
 
 		if v.SignedLastBlock {
 			app.stateDeliver.SetValidatorPresent(address)
@@ -177,6 +214,14 @@ func (app *Blockchain) BeginBlock(req abciTypes.RequestBeginBlock) abciTypes.Res
 			app.stateDeliver.SetValidatorAbsent(address)
 			app.validatorsStatuses[address] = ValidatorAbsent
 		}
+
+		app.recordValidatorStats(address, v.SignedLastBlock)
+	}
+
+	if len(req.Header.ProposerAddress) != 0 {
+		var proposer [20]byte
+		copy(proposer[:], req.Header.ProposerAddress)
+		app.blockProposer = &proposer
 	}
 
 	// give penalty to Byzantine validators
@@ -184,14 +229,30 @@ func (app *Blockchain) BeginBlock(req abciTypes.RequestBeginBlock) abciTypes.Res
 		var address [20]byte
 		copy(address[:], byzVal.Validator.Address)
 
+		// evidence may be against public key which candidate has replaced since
+		candidateAddress := app.stateDeliver.ResolveEvidenceAddress(address)
+
 		// skip already offline candidates to prevent double punishing
-		candidate := app.stateDeliver.GetStateCandidateByTmAddress(address)
+		candidate := app.stateDeliver.GetStateCandidateByTmAddress(candidateAddress)
 		if candidate == nil || candidate.Status == state.CandidateStatusOffline {
 			continue
 		}
 
-		app.stateDeliver.PunishFrozenFundsWithAddress(height, height+state.UnbondPeriod, address)
-		app.stateDeliver.PunishByzantineValidator(address)
+		params := app.stateDeliver.GetParams()
+		evidenceHeight := uint64(byzVal.Height)
+
+		// skip evidences which are too old to be punished
+		if params.EvidenceMaxAge > 0 && height > evidenceHeight+params.EvidenceMaxAge {
+			log.Info("Evidence is too old", "address", fmt.Sprintf("%x", address), "height", evidenceHeight)
+			continue
+		}
+
+		// frozen funds and redelegations of candidate are moved to its new key on replacement
+		app.stateDeliver.PunishFrozenFundsWithAddress(height, height+params.UnbondPeriod, candidateAddress,
+			evidenceHeight)
+		app.stateDeliver.PunishRedelegationsWithAddress(height, height+params.UnbondPeriod, candidateAddress,
+			evidenceHeight)
+		app.stateDeliver.PunishByzantineValidator(candidateAddress, evidenceHeight)
 	}
 
 	// apply frozen funds (used for unbond stakes)
@@ -211,6 +272,14 @@ func (app *Blockchain) BeginBlock(req abciTypes.RequestBeginBlock) abciTypes.Res
 		frozenFunds.Delete()
 	}
 
+	// redelegated stakes are no longer slashable for source candidate's misbehaviour
+	if redelegations := app.stateDeliver.GetStateRedelegations(height); redelegations != nil {
+		redelegations.Delete()
+	}
+
+	// apply candidates' commission changes which notice period is over
+	app.stateDeliver.ApplyCandidateCommissionChanges(height)
+
 	return abciTypes.ResponseBeginBlock{}
 }
 
@@ -282,8 +351,14 @@ func (app *Blockchain) EndBlock(req abciTypes.RequestEndBlock) abciTypes.Respons
 		app.stateDeliver.PayRewards()
 	}
 
+	// finish governance proposals which voting period is over
+	app.stateDeliver.TallyProposals(height)
+
 	// update validators
-	if req.Height%120 == 0 || hasDroppedValidators {
+	if height%app.stateDeliver.GetParams().ValidatorsUpdatePeriod == 0 || hasDroppedValidators {
+		// replaced keys leave validator set and new ones enter it within this update
+		app.stateDeliver.ApplyCandidatePubKeyChanges()
+
 		app.stateDeliver.RecalculateTotalStakeValues()
 
 		app.stateDeliver.ClearCandidates()
@@ -333,6 +408,7 @@ func (app *Blockchain) EndBlock(req abciTypes.RequestEndBlock) abciTypes.Respons
 		activeValidators := app.getCurrentValidators()
 
 		app.saveCurrentValidators(newValidators)
+		app.saveValidatorSetChanges(height, activeValidators, newValidators)
 
 		updates = newValidators
 
@@ -398,7 +474,12 @@ func (app *Blockchain) DeliverTx(req abciTypes.RequestDeliverTx) abciTypes.Respo
 
 // Validate a tx for the mempool
 func (app *Blockchain) CheckTx(req abciTypes.RequestCheckTx) abciTypes.ResponseCheckTx {
-	response := transaction.RunTx(app.stateCheck, true, req.Tx, nil, app.height, app.currentMempool, app.MinGasPrice())
+	// prevalidated transactions are not decoded again and their signers are cached
+	tx, response := transaction.CheckStateless(req.Tx)
+	if response.Code == code.OK {
+		response = transaction.RunDecodedTx(app.stateCheck, true, req.Tx, tx, nil, app.height, app.currentMempool,
+			app.MinGasPrice())
+	}
 
 	return abciTypes.ResponseCheckTx{
 		Code:      response.Code,
@@ -416,6 +497,12 @@ func (app *Blockchain) CheckTx(req abciTypes.RequestCheckTx) abciTypes.ResponseC
 	}
 }
 
+// PrevalidateTxs runs stateless checks of transactions concurrently. Signers of valid transactions are cached, so
+// following CheckTx and DeliverTx of them don't recover signatures again, and CheckTx takes them decoded.
+func (app *Blockchain) PrevalidateTxs(txs [][]byte) []transaction.Response {
+	return app.prevalidator.CheckAll(txs)
+}
+
 // Commit the state and return the application Merkle root hash
 func (app *Blockchain) Commit() abciTypes.ResponseCommit {
 	// Committing Minter Blockchain state
@@ -430,6 +517,10 @@ func (app *Blockchain) Commit() abciTypes.ResponseCommit {
 	// Persist application hash and height
 	app.appDB.SetLastBlockHash(hash)
 	app.appDB.SetLastHeight(app.height)
+	app.saveValidatorStats(app.height)
+
+	// Start snapshot of committed state
+	app.createSnapshot(app.height, hash)
 
 	// Resetting check state to be consistent with current height
 	app.resetCheckState()
@@ -0,0 +1,128 @@
+package minter
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/cmd/utils"
+	"github.com/MinterTeam/minter-go-node/core/snapshot"
+	"github.com/MinterTeam/minter-go-node/log"
+	"github.com/tendermint/tendermint/libs/common"
+	tmNode "github.com/tendermint/tendermint/node"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// snapshotWaitTimeout limits waiting for Tendermint to save its state of snapshot's height
+const snapshotWaitTimeout = time.Minute
+
+// SnapshotsDir returns directory node saves its snapshots to
+func SnapshotsDir() string {
+	return utils.GetMinterHome() + "/data/snapshots"
+}
+
+// SnapshotPath returns path of snapshot of given height
+func SnapshotPath(dir string, height uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("snapshot-%d.bin", height))
+}
+
+// createSnapshot starts writing snapshot of just committed height in background
+func (app *Blockchain) createSnapshot(height uint64, hash []byte) {
+	if app.snapshotInterval == 0 || height%app.snapshotInterval != 0 || app.tmNode == nil {
+		return
+	}
+
+	// Tendermint's consensus state is not updated while fast syncing
+	if app.tmNode.ConsensusReactor().FastSync() {
+		return
+	}
+
+	logger := log.With("module", "snapshot", "height", height)
+	if !atomic.CompareAndSwapUint32(&app.snapshotInProgress, 0, 1) {
+		logger.Error("Previous snapshot is still in progress, skipping")
+		return
+	}
+
+	if err := common.EnsureDir(SnapshotsDir(), 0777); err != nil {
+		logger.Error("Failed to create snapshots dir", "err", err)
+		atomic.StoreUint32(&app.snapshotInProgress, 0)
+		return
+	}
+
+	// iterators see databases as of this height even while next blocks are processed
+	stateIt := app.stateDB.Iterator(nil, nil)
+	appIt := app.appDB.DB().Iterator(nil, nil)
+	node := app.tmNode
+
+	app.wg.Add(1)
+	go func() {
+		defer app.wg.Done()
+		defer atomic.StoreUint32(&app.snapshotInProgress, 0)
+
+		err := snapshot.Write(SnapshotPath(SnapshotsDir(), height), height, hash, stateIt, appIt,
+			func() (snapshot.TendermintData, error) {
+				return waitTendermintData(node, height)
+			})
+		if err != nil {
+			logger.Error("Failed to create snapshot", "err", err)
+			return
+		}
+
+		logger.Info("Snapshot created")
+		pruneSnapshots(SnapshotsDir(), app.snapshotKeepRecent)
+	}()
+}
+
+// waitTendermintData waits until Tendermint saves its state after given height. Tendermint does it right
+// after the block is committed by application and keeps the state until the next block is committed.
+func waitTendermintData(node *tmNode.Node, height uint64) (snapshot.TendermintData, error) {
+	deadline := time.Now().Add(snapshotWaitTimeout)
+
+	for {
+		tmState := node.ConsensusState().GetState()
+
+		switch {
+		case tmState.LastBlockHeight == int64(height):
+			return snapshot.TendermintData{
+				State:      tmState,
+				Block:      node.BlockStore().LoadBlock(int64(height)),
+				SeenCommit: node.BlockStore().LoadSeenCommit(int64(height)),
+			}, nil
+		case tmState.LastBlockHeight > int64(height):
+			return snapshot.TendermintData{}, fmt.Errorf("tendermint is already at height %d", tmState.LastBlockHeight)
+		}
+
+		if time.Now().After(deadline) {
+			return snapshot.TendermintData{}, fmt.Errorf("tendermint has not reached height %d", height)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// pruneSnapshots removes all snapshots in dir except keep most recent ones
+func pruneSnapshots(dir string, keep int) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var heights []uint64
+	for _, file := range files {
+		var height uint64
+		if _, err := fmt.Sscanf(file.Name(), "snapshot-%d.bin", &height); err == nil &&
+			file.Name() == filepath.Base(SnapshotPath(dir, height)) {
+			heights = append(heights, height)
+		}
+	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+
+	for i := keep; i < len(heights); i++ {
+		_ = os.Remove(SnapshotPath(dir, heights[i]))
+	}
+}
@@ -0,0 +1,30 @@
+package minter
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/state"
+)
+
+// Names of software upgrades known by this release. Upgrade is applied at the height
+// of a plan with the same name approved by governance.
+const (
+	UpgradeSplitCandidatesStorage = "split-candidates-storage"
+	UpgradeFrozenFundsIndex       = "frozen-funds-index"
+	UpgradeStakesIndex            = "stakes-index"
+)
+
+func init() {
+	RegisterUpgradeHandler(UpgradeSplitCandidatesStorage, func(app *Blockchain, plan state.UpgradePlan) error {
+		app.stateDeliver.MigrateCandidatesStorage()
+		return nil
+	})
+
+	RegisterUpgradeHandler(UpgradeFrozenFundsIndex, func(app *Blockchain, plan state.UpgradePlan) error {
+		app.stateDeliver.MigrateFrozenFundsIndex()
+		return nil
+	})
+
+	RegisterUpgradeHandler(UpgradeStakesIndex, func(app *Blockchain, plan state.UpgradePlan) error {
+		app.stateDeliver.MigrateStakesIndex()
+		return nil
+	})
+}
@@ -0,0 +1,85 @@
+package minter
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/log"
+	"os"
+)
+
+// haltExitCode is an exit code of the node halted by upgrade or violated invariants
+const haltExitCode = 2
+
+// UpgradeHandler performs state migrations of software upgrade. It is called once at the height of upgrade plan
+// before any other processing of the block.
+type UpgradeHandler func(app *Blockchain, plan state.UpgradePlan) error
+
+var upgradeHandlers = map[string]UpgradeHandler{}
+
+// RegisterUpgradeHandler adds handler of software upgrade with given name. Should be called from init functions
+// of the release which implements the upgrade.
+func RegisterUpgradeHandler(name string, handler UpgradeHandler) {
+	if _, exists := upgradeHandlers[name]; exists {
+		panic(fmt.Sprintf("Upgrade handler %s is already registered", name))
+	}
+
+	upgradeHandlers[name] = handler
+}
+
+// HasUpgradeHandler checks if running binary knows software upgrade with given name
+func HasUpgradeHandler(name string) bool {
+	_, exists := upgradeHandlers[name]
+	return exists
+}
+
+// applyUpgradePlan runs handler of software upgrade scheduled at given height. If running binary does not know
+// the upgrade, the node halts. It halts again on every start until it is restarted with a new version.
+func (app *Blockchain) applyUpgradePlan(height uint64) {
+	plan := app.stateDeliver.GetUpgradePlan()
+	if plan == nil || plan.Height != height {
+		return
+	}
+
+	handler, exists := upgradeHandlers[plan.Name]
+	if !exists {
+		app.haltForUpgrade(*plan)
+		return
+	}
+
+	if err := handler(app, *plan); err != nil {
+		panic(fmt.Sprintf("Upgrade %s failed: %s", plan.Name, err))
+	}
+
+	app.stateDeliver.ApplyUpgradePlan(height)
+	log.Info("Upgrade applied", "name", plan.Name, "height", height)
+}
+
+// haltForUpgrade stops the node before processing the block of upgrade
+func (app *Blockchain) haltForUpgrade(plan state.UpgradePlan) {
+	log.Error(fmt.Sprintf("UPGRADE \"%s\" NEEDED at height %d. Please install a new version of Minter node and restart it",
+		plan.Name, plan.Height), "info", plan.Info)
+
+	app.halt()
+}
+
+// halt closes databases and exits the process. Should be called from BeginBlock: nothing of the block is persisted
+// yet, so Tendermint replays the block after restart. Blocking here instead would hold the ABCI connection and
+// stall consensus, mempool and queries of the node.
+func (app *Blockchain) halt() {
+	app.wg.Done()
+	app.Stop()
+
+	os.Exit(haltExitCode)
+}
+
+// UpgradeRequired returns approved software upgrade which is unknown to running binary or nil if there is no
+// such upgrade. It is reported as soon as the upgrade is scheduled, so operators have time to install a new version
+// before the node halts at the height of the upgrade.
+func (app *Blockchain) UpgradeRequired() *state.UpgradePlan {
+	plan := app.CurrentState().GetUpgradePlan()
+	if plan == nil || plan.Height <= app.LastCommittedHeight() || HasUpgradeHandler(plan.Name) {
+		return nil
+	}
+
+	return plan
+}
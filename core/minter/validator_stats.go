@@ -0,0 +1,148 @@
+package minter
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ValidatorWindowStats holds signing statistics of a validator over a window of last blocks. Blocks is a number of
+// blocks actually counted, it is less than Window if the chain is younger than the window.
+type ValidatorWindowStats struct {
+	Window   uint64
+	Blocks   uint64
+	Signed   uint64
+	Missed   uint64
+	Proposed uint64
+}
+
+// parseValidatorStatsWindows parses comma separated list of windows. Windows are rounded up to the size of stats bucket.
+func parseValidatorStatsWindows(value string) ([]uint64, error) {
+	var windows []uint64
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		window, err := strconv.ParseUint(item, 10, 64)
+		if err != nil || window == 0 {
+			return nil, fmt.Errorf("invalid validator stats window %q", item)
+		}
+
+		if window%appdb.ValidatorStatsBucketSize != 0 {
+			window += appdb.ValidatorStatsBucketSize - window%appdb.ValidatorStatsBucketSize
+		}
+
+		windows = append(windows, window)
+	}
+
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i] < windows[j]
+	})
+
+	return windows, nil
+}
+
+// recordValidatorStats counts signed and missed blocks of validators by signatures in current block
+func (app *Blockchain) recordValidatorStats(address [20]byte, signed bool) {
+	stats, ok := app.validatorStats[address]
+	if !ok {
+		stats = &appdb.ValidatorStats{}
+		app.validatorStats[address] = stats
+	}
+
+	if signed {
+		stats.Signed++
+	} else {
+		stats.Missed++
+	}
+}
+
+// saveValidatorStats persists statistics of current block and removes buckets which are out of the largest window.
+// Signatures are counted to the previous block, which they were made for, while proposer is counted to current
+// block. Blocks which statistics are already saved, e.g. replayed after restart, are skipped.
+func (app *Blockchain) saveValidatorStats(height uint64) {
+	signatures, proposer := app.validatorStats, app.blockProposer
+	app.validatorStats = map[[20]byte]*appdb.ValidatorStats{}
+	app.blockProposer = nil
+
+	if height <= app.appDB.GetValidatorStatsHeight() {
+		return
+	}
+
+	signaturesBucket := (height - 1) / appdb.ValidatorStatsBucketSize
+	for address, stats := range signatures {
+		app.addValidatorStats(address, signaturesBucket, *stats)
+	}
+
+	bucket := height / appdb.ValidatorStatsBucketSize
+	if proposer != nil {
+		app.addValidatorStats(*proposer, bucket, appdb.ValidatorStats{Proposed: 1})
+	}
+
+	app.appDB.SetValidatorStatsHeight(height)
+
+	if height%appdb.ValidatorStatsBucketSize != 0 || len(app.validatorStatsWindows) == 0 {
+		return
+	}
+
+	maxBuckets := app.validatorStatsWindows[len(app.validatorStatsWindows)-1] / appdb.ValidatorStatsBucketSize
+	if bucket > maxBuckets {
+		app.appDB.PruneValidatorStats(bucket - maxBuckets)
+	}
+}
+
+func (app *Blockchain) addValidatorStats(address [20]byte, bucket uint64, stats appdb.ValidatorStats) {
+	saved := app.appDB.GetValidatorStats(address, bucket)
+	saved.Add(stats)
+	app.appDB.SetValidatorStats(address, bucket, saved)
+}
+
+// GetValidatorStats returns signing statistics of validator with given tendermint address over configured windows.
+// Statistics are aggregated by buckets, so windows are counted by whole buckets back from the last completed one.
+// Blocks of the bucket which is being filled are not counted yet.
+func (app *Blockchain) GetValidatorStats(address [20]byte) []ValidatorWindowStats {
+	app.lock.RLock()
+	defer app.lock.RUnlock()
+
+	return getValidatorStats(app.appDB, app.validatorStatsWindows, app.LastCommittedHeight(), address)
+}
+
+func getValidatorStats(appDB *appdb.AppDB, windows []uint64, height uint64, address [20]byte) []ValidatorWindowStats {
+	// signatures of the last committed block are counted with the next one, so bucket is completed when
+	// its last block is followed by a committed one
+	completed := height / appdb.ValidatorStatsBucketSize
+
+	result := make([]ValidatorWindowStats, len(windows))
+
+	var total appdb.ValidatorStats
+	var blocks uint64
+	counted := uint64(0)
+	for i, window := range windows {
+		// windows are sorted, so each one extends the previous
+		for counted < completed && counted < window/appdb.ValidatorStatsBucketSize {
+			bucket := completed - 1 - counted
+			total.Add(appDB.GetValidatorStats(address, bucket))
+			counted++
+
+			blocks += appdb.ValidatorStatsBucketSize
+			if bucket == 0 {
+				// there is no block at height 0
+				blocks--
+			}
+		}
+
+		result[i] = ValidatorWindowStats{
+			Window:   window,
+			Blocks:   blocks,
+			Signed:   total.Signed,
+			Missed:   total.Missed,
+			Proposed: total.Proposed,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,89 @@
+package minter
+
+import (
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"github.com/MinterTeam/minter-go-node/storage"
+	"testing"
+)
+
+func TestParseValidatorStatsWindows(t *testing.T) {
+	windows, err := parseValidatorStatsWindows("100000, 1000,1500")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []uint64{1000, 2000, 100000}
+	if len(windows) != len(expected) {
+		t.Fatalf("Windows are not correct. Expected %v, got %v", expected, windows)
+	}
+
+	for i := range expected {
+		if windows[i] != expected[i] {
+			t.Fatalf("Windows are not correct. Expected %v, got %v", expected, windows)
+		}
+	}
+
+	if _, err := parseValidatorStatsWindows("1000,abc"); err == nil {
+		t.Fatalf("Invalid window should not be parsed")
+	}
+}
+
+func TestSaveValidatorStats(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AppDBBackend = storage.MemoryBackend
+
+	app := &Blockchain{
+		appDB:          appdb.NewAppDB(cfg),
+		validatorStats: map[[20]byte]*appdb.ValidatorStats{},
+	}
+
+	signer, proposer := [20]byte{1}, [20]byte{2}
+	height := uint64(appdb.ValidatorStatsBucketSize)
+
+	for i := 0; i < 2; i++ {
+		// the second run replays the same block
+		app.recordValidatorStats(signer, true)
+		app.blockProposer = &proposer
+		app.saveValidatorStats(height)
+	}
+
+	if stats := app.appDB.GetValidatorStats(signer, 0); stats.Signed != 1 {
+		t.Fatalf("Signature should be counted once to the previous block, got %d", stats.Signed)
+	}
+
+	if stats := app.appDB.GetValidatorStats(proposer, 1); stats.Proposed != 1 {
+		t.Fatalf("Proposed block should be counted once to current block, got %d", stats.Proposed)
+	}
+
+	if app.appDB.GetValidatorStatsHeight() != height {
+		t.Fatalf("Validator stats height should be %d, got %d", height, app.appDB.GetValidatorStatsHeight())
+	}
+}
+
+func TestGetValidatorStats(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AppDBBackend = storage.MemoryBackend
+	appDB := appdb.NewAppDB(cfg)
+
+	address := [20]byte{1}
+	for bucket := uint64(0); bucket < 3; bucket++ {
+		appDB.SetValidatorStats(address, bucket, appdb.ValidatorStats{Signed: bucket + 1})
+	}
+
+	windows := []uint64{appdb.ValidatorStatsBucketSize, 10 * appdb.ValidatorStatsBucketSize}
+
+	// the third bucket is being filled, so windows start from the second one
+	stats := getValidatorStats(appDB, windows, 2*appdb.ValidatorStatsBucketSize+10, address)
+	if stats[0].Signed != 2 || stats[0].Blocks != appdb.ValidatorStatsBucketSize {
+		t.Fatalf("The first window should count the last completed bucket only, got %+v", stats[0])
+	}
+
+	if stats[1].Signed != 3 || stats[1].Blocks != 2*appdb.ValidatorStatsBucketSize-1 {
+		t.Fatalf("The second window should count all completed buckets, got %+v", stats[1])
+	}
+
+	if stats := getValidatorStats(appDB, windows, appdb.ValidatorStatsBucketSize-1, address); stats[0].Blocks != 0 {
+		t.Fatalf("Window should be empty until the first bucket is completed, got %+v", stats[0])
+	}
+}
@@ -0,0 +1,113 @@
+package minter
+
+import (
+	"bytes"
+	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/eventsdb/events"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+)
+
+// ValidatorUpdateDelay is a number of blocks after which validator set returned in EndBlock becomes active in consensus
+const ValidatorUpdateDelay = 2
+
+// ValidatorPowerChange is a change of validator's voting power between two validator sets.
+// OldPower is 0 for added validators and NewPower is 0 for removed ones.
+type ValidatorPowerChange struct {
+	PubKey   types.Pubkey
+	OldPower int64
+	NewPower int64
+}
+
+// diffValidators returns changes of voting powers between old and new validator sets
+func diffValidators(oldVals, newVals abciTypes.ValidatorUpdates) []ValidatorPowerChange {
+	var changes []ValidatorPowerChange
+
+	for _, newVal := range newVals {
+		oldPower := int64(0)
+		for _, oldVal := range oldVals {
+			if bytes.Equal(oldVal.PubKey.Data, newVal.PubKey.Data) {
+				oldPower = oldVal.Power
+				break
+			}
+		}
+
+		if oldPower != newVal.Power {
+			changes = append(changes, ValidatorPowerChange{
+				PubKey:   newVal.PubKey.Data,
+				OldPower: oldPower,
+				NewPower: newVal.Power,
+			})
+		}
+	}
+
+	for _, oldVal := range oldVals {
+		persisted := false
+		for _, newVal := range newVals {
+			if bytes.Equal(oldVal.PubKey.Data, newVal.PubKey.Data) {
+				persisted = true
+				break
+			}
+		}
+
+		if !persisted {
+			changes = append(changes, ValidatorPowerChange{
+				PubKey:   oldVal.PubKey.Data,
+				OldPower: oldVal.Power,
+				NewPower: 0,
+			})
+		}
+	}
+
+	return changes
+}
+
+// chainHeight converts height of a Tendermint block to height of the chain, which continues from StartHeight of
+// genesis. History of validator sets is keyed by chain heights, so the genesis set is recorded at StartHeight+1.
+func (app *Blockchain) chainHeight(height uint64) uint64 {
+	return app.appDB.GetStartHeight() + height
+}
+
+// saveValidatorSetChanges records new validator set to history and emits events on its changes.
+// Validator set which is chosen at given height becomes active in consensus ValidatorUpdateDelay blocks later.
+func (app *Blockchain) saveValidatorSetChanges(height uint64, oldVals, newVals abciTypes.ValidatorUpdates) {
+	changes := diffValidators(oldVals, newVals)
+	if len(changes) == 0 {
+		return
+	}
+
+	activeSince := height + ValidatorUpdateDelay
+	app.appDB.SaveValidatorSet(app.chainHeight(activeSince), newVals)
+
+	edb := eventsdb.GetCurrent()
+	for _, change := range changes {
+		edb.AddEvent(height, events.ValidatorPowerChangeEvent{
+			ValidatorPubKey: change.PubKey,
+			OldPower:        change.OldPower,
+			NewPower:        change.NewPower,
+			ActiveSince:     activeSince,
+		})
+	}
+}
+
+// GetValidatorSet returns validator set which is active in consensus at given height along with its changes
+// in comparison with previous set. Returns nil if history of validator sets does not cover given height.
+func (app *Blockchain) GetValidatorSet(height uint64) (*appdb.ValidatorSet, []ValidatorPowerChange) {
+	startHeight := app.appDB.GetStartHeight()
+
+	set := app.appDB.GetValidatorSet(startHeight + height)
+	if set == nil || set.Height <= startHeight {
+		return nil, nil
+	}
+
+	var prevVals abciTypes.ValidatorUpdates
+	if prevSet := app.appDB.GetValidatorSet(set.Height - 1); prevSet != nil {
+		prevVals = prevSet.Validators
+	}
+
+	return &appdb.ValidatorSet{
+		Height:     set.Height - startHeight,
+		Validators: set.Validators,
+	}, diffValidators(prevVals, set.Validators)
+}
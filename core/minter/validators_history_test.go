@@ -0,0 +1,95 @@
+package minter
+
+import (
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"github.com/MinterTeam/minter-go-node/storage"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+	"testing"
+)
+
+func TestDiffValidators(t *testing.T) {
+	oldVals := abciTypes.ValidatorUpdates{
+		abciTypes.Ed25519ValidatorUpdate([]byte{1}, 50),
+		abciTypes.Ed25519ValidatorUpdate([]byte{2}, 30),
+		abciTypes.Ed25519ValidatorUpdate([]byte{3}, 20),
+	}
+
+	newVals := abciTypes.ValidatorUpdates{
+		abciTypes.Ed25519ValidatorUpdate([]byte{1}, 50),
+		abciTypes.Ed25519ValidatorUpdate([]byte{2}, 40),
+		abciTypes.Ed25519ValidatorUpdate([]byte{4}, 10),
+	}
+
+	expected := []ValidatorPowerChange{
+		{PubKey: []byte{2}, OldPower: 30, NewPower: 40},
+		{PubKey: []byte{4}, OldPower: 0, NewPower: 10},
+		{PubKey: []byte{3}, OldPower: 20, NewPower: 0},
+	}
+
+	changes := diffValidators(oldVals, newVals)
+	if len(changes) != len(expected) {
+		t.Fatalf("Changes are not correct. Expected %v, got %v", expected, changes)
+	}
+
+	for i := range expected {
+		if changes[i].PubKey[0] != expected[i].PubKey[0] || changes[i].OldPower != expected[i].OldPower || changes[i].NewPower != expected[i].NewPower {
+			t.Fatalf("Changes are not correct. Expected %v, got %v", expected, changes)
+		}
+	}
+
+	if changes := diffValidators(newVals, newVals); len(changes) != 0 {
+		t.Fatalf("Same validator sets should not have changes, got %v", changes)
+	}
+}
+
+func TestGetValidatorSetWithStartHeight(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.AppDBBackend = storage.MemoryBackend
+
+	app := &Blockchain{
+		appDB: appdb.NewAppDB(cfg),
+	}
+
+	const startHeight = 1000
+	genesisVals := abciTypes.ValidatorUpdates{abciTypes.Ed25519ValidatorUpdate([]byte{1}, 100)}
+	newVals := abciTypes.ValidatorUpdates{
+		abciTypes.Ed25519ValidatorUpdate([]byte{1}, 60),
+		abciTypes.Ed25519ValidatorUpdate([]byte{2}, 40),
+	}
+
+	app.appDB.SetStartHeight(startHeight)
+	app.appDB.SaveValidatorSet(startHeight+1, genesisVals)
+	app.saveValidatorSetChanges(10, genesisVals, newVals)
+
+	if set := app.appDB.GetValidatorSet(startHeight + 1); set == nil || set.Height != startHeight+1 {
+		t.Fatalf("Genesis validator set should be keyed by first height of the chain, got %v", set)
+	}
+
+	cases := []struct {
+		height      uint64
+		activeSince uint64
+		validators  int
+		changes     int
+	}{
+		{height: 1, activeSince: 1, validators: 1, changes: 1},
+		{height: 11, activeSince: 1, validators: 1, changes: 1},
+		{height: 12, activeSince: 12, validators: 2, changes: 2},
+		{height: 100, activeSince: 12, validators: 2, changes: 2},
+	}
+
+	for _, c := range cases {
+		set, changes := app.GetValidatorSet(c.height)
+		if set == nil {
+			t.Fatalf("Validator set at height %d is not found", c.height)
+		}
+
+		if set.Height != c.activeSince || len(set.Validators) != c.validators || len(changes) != c.changes {
+			t.Fatalf("Validator set at height %d is not correct, got %v with changes %v", c.height, set, changes)
+		}
+	}
+
+	if set, _ := app.GetValidatorSet(0); set != nil {
+		t.Fatalf("Validator set should not be found below first height of the chain, got %v", set)
+	}
+}
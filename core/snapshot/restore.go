@@ -0,0 +1,175 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/danil-lashin/iavl"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+	dbm "github.com/tendermint/tendermint/libs/db"
+)
+
+// proofBatchSize is a number of state records proved against application hash at once
+const proofBatchSize = 1000
+
+// Databases are databases snapshot is restored to. All of them should be empty.
+type Databases struct {
+	State            dbm.DB
+	App              dbm.DB
+	TendermintState  dbm.DB
+	TendermintBlocks dbm.DB
+
+	// ChainID is expected chain id of snapshot
+	ChainID string
+}
+
+// Restore applies snapshot to empty databases. Snapshot is accepted only if its application hash is equal
+// to trustedHash, all its chunks match their hashes and every record of restored state tree is proved
+// against trustedHash. Records of application database and Tendermint data are not covered by the hash, so
+// they are checked against the verified state: height and hash of application database should be the trusted
+// ones, current validators should be validators of the state, Tendermint's next validator set should consist
+// of them and the block of snapshot's height should be committed by validators its header refers to.
+func Restore(r *Reader, trustedHash []byte, dbs Databases) error {
+	meta := r.Metadata()
+	if !bytes.Equal(meta.AppHash, trustedHash) {
+		return fmt.Errorf("snapshot app hash %X does not match trusted hash %X", meta.AppHash, trustedHash)
+	}
+
+	// application database marks node as initialized, so it is written only after verification
+	var appItems []Item
+	var tmData TendermintData
+	err := r.Items(func(item Item) error {
+		switch item.Store {
+		case StoreState:
+			dbs.State.Set(item.Key, item.Value)
+		case StoreApp:
+			appItems = append(appItems, item)
+		case StoreTendermint:
+			return decodeTendermintItem(&tmData, item)
+		default:
+			return fmt.Errorf("unknown store %d", item.Store)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := verifyStateTree(dbs.State, meta.Height, trustedHash); err != nil {
+		return err
+	}
+
+	validators, err := verifyAppItems(appItems, dbs.State, meta.Height, trustedHash)
+	if err != nil {
+		return err
+	}
+
+	if tmData.State.LastBlockHeight != int64(meta.Height) || !bytes.Equal(tmData.State.AppHash, trustedHash) {
+		return fmt.Errorf("tendermint state does not match snapshot")
+	}
+
+	if tmData.State.ChainID != dbs.ChainID {
+		return fmt.Errorf("snapshot is made for chain %s, expected %s", tmData.State.ChainID, dbs.ChainID)
+	}
+
+	if err := verifyTendermintData(tmData, validators); err != nil {
+		return err
+	}
+
+	if err := restoreTendermint(tmData, dbs.TendermintState, dbs.TendermintBlocks); err != nil {
+		return err
+	}
+
+	for _, item := range appItems {
+		dbs.App.Set(item.Key, item.Value)
+	}
+
+	return nil
+}
+
+// verifyAppItems checks records of application database against verified state and returns current
+// validators stored in them
+func verifyAppItems(items []Item, stateDB dbm.DB, height uint64, trustedHash []byte) (
+	validators abciTypes.ValidatorUpdates, err error) {
+	// application database and state panic on malformed records
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid application data: %v", r)
+		}
+	}()
+
+	app := appdb.NewAppDBFromDB(dbm.NewMemDB())
+	for _, item := range items {
+		app.DB().Set(item.Key, item.Value)
+	}
+
+	if app.GetLastHeight() != height || !bytes.Equal(app.GetLastBlockHash(), trustedHash) {
+		return nil, fmt.Errorf("application data does not match snapshot")
+	}
+
+	stateDeliver, err := state.New(height, stateDB, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var stateValidators state.Validators
+	if vals := stateDeliver.GetStateValidators(); vals != nil {
+		stateValidators = vals.Data()
+	}
+
+	validators = app.GetValidators()
+	if len(validators) != len(stateValidators) {
+		return nil, fmt.Errorf("application has %d validators, state has %d", len(validators),
+			len(stateValidators))
+	}
+
+	for i, validator := range validators {
+		if !bytes.Equal(validator.PubKey.Data, stateValidators[i].PubKey) || validator.Power <= 0 {
+			return nil, fmt.Errorf("validator %X of application does not match state", validator.PubKey.Data)
+		}
+	}
+
+	return validators, nil
+}
+
+// verifyStateTree loads state tree of given height and checks range proofs of all its records
+func verifyStateTree(db dbm.DB, height uint64, trustedHash []byte) error {
+	tree := iavl.NewMutableTree(db, 1024)
+	if _, err := tree.LoadVersion(int64(height)); err != nil {
+		return fmt.Errorf("can't load state of height %d: %v", height, err)
+	}
+
+	if !bytes.Equal(tree.Hash(), trustedHash) {
+		return fmt.Errorf("state hash %X does not match trusted hash %X", tree.Hash(), trustedHash)
+	}
+
+	var start []byte
+	for {
+		keys, values, proof, err := tree.GetRangeWithProof(start, nil, proofBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(keys) == 0 {
+			return nil
+		}
+
+		if err := proof.Verify(trustedHash); err != nil {
+			return fmt.Errorf("invalid state proof: %v", err)
+		}
+
+		for i := range keys {
+			if err := proof.VerifyItem(keys[i], values[i]); err != nil {
+				return fmt.Errorf("invalid state proof of key %X: %v", keys[i], err)
+			}
+		}
+
+		if len(keys) < proofBatchSize {
+			return nil
+		}
+
+		start = append(append([]byte{}, keys[len(keys)-1]...), 0)
+	}
+}
@@ -0,0 +1,332 @@
+// Package snapshot implements snapshots of node's state which allow new nodes to start from
+// a recent height instead of replaying all blocks from genesis.
+//
+// Snapshot is a single file which consists of chunks followed by metadata:
+//
+//	magic | chunk 0 | chunk 1 | ... | metadata | metadata length (8 bytes)
+//
+// Each chunk is an RLP encoded list of items of up to chunkSize bytes. Metadata holds height and
+// application hash of the snapshot together with offsets and SHA-256 hashes of all chunks, so each
+// chunk is verified before it is applied.
+package snapshot
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	"io"
+	"os"
+)
+
+const (
+	// Format is a version of snapshot file format
+	Format uint = 1
+
+	chunkSize = 4 << 20
+)
+
+// Stores which snapshot items belong to
+const (
+	StoreState      byte = 1 // raw records of IAVL state database
+	StoreApp        byte = 2 // raw records of application database
+	StoreTendermint byte = 3 // Tendermint's state, last block and its commit
+)
+
+var magic = []byte("MNTSNAP1")
+
+// Item is a single key-value record of a store
+type Item struct {
+	Store byte
+	Key   []byte
+	Value []byte
+}
+
+// ChunkInfo describes position and hash of a chunk in snapshot file
+type ChunkInfo struct {
+	Offset uint64
+	Size   uint64
+	Hash   []byte
+}
+
+// Metadata describes snapshot
+type Metadata struct {
+	Format  uint
+	Height  uint64
+	AppHash []byte
+	Chunks  []ChunkInfo
+}
+
+// Writer writes snapshot file. File becomes visible under its name only after successful Close.
+type Writer struct {
+	path   string
+	file   *os.File
+	offset uint64
+	items  []Item
+	size   int
+	meta   Metadata
+}
+
+// Create starts writing snapshot of given height to path
+func Create(path string, height uint64, appHash []byte) (*Writer, error) {
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := file.Write(magic); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &Writer{
+		path:   path,
+		file:   file,
+		offset: uint64(len(magic)),
+		meta: Metadata{
+			Format:  Format,
+			Height:  height,
+			AppHash: appHash,
+		},
+	}, nil
+}
+
+// Add adds record of given store to snapshot
+func (w *Writer) Add(store byte, key []byte, value []byte) error {
+	w.items = append(w.items, Item{
+		Store: store,
+		Key:   append([]byte{}, key...),
+		Value: append([]byte{}, value...),
+	})
+	w.size += len(key) + len(value)
+
+	if w.size >= chunkSize {
+		return w.flush()
+	}
+
+	return nil
+}
+
+// Close writes remaining items and metadata and moves the file to its place
+func (w *Writer) Close() error {
+	if err := w.flush(); err != nil {
+		w.Abort()
+		return err
+	}
+
+	meta, err := rlp.EncodeToBytes(w.meta)
+	if err != nil {
+		w.Abort()
+		return err
+	}
+
+	length := make([]byte, 8)
+	binary.BigEndian.PutUint64(length, uint64(len(meta)))
+
+	if _, err := w.file.Write(append(meta, length...)); err != nil {
+		w.Abort()
+		return err
+	}
+
+	if err := w.file.Sync(); err != nil {
+		w.Abort()
+		return err
+	}
+
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.path + ".tmp")
+		return err
+	}
+
+	return os.Rename(w.path+".tmp", w.path)
+}
+
+// Abort removes partially written snapshot
+func (w *Writer) Abort() {
+	w.file.Close()
+	os.Remove(w.path + ".tmp")
+}
+
+func (w *Writer) flush() error {
+	if len(w.items) == 0 {
+		return nil
+	}
+
+	chunk, err := rlp.EncodeToBytes(w.items)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.file.Write(chunk); err != nil {
+		return err
+	}
+
+	hash := sha256.Sum256(chunk)
+	w.meta.Chunks = append(w.meta.Chunks, ChunkInfo{
+		Offset: w.offset,
+		Size:   uint64(len(chunk)),
+		Hash:   hash[:],
+	})
+
+	w.offset += uint64(len(chunk))
+	w.items = nil
+	w.size = 0
+
+	return nil
+}
+
+// Write creates snapshot of given height at path. Iterators should point to the state of databases at
+// this height, tendermint is called after databases are written and returns Tendermint's data at this height.
+func Write(path string, height uint64, appHash []byte, stateIt dbm.Iterator, appIt dbm.Iterator,
+	tendermint func() (TendermintData, error)) error {
+	defer stateIt.Close()
+	defer appIt.Close()
+
+	w, err := Create(path, height, appHash)
+	if err != nil {
+		return err
+	}
+
+	sources := []struct {
+		store byte
+		it    dbm.Iterator
+	}{
+		{StoreState, stateIt},
+		{StoreApp, appIt},
+	}
+
+	for _, source := range sources {
+		for ; source.it.Valid(); source.it.Next() {
+			if err := w.Add(source.store, source.it.Key(), source.it.Value()); err != nil {
+				w.Abort()
+				return err
+			}
+		}
+	}
+
+	data, err := tendermint()
+	if err != nil {
+		w.Abort()
+		return err
+	}
+
+	if err := w.AddTendermintData(data); err != nil {
+		w.Abort()
+		return err
+	}
+
+	return w.Close()
+}
+
+// Reader reads snapshot file
+type Reader struct {
+	file *os.File
+	meta Metadata
+}
+
+// Open opens snapshot file and reads its metadata
+func Open(path string) (*Reader, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := readMetadata(file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("can't read snapshot %s: %v", path, err)
+	}
+
+	return &Reader{
+		file: file,
+		meta: meta,
+	}, nil
+}
+
+// Metadata returns metadata of snapshot
+func (r *Reader) Metadata() Metadata {
+	return r.meta
+}
+
+// Items verifies chunks one by one and calls fn for each item of a verified chunk
+func (r *Reader) Items(fn func(item Item) error) error {
+	for i, info := range r.meta.Chunks {
+		chunk := make([]byte, info.Size)
+		if _, err := r.file.ReadAt(chunk, int64(info.Offset)); err != nil {
+			return fmt.Errorf("can't read chunk %d: %v", i, err)
+		}
+
+		if hash := sha256.Sum256(chunk); !bytes.Equal(hash[:], info.Hash) {
+			return fmt.Errorf("hash of chunk %d does not match: expected %X, got %X", i, info.Hash, hash)
+		}
+
+		var items []Item
+		if err := rlp.DecodeBytes(chunk, &items); err != nil {
+			return fmt.Errorf("can't decode chunk %d: %v", i, err)
+		}
+
+		for _, item := range items {
+			if err := fn(item); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close closes snapshot file
+func (r *Reader) Close() error {
+	return r.file.Close()
+}
+
+func readMetadata(file *os.File) (Metadata, error) {
+	var meta Metadata
+
+	header := make([]byte, len(magic))
+	if _, err := io.ReadFull(file, header); err != nil {
+		return meta, err
+	}
+
+	if !bytes.Equal(header, magic) {
+		return meta, errors.New("not a snapshot file")
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		return meta, err
+	}
+
+	size := stat.Size()
+	if size < int64(len(magic))+8 {
+		return meta, errors.New("snapshot file is truncated")
+	}
+
+	length := make([]byte, 8)
+	if _, err := file.ReadAt(length, size-8); err != nil {
+		return meta, err
+	}
+
+	metaSize := int64(binary.BigEndian.Uint64(length))
+	if metaSize > size-int64(len(magic))-8 {
+		return meta, errors.New("snapshot file is truncated")
+	}
+
+	enc := make([]byte, metaSize)
+	if _, err := file.ReadAt(enc, size-8-metaSize); err != nil {
+		return meta, err
+	}
+
+	if err := rlp.DecodeBytes(enc, &meta); err != nil {
+		return meta, err
+	}
+
+	if meta.Format != Format {
+		return meta, fmt.Errorf("unsupported snapshot format %d", meta.Format)
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,156 @@
+package snapshot
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/appdb"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/danil-lashin/iavl"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/db"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriterReader(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "snapshot-10.bin")
+	w, err := Create(path, 10, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		if err := w.Add(StoreState, []byte(fmt.Sprintf("key%d", i)), []byte{byte(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := r.Metadata()
+	if meta.Height != 10 || !bytes.Equal(meta.AppHash, []byte{1, 2, 3}) || len(meta.Chunks) != 1 {
+		t.Fatalf("Wrong metadata %v", meta)
+	}
+
+	count := 0
+	err = r.Items(func(item Item) error {
+		if item.Store != StoreState || !bytes.Equal(item.Key, []byte(fmt.Sprintf("key%d", count))) {
+			return fmt.Errorf("wrong item %d", count)
+		}
+		count++
+		return nil
+	})
+	if err != nil || count != 100 {
+		t.Fatalf("Items are not read: %v, got %d items", err, count)
+	}
+	r.Close()
+
+	// corrupt the chunk
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[meta.Chunks[0].Offset+10] ^= 0xff
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	if err := r.Items(func(item Item) error { return nil }); err == nil {
+		t.Fatal("Corrupted chunk should not be accepted")
+	}
+}
+
+func TestVerifyStateTree(t *testing.T) {
+	memDB := db.NewMemDB()
+	tree := iavl.NewMutableTree(memDB, 1024)
+	for i := 0; i < 2500; i++ {
+		tree.Set([]byte(fmt.Sprintf("key%d", i)), []byte(fmt.Sprintf("value%d", i)))
+	}
+
+	hash, version, err := tree.SaveVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifyStateTree(memDB, uint64(version), hash); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := verifyStateTree(memDB, uint64(version), []byte{1, 2, 3}); err == nil {
+		t.Fatal("State should not be accepted with wrong hash")
+	}
+}
+
+func TestVerifyAppItems(t *testing.T) {
+	stateDB := db.NewMemDB()
+	s, err := state.New(0, stateDB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubkey := make([]byte, 32)
+	pubkey[0] = 1
+	s.CreateValidator(types.Address{}, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	hash, version, err := s.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	makeItems := func(height uint64, hash []byte, vals abciTypes.ValidatorUpdates) []Item {
+		app := appdb.NewAppDBFromDB(db.NewMemDB())
+		app.SetLastHeight(height)
+		app.SetLastBlockHash(hash)
+		app.SaveValidators(vals)
+
+		var items []Item
+		it := app.DB().Iterator(nil, nil)
+		defer it.Close()
+		for ; it.Valid(); it.Next() {
+			items = append(items, Item{Store: StoreApp, Key: it.Key(), Value: it.Value()})
+		}
+
+		return items
+	}
+
+	height := uint64(version)
+	vals := abciTypes.ValidatorUpdates{abciTypes.Ed25519ValidatorUpdate(pubkey, 100)}
+	if _, err := verifyAppItems(makeItems(height, hash, vals), stateDB, height, hash); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := verifyAppItems(makeItems(height+1, hash, vals), stateDB, height, hash); err == nil {
+		t.Fatal("Application data of other height should not be accepted")
+	}
+
+	otherPubkey := make([]byte, 32)
+	otherPubkey[0] = 2
+	otherVals := abciTypes.ValidatorUpdates{abciTypes.Ed25519ValidatorUpdate(otherPubkey, 100)}
+	if _, err := verifyAppItems(makeItems(height, hash, otherVals), stateDB, height, hash); err == nil {
+		t.Fatal("Validators which are not in state should not be accepted")
+	}
+}
@@ -0,0 +1,161 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/tendermint/go-amino"
+	abciTypes "github.com/tendermint/tendermint/abci/types"
+	bc "github.com/tendermint/tendermint/blockchain"
+	dbm "github.com/tendermint/tendermint/libs/db"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+var cdc = amino.NewCodec()
+
+func init() {
+	types.RegisterBlockAmino(cdc)
+}
+
+// Keys of items of Tendermint store
+var (
+	tmStateKey      = []byte("state")
+	tmBlockKey      = []byte("block")
+	tmSeenCommitKey = []byte("seenCommit")
+)
+
+// TendermintData is Tendermint's data node needs to continue from snapshot's height
+type TendermintData struct {
+	State      sm.State
+	Block      *types.Block
+	SeenCommit *types.Commit
+}
+
+// AddTendermintData adds Tendermint's state after snapshot's height, block at this height and its commit
+func (w *Writer) AddTendermintData(data TendermintData) error {
+	if data.State.LastBlockHeight != int64(w.meta.Height) || data.Block == nil || data.SeenCommit == nil {
+		return fmt.Errorf("tendermint data does not match snapshot height %d", w.meta.Height)
+	}
+
+	items := []struct {
+		key   []byte
+		value interface{}
+	}{
+		{tmStateKey, data.State},
+		{tmBlockKey, data.Block},
+		{tmSeenCommitKey, data.SeenCommit},
+	}
+
+	for _, item := range items {
+		enc, err := cdc.MarshalBinaryBare(item.value)
+		if err != nil {
+			return err
+		}
+
+		if err := w.Add(StoreTendermint, item.key, enc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeTendermintItem puts item of Tendermint store to data
+func decodeTendermintItem(data *TendermintData, item Item) error {
+	switch string(item.Key) {
+	case string(tmStateKey):
+		return cdc.UnmarshalBinaryBare(item.Value, &data.State)
+	case string(tmBlockKey):
+		data.Block = new(types.Block)
+		return cdc.UnmarshalBinaryBare(item.Value, data.Block)
+	case string(tmSeenCommitKey):
+		data.SeenCommit = new(types.Commit)
+		return cdc.UnmarshalBinaryBare(item.Value, data.SeenCommit)
+	default:
+		return fmt.Errorf("unknown tendermint item %s", item.Key)
+	}
+}
+
+// verifyTendermintData checks that block of snapshot's height belongs to Tendermint's state and is committed
+// by validators its header refers to, and that validators of the next heights are the verified ones.
+// Validators of the verified state take part in consensus after the next height, so the next validator set
+// of Tendermint should consist of them.
+func verifyTendermintData(data TendermintData, validators abciTypes.ValidatorUpdates) error {
+	if data.Block == nil || data.SeenCommit == nil {
+		return errors.New("snapshot does not contain tendermint data")
+	}
+
+	height, header := data.State.LastBlockHeight, data.Block.Header
+	if header.Height != height || header.ChainID != data.State.ChainID {
+		return fmt.Errorf("block %d of chain %s does not match state", header.Height, header.ChainID)
+	}
+
+	if err := data.Block.ValidateBasic(); err != nil {
+		return fmt.Errorf("invalid block: %v", err)
+	}
+
+	if !bytes.Equal(data.Block.Hash(), data.State.LastBlockID.Hash) {
+		return fmt.Errorf("block hash %X does not match state", data.Block.Hash())
+	}
+
+	if !bytes.Equal(header.ValidatorsHash, data.State.LastValidators.Hash()) ||
+		!bytes.Equal(header.NextValidatorsHash, data.State.Validators.Hash()) {
+		return errors.New("validators of tendermint state do not match block header")
+	}
+
+	// commit of the block should be signed by validators of its height
+	if data.SeenCommit.Height() != height || !data.SeenCommit.BlockID.Equals(data.State.LastBlockID) {
+		return fmt.Errorf("commit does not match block of height %d", height)
+	}
+
+	if err := data.State.LastValidators.VerifyCommit(data.State.ChainID, data.State.LastBlockID, height,
+		data.SeenCommit); err != nil {
+		return fmt.Errorf("invalid commit: %v", err)
+	}
+
+	nextValidators, err := types.PB2TM.ValidatorUpdates(validators)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(types.NewValidatorSet(nextValidators).Hash(), data.State.NextValidators.Hash()) {
+		return errors.New("next validators of tendermint state do not match application validators")
+	}
+
+	return nil
+}
+
+// restoreTendermint writes Tendermint's state and block store so Tendermint continues from snapshot's height.
+// Data should be checked by verifyTendermintData first.
+func restoreTendermint(data TendermintData, stateDB dbm.DB, blockStoreDB dbm.DB) error {
+	height := data.State.LastBlockHeight
+
+	// there is no validators and consensus params history before snapshot's height, so sets
+	// are saved in full and marked as changed at heights Tendermint is going to load them
+	validators := []struct {
+		height int64
+		set    *types.ValidatorSet
+	}{
+		{height, data.State.LastValidators},
+		{height + 1, data.State.Validators},
+	}
+	for _, vals := range validators {
+		info := sm.ValidatorsInfo{
+			ValidatorSet:      vals.set,
+			LastHeightChanged: vals.height,
+		}
+		stateDB.Set([]byte(fmt.Sprintf("validatorsKey:%v", vals.height)), info.Bytes())
+	}
+
+	data.State.LastHeightValidatorsChanged = height + 2
+	data.State.LastHeightConsensusParamsChanged = height + 1
+	sm.SaveState(stateDB, data.State)
+
+	// block store accepts only contiguous blocks
+	bc.BlockStoreStateJSON{Height: height - 1}.Save(blockStoreDB)
+	bc.NewBlockStore(blockStoreDB).SaveBlock(data.Block, data.Block.MakePartSet(types.BlockPartSizeBytes),
+		data.SeenCommit)
+
+	return nil
+}
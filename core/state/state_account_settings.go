@@ -0,0 +1,71 @@
+package state
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"io"
+)
+
+// stateAccountSettings represents delegator's settings of an account which are being modified.
+// Settings are stored apart from account's data so accounts encoding stays untouched.
+type stateAccountSettings struct {
+	address types.Address
+	data    AccountSettings
+
+	onDirty func(addr types.Address)
+}
+
+type AccountSettings struct {
+	CompoundRewards bool
+	RewardAddress   types.Address
+}
+
+// newAccountSettings creates a state account settings object.
+func newAccountSettings(address types.Address, data AccountSettings, onDirty func(addr types.Address)) *stateAccountSettings {
+	return &stateAccountSettings{
+		address: address,
+		data:    data,
+		onDirty: onDirty,
+	}
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (s *stateAccountSettings) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, s.data)
+}
+
+// empty returns whether all settings have default values.
+func (s *stateAccountSettings) empty() bool {
+	return !s.data.CompoundRewards && s.data.RewardAddress == types.Address{}
+}
+
+func (s *stateAccountSettings) SetCompoundRewards(compound bool) {
+	s.data.CompoundRewards = compound
+	s.onDirty(s.address)
+}
+
+// SetRewardAddress sets recipient of delegator's rewards. Zero address resets it to the account itself.
+func (s *stateAccountSettings) SetRewardAddress(address types.Address) {
+	s.data.RewardAddress = address
+	s.onDirty(s.address)
+}
+
+//
+// Attribute accessors
+//
+
+func (s *stateAccountSettings) Address() types.Address {
+	return s.address
+}
+
+func (s *stateAccountSettings) CompoundRewards() bool {
+	return s.data.CompoundRewards
+}
+
+func (s *stateAccountSettings) RewardAddress() types.Address {
+	return s.data.RewardAddress
+}
+
+func (s *stateAccountSettings) Data() AccountSettings {
+	return s.data
+}
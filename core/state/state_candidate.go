@@ -24,6 +24,14 @@ type stateCandidates struct {
 	db   *StateDB
 
 	onDirty func() // Callback method to mark a state object newly dirty
+
+	// splitStorage is set if candidates and stakes are stored in separate keys, see state_candidate_storage.go
+	splitStorage bool
+
+	// positions of candidates in data by public keys and flags of loaded ones. They are set only while candidates
+	// of split layout are loaded on demand, data holds public keys of other candidates then.
+	positions map[string]int
+	loaded    []bool
 }
 
 type Candidates []Candidate
@@ -60,16 +68,7 @@ func (s *Stake) CalcSimulatedBipValue(context *StateDB) *big.Int {
 
 	totalStaked := big.NewInt(0)
 	totalStaked.Add(totalStaked, s.Value)
-
-	candidates := context.getStateCandidates()
-
-	for _, candidate := range candidates.data {
-		for _, stake := range candidate.Stakes {
-			if stake.Coin == s.Coin {
-				totalStaked.Add(totalStaked, stake.Value)
-			}
-		}
-	}
+	totalStaked.Add(totalStaked, context.getTotalStaked(s.Coin))
 
 	coin := context.getStateCoin(s.Coin)
 	bipValue := formula.CalculateSaleReturn(coin.Volume(), coin.ReserveBalance(), coin.data.Crr, totalStaked)
@@ -122,6 +121,46 @@ func (s *Stake) CalcBipValue(context *StateDB) *big.Int {
 	return value
 }
 
+// getTotalStaked returns total value of stakes in given coin. The result is cached until the end of block, Delegate
+// and SubStake keep it up to date, other changes of candidates reset it. Candidates which are not loaded are read
+// from storage without loading them.
+func (s *StateDB) getTotalStaked(coin types.CoinSymbol) *big.Int {
+	if total, has := s.stakeTotals[coin]; has {
+		return total
+	}
+
+	total := big.NewInt(0)
+	if candidates := s.getStateCandidatesOnDemand(); candidates != nil {
+		for i := range candidates.data {
+			candidate := &candidates.data[i]
+			if !candidates.isLoaded(i) {
+				stored := s.loadCandidate(candidate.PubKey)
+				candidate = &stored
+			}
+
+			for _, stake := range candidate.Stakes {
+				if stake.Coin == coin {
+					total.Add(total, stake.Value)
+				}
+			}
+		}
+	}
+
+	if s.stakeTotals == nil {
+		s.stakeTotals = make(map[types.CoinSymbol]*big.Int)
+	}
+	s.stakeTotals[coin] = total
+
+	return total
+}
+
+// addTotalStaked adds delta to cached total value of stakes in given coin
+func (s *StateDB) addTotalStaked(coin types.CoinSymbol, delta *big.Int) {
+	if total, has := s.stakeTotals[coin]; has {
+		total.Add(total, delta)
+	}
+}
+
 type Candidate struct {
 	RewardAddress  types.Address
 	OwnerAddress   types.Address
@@ -145,6 +184,18 @@ func (candidate Candidate) GetStakeOfAddress(addr types.Address, coin types.Coin
 	return nil
 }
 
+// SelfStake returns total bip value of stakes delegated by candidate's owner
+func (candidate Candidate) SelfStake() *big.Int {
+	selfStake := big.NewInt(0)
+	for _, stake := range candidate.Stakes {
+		if stake.Owner == candidate.OwnerAddress && stake.BipValue != nil {
+			selfStake.Add(selfStake, stake.BipValue)
+		}
+	}
+
+	return selfStake
+}
+
 func (candidate Candidate) String() string {
 	return fmt.Sprintf("Candidate")
 }
@@ -0,0 +1,93 @@
+package state
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"io"
+)
+
+// Reasons of candidate's jailing
+const (
+	JailReasonAbsent    byte = 1
+	JailReasonByzantine byte = 2
+)
+
+// stateCandidateJail represents candidate's jail status which is being modified.
+type stateCandidateJail struct {
+	pubkey  types.Pubkey
+	data    CandidateJail
+	deleted bool
+
+	onDirty func(pubkey types.Pubkey)
+}
+
+// CandidateJail holds jail status and history of a candidate. Tombstoned candidates can never be unjailed
+// and their public key can not be declared again.
+type CandidateJail struct {
+	JailedUntil uint64
+	Tombstoned  bool
+	History     []JailRecord
+}
+
+type JailRecord struct {
+	Height      uint64
+	JailedUntil uint64
+	Reason      byte
+}
+
+// IsJailed checks if candidate is in jail
+func (j CandidateJail) IsJailed() bool {
+	return j.JailedUntil > 0 || j.Tombstoned
+}
+
+// newCandidateJail creates a state candidate jail.
+func newCandidateJail(pubkey types.Pubkey, data CandidateJail, onDirty func(pubkey types.Pubkey)) *stateCandidateJail {
+	return &stateCandidateJail{
+		pubkey:  pubkey,
+		data:    data,
+		onDirty: onDirty,
+	}
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (j *stateCandidateJail) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, j.data)
+}
+
+func (j *stateCandidateJail) Jail(height uint64, until uint64, reason byte) {
+	j.data.JailedUntil = until
+	j.data.History = append(j.data.History, JailRecord{
+		Height:      height,
+		JailedUntil: until,
+		Reason:      reason,
+	})
+	j.deleted = false
+	j.onDirty(j.pubkey)
+}
+
+func (j *stateCandidateJail) Tombstone() {
+	j.data.Tombstoned = true
+	j.onDirty(j.pubkey)
+}
+
+func (j *stateCandidateJail) Unjail() {
+	j.data.JailedUntil = 0
+	j.onDirty(j.pubkey)
+}
+
+func (j *stateCandidateJail) Delete() {
+	j.deleted = true
+	j.onDirty(j.pubkey)
+}
+
+//
+// Attribute accessors
+//
+
+func (j *stateCandidateJail) PubKey() types.Pubkey {
+	return j.pubkey
+}
+
+func (j *stateCandidateJail) Data() CandidateJail {
+	return j.data
+}
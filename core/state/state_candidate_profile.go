@@ -0,0 +1,67 @@
+package state
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"io"
+)
+
+// stateCandidateProfile represents candidate's public profile which is being modified.
+type stateCandidateProfile struct {
+	pubkey  types.Pubkey
+	data    CandidateProfile
+	deleted bool
+
+	onDirty func(pubkey types.Pubkey)
+}
+
+// CandidateProfile is an on-chain information about candidate provided by its owner
+type CandidateProfile struct {
+	Moniker         string
+	Website         string
+	Description     string
+	SecurityContact string
+	LogoHash        string
+}
+
+// Size returns total length of profile's fields in bytes
+func (p CandidateProfile) Size() int {
+	return len(p.Moniker) + len(p.Website) + len(p.Description) + len(p.SecurityContact) + len(p.LogoHash)
+}
+
+// newCandidateProfile creates a state candidate profile.
+func newCandidateProfile(pubkey types.Pubkey, data CandidateProfile, onDirty func(pubkey types.Pubkey)) *stateCandidateProfile {
+	return &stateCandidateProfile{
+		pubkey:  pubkey,
+		data:    data,
+		onDirty: onDirty,
+	}
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (p *stateCandidateProfile) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, p.data)
+}
+
+func (p *stateCandidateProfile) SetData(data CandidateProfile) {
+	p.data = data
+	p.deleted = false
+	p.onDirty(p.pubkey)
+}
+
+func (p *stateCandidateProfile) Delete() {
+	p.deleted = true
+	p.onDirty(p.pubkey)
+}
+
+//
+// Attribute accessors
+//
+
+func (p *stateCandidateProfile) PubKey() types.Pubkey {
+	return p.pubkey
+}
+
+func (p *stateCandidateProfile) Data() CandidateProfile {
+	return p.data
+}
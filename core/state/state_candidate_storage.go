@@ -0,0 +1,343 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"math/big"
+)
+
+// Candidates are stored either as a single list under candidatesKey (legacy layout) or split into
+// separate keys (split layout):
+//
+//	candidatesIndexKey                  -> ordered list of candidates' public keys
+//	candidatePrefix + pubkey            -> candidate's data and ordered list of its stakes
+//	stakePrefix + pubkey + owner + coin -> stake's value and bip value
+//
+// With split layout Commit writes only candidates and stakes which were changed, so the cost of a
+// delegation does not depend on the total number of stakes. Existing state is switched to split
+// layout by MigrateCandidatesStorage at an upgrade height.
+
+// candidateRecord is candidate's data stored under its own key
+type candidateRecord struct {
+	RewardAddress  types.Address
+	OwnerAddress   types.Address
+	TotalBipStake  *big.Int
+	Commission     uint
+	CreatedAtBlock uint
+	Status         byte
+	Stakes         []stakeID
+}
+
+// stakeID identifies stake within a candidate and keeps order of candidate's stakes
+type stakeID struct {
+	Owner types.Address
+	Coin  types.CoinSymbol
+}
+
+// stakeRecord is stake's data stored under its own key
+type stakeRecord struct {
+	Value    *big.Int
+	BipValue *big.Int
+}
+
+// MigrateCandidatesStorage switches candidates to split storage layout. All candidates and stakes
+// are written to their keys and the legacy list is removed on Commit.
+func (s *StateDB) MigrateCandidatesStorage() {
+	candidates := s.getStateCandidates()
+	if candidates == nil {
+		candidates = newCandidate(s, Candidates{}, s.MarkStateCandidateDirty)
+		s.setStateCandidates(candidates)
+	}
+
+	candidates.splitStorage = true
+	s.MarkStateCandidateDirty()
+}
+
+// loadStateCandidatesSplit loads index of candidates stored in split layout. Candidates themselves are loaded
+// from their keys on demand, see stateCandidates.get and stateCandidates.loadAll. Returns nil if not found.
+func (s *StateDB) loadStateCandidatesSplit() *stateCandidates {
+	_, enc := s.iavl.Get(candidatesIndexKey)
+	if len(enc) == 0 {
+		return nil
+	}
+
+	var pubkeys []types.Pubkey
+	if err := rlp.DecodeBytes(enc, &pubkeys); err != nil {
+		panic(err)
+	}
+
+	data := make(Candidates, len(pubkeys))
+	positions := make(map[string]int, len(pubkeys))
+	for i, pubkey := range pubkeys {
+		data[i].PubKey = pubkey
+		positions[string(pubkey)] = i
+	}
+
+	// unlike legacy layout, loading does not make candidates dirty
+	obj := &stateCandidates{
+		db:           s,
+		data:         data,
+		onDirty:      s.MarkStateCandidateDirty,
+		splitStorage: true,
+		positions:    positions,
+		loaded:       make([]bool, len(pubkeys)),
+	}
+	s.setStateCandidates(obj)
+	return obj
+}
+
+// loadCandidate reads candidate with given public key and its stakes from their keys
+func (s *StateDB) loadCandidate(pubkey types.Pubkey) Candidate {
+	record := s.getCandidateRecord(pubkey)
+	if record == nil {
+		panic(fmt.Sprintf("candidate %s not found", pubkey.String()))
+	}
+
+	candidate := Candidate{
+		RewardAddress:  record.RewardAddress,
+		OwnerAddress:   record.OwnerAddress,
+		TotalBipStake:  record.TotalBipStake,
+		PubKey:         pubkey,
+		Commission:     record.Commission,
+		CreatedAtBlock: record.CreatedAtBlock,
+		Status:         record.Status,
+	}
+
+	if len(record.Stakes) > 0 {
+		candidate.Stakes = make([]Stake, len(record.Stakes))
+	}
+
+	for j, id := range record.Stakes {
+		_, enc := s.iavl.Get(getStakeKey(pubkey, id))
+		var stake stakeRecord
+		if err := rlp.DecodeBytes(enc, &stake); err != nil {
+			panic(err)
+		}
+
+		candidate.Stakes[j] = Stake{
+			Owner:    id.Owner,
+			Coin:     id.Coin,
+			Value:    stake.Value,
+			BipValue: stake.BipValue,
+		}
+	}
+
+	return candidate
+}
+
+// isLoaded checks if candidate at given position of data is loaded
+func (c *stateCandidates) isLoaded(i int) bool {
+	return c.positions == nil || c.loaded[i]
+}
+
+// load loads candidate at given position of data if it is not loaded yet
+func (c *stateCandidates) load(i int) {
+	if c.isLoaded(i) {
+		return
+	}
+
+	c.data[i] = c.db.loadCandidate(c.data[i].PubKey)
+	c.loaded[i] = true
+}
+
+// loadAll loads all candidates, so data can be iterated and changed freely. Candidates are loaded in place, so
+// pointers returned by get stay valid.
+func (c *stateCandidates) loadAll() {
+	if c.positions == nil {
+		return
+	}
+
+	for i := range c.data {
+		c.load(i)
+	}
+
+	c.positions, c.loaded = nil, nil
+}
+
+// reindex updates positions of candidates after their public keys are changed or candidates are appended to data.
+// Appended candidates are considered loaded. Candidates can be removed only after loadAll.
+func (c *stateCandidates) reindex() {
+	if c.positions == nil {
+		return
+	}
+
+	c.positions = make(map[string]int, len(c.data))
+	for i := range c.data {
+		c.positions[string(c.data[i].PubKey)] = i
+	}
+
+	for len(c.loaded) < len(c.data) {
+		c.loaded = append(c.loaded, true)
+	}
+}
+
+// get returns candidate with given public key or nil if not found. Only this candidate is loaded if candidates
+// are loaded on demand.
+func (c *stateCandidates) get(pubkey types.Pubkey) *Candidate {
+	if c.positions != nil {
+		i, has := c.positions[string(pubkey)]
+		if !has {
+			return nil
+		}
+
+		c.load(i)
+		return &c.data[i]
+	}
+
+	for i := range c.data {
+		if bytes.Equal(c.data[i].PubKey, pubkey) {
+			return &c.data[i]
+		}
+	}
+
+	return nil
+}
+
+// updateStateCandidatesSplit writes changed candidates and stakes to their keys
+func (s *StateDB) updateStateCandidatesSplit(candidates *stateCandidates) {
+	if s.stateCandidatesDirtyAll {
+		s.updateCandidatesIndex(candidates.data)
+		s.iavl.Remove(candidatesKey)
+	}
+
+	for i, candidate := range candidates.data {
+		// candidates which are not loaded are not changed
+		if !candidates.isLoaded(i) {
+			continue
+		}
+
+		if _, dirty := s.stateCandidatesDirtyKeys[string(candidate.PubKey)]; s.stateCandidatesDirtyAll || dirty {
+			s.updateCandidateRecord(candidate)
+		}
+	}
+}
+
+// updateCandidatesIndex writes the list of candidates and removes data of candidates which are not in it anymore
+func (s *StateDB) updateCandidatesIndex(data Candidates) {
+	pubkeys := make([]types.Pubkey, len(data))
+	current := make(map[string]struct{}, len(data))
+	for i, candidate := range data {
+		pubkeys[i] = candidate.PubKey
+		current[string(candidate.PubKey)] = struct{}{}
+	}
+
+	enc, err := rlp.EncodeToBytes(pubkeys)
+	if err != nil {
+		panic(fmt.Errorf("can't encode candidates index: %v", err))
+	}
+
+	_, stored := s.iavl.Get(candidatesIndexKey)
+	if bytes.Equal(stored, enc) {
+		return
+	}
+
+	if len(stored) > 0 {
+		var storedPubkeys []types.Pubkey
+		if err := rlp.DecodeBytes(stored, &storedPubkeys); err != nil {
+			panic(err)
+		}
+
+		for _, pubkey := range storedPubkeys {
+			if _, has := current[string(pubkey)]; !has {
+				s.deleteCandidateRecord(pubkey)
+			}
+		}
+	}
+
+	s.iavl.Set(candidatesIndexKey, enc)
+}
+
+// updateCandidateRecord writes candidate's data and stakes which differ from stored ones
+func (s *StateDB) updateCandidateRecord(candidate Candidate) {
+	record := candidateRecord{
+		RewardAddress:  candidate.RewardAddress,
+		OwnerAddress:   candidate.OwnerAddress,
+		TotalBipStake:  candidate.TotalBipStake,
+		Commission:     candidate.Commission,
+		CreatedAtBlock: candidate.CreatedAtBlock,
+		Status:         candidate.Status,
+		Stakes:         make([]stakeID, len(candidate.Stakes)),
+	}
+
+	current := make(map[stakeID]struct{}, len(candidate.Stakes))
+	for i, stake := range candidate.Stakes {
+		record.Stakes[i] = stakeID{Owner: stake.Owner, Coin: stake.Coin}
+		current[record.Stakes[i]] = struct{}{}
+	}
+
+	enc, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		panic(fmt.Errorf("can't encode candidate: %v", err))
+	}
+
+	key := getCandidateKey(candidate.PubKey)
+	_, stored := s.iavl.Get(key)
+	if !bytes.Equal(stored, enc) {
+		// remove stakes which are not in the list anymore
+		if storedRecord := s.getCandidateRecord(candidate.PubKey); storedRecord != nil {
+			for _, id := range storedRecord.Stakes {
+				if _, has := current[id]; !has {
+					s.iavl.Remove(getStakeKey(candidate.PubKey, id))
+				}
+			}
+		}
+
+		s.iavl.Set(key, enc)
+	}
+
+	for i, stake := range candidate.Stakes {
+		enc, err := rlp.EncodeToBytes(stakeRecord{
+			Value:    stake.Value,
+			BipValue: stake.BipValue,
+		})
+		if err != nil {
+			panic(fmt.Errorf("can't encode stake: %v", err))
+		}
+
+		key := getStakeKey(candidate.PubKey, record.Stakes[i])
+		if _, stored := s.iavl.Get(key); !bytes.Equal(stored, enc) {
+			s.iavl.Set(key, enc)
+		}
+	}
+}
+
+func (s *StateDB) deleteCandidateRecord(pubkey types.Pubkey) {
+	record := s.getCandidateRecord(pubkey)
+	if record == nil {
+		return
+	}
+
+	for _, id := range record.Stakes {
+		s.iavl.Remove(getStakeKey(pubkey, id))
+	}
+
+	s.iavl.Remove(getCandidateKey(pubkey))
+}
+
+func (s *StateDB) getCandidateRecord(pubkey types.Pubkey) *candidateRecord {
+	_, enc := s.iavl.Get(getCandidateKey(pubkey))
+	if len(enc) == 0 {
+		return nil
+	}
+
+	var record candidateRecord
+	if err := rlp.DecodeBytes(enc, &record); err != nil {
+		panic(err)
+	}
+
+	return &record
+}
+
+func getCandidateKey(pubkey types.Pubkey) []byte {
+	return append(append([]byte{}, candidatePrefix...), pubkey...)
+}
+
+func getStakeKey(pubkey types.Pubkey, id stakeID) []byte {
+	key := make([]byte, 0, len(stakePrefix)+len(pubkey)+types.AddressLength+types.CoinSymbolLength)
+	key = append(key, stakePrefix...)
+	key = append(key, pubkey...)
+	key = append(key, id.Owner[:]...)
+	return append(key, id.Coin[:]...)
+}
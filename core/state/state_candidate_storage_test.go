@@ -0,0 +1,230 @@
+package state
+
+import (
+	"crypto/rand"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/tendermint/tendermint/libs/db"
+	"math/big"
+	"testing"
+)
+
+func TestStateDB_MigrateCandidatesStorage(t *testing.T) {
+	memDB := db.NewMemDB()
+	s, err := New(0, memDB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+	pubkeys := [][]byte{createTestCandidate(s), createTestCandidate(s), createTestCandidate(s)}
+	for _, pubkey := range pubkeys {
+		s.Delegate(owner, pubkey, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(10)))
+	}
+
+	if _, _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	s.MigrateCandidatesStorage()
+	if _, _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, enc := s.iavl.Get(candidatesKey); len(enc) != 0 {
+		t.Fatalf("Legacy candidates list should be removed")
+	}
+
+	// unbond whole stake of the second candidate
+	s.SubStake(owner, pubkeys[1], types.GetBaseCoin(), helpers.BipToPip(big.NewInt(10)))
+	_, version, err := s.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := New(uint64(version), memDB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	candidate := loaded.GetStateCandidate(pubkeys[2])
+	if candidate == nil || len(candidate.Stakes) != 2 {
+		t.Fatalf("Candidate is not loaded from split storage")
+	}
+
+	loadedCount := 0
+	for _, isLoaded := range loaded.stateCandidates.loaded {
+		if isLoaded {
+			loadedCount++
+		}
+	}
+
+	if loaded.stateCandidates.positions == nil || loadedCount != 1 {
+		t.Fatalf("Only requested candidate should be loaded, got %d loaded", loadedCount)
+	}
+
+	candidates := loaded.GetStateCandidates()
+	if candidates.positions != nil || candidates.data[2].PubKey.Compare(candidate.PubKey) != 0 {
+		t.Fatalf("All candidates should be loaded in place")
+	}
+
+	if candidates == nil || !candidates.splitStorage || len(candidates.data) != len(pubkeys) {
+		t.Fatalf("Candidates are not loaded from split storage")
+	}
+
+	for i, pubkey := range pubkeys {
+		candidate := candidates.data[i]
+		if candidate.PubKey.Compare(pubkey) != 0 {
+			t.Fatalf("Order of candidates is not kept")
+		}
+
+		expectedStakes := 2
+		if i == 1 {
+			expectedStakes = 1
+		}
+
+		if len(candidate.Stakes) != expectedStakes {
+			t.Fatalf("Candidate %d should have %d stakes, got %d", i, expectedStakes, len(candidate.Stakes))
+		}
+	}
+
+	stake := candidates.data[0].GetStakeOfAddress(owner, types.GetBaseCoin())
+	if stake == nil || stake.Value.Cmp(helpers.BipToPip(big.NewInt(10))) != 0 {
+		t.Fatalf("Stake is not loaded from split storage")
+	}
+
+	if _, enc := loaded.iavl.Get(getStakeKey(pubkeys[1], stakeID{Owner: owner, Coin: types.GetBaseCoin()})); len(enc) != 0 {
+		t.Fatalf("Removed stake should be deleted from storage")
+	}
+}
+
+func TestStateDB_DelegateOnDemand(t *testing.T) {
+	memDB := db.NewMemDB()
+	s, err := New(0, memDB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+	pubkeys := [][]byte{createTestCandidate(s), createTestCandidate(s), createTestCandidate(s)}
+	for _, pubkey := range pubkeys {
+		s.Delegate(owner, pubkey, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(10)))
+	}
+
+	s.MigrateCandidatesStorage()
+	_, version, err := s.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := New(uint64(version), memDB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	countLoaded := func() int {
+		count := 0
+		for _, isLoaded := range loaded.stateCandidates.loaded {
+			if isLoaded {
+				count++
+			}
+		}
+
+		return count
+	}
+
+	loaded.Delegate(owner, pubkeys[0], types.GetBaseCoin(), helpers.BipToPip(big.NewInt(5)))
+	if loaded.stateCandidates.positions == nil || countLoaded() != 1 {
+		t.Fatalf("Only candidate of delegation should be loaded")
+	}
+
+	if total := loaded.getTotalStaked(types.GetBaseCoin()); total.Cmp(helpers.BipToPip(big.NewInt(38))) != 0 {
+		t.Fatalf("Total staked is not correct, got %s", total)
+	}
+
+	loaded.SubStake(owner, pubkeys[1], types.GetBaseCoin(), helpers.BipToPip(big.NewInt(4)))
+	if total := loaded.getTotalStaked(types.GetBaseCoin()); total.Cmp(helpers.BipToPip(big.NewInt(34))) != 0 {
+		t.Fatalf("Total staked is not updated, got %s", total)
+	}
+
+	newPubkey := make([]byte, 32)
+	rand.Read(newPubkey)
+	loaded.GetStateCandidate(pubkeys[0]).PubKey = newPubkey
+	loaded.MarkStateCandidateDirty()
+
+	if loaded.stateCandidates.positions == nil || countLoaded() != 2 {
+		t.Fatalf("Marking candidates dirty should not load all of them")
+	}
+
+	if _, _, err := loaded.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	candidates := loaded.GetStateCandidates()
+	if len(candidates.data) != len(pubkeys) || candidates.data[0].PubKey.Compare(newPubkey) != 0 {
+		t.Fatalf("Candidates are not kept")
+	}
+
+	stake := loaded.GetStateCandidate(pubkeys[2]).GetStakeOfAddress(owner, types.GetBaseCoin())
+	if stake == nil || stake.Value.Cmp(helpers.BipToPip(big.NewInt(10))) != 0 {
+		t.Fatalf("Stake of candidate which was not loaded is not kept")
+	}
+
+	if candidate := loaded.GetStateCandidate(newPubkey); candidate == nil || len(candidate.Stakes) != 2 {
+		t.Fatalf("Candidate with changed public key is not kept")
+	}
+}
+
+func BenchmarkStateDB_CommitDelegation(b *testing.B) {
+	for _, split := range []bool{false, true} {
+		for _, stakesCount := range []int{1000, 10000} {
+			b.Run(fmt.Sprintf("split=%t/stakes=%d", split, stakesCount), func(b *testing.B) {
+				memDB := db.NewMemDB()
+				s, err := New(0, memDB, false)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				var pubkeys [][]byte
+				for i := 0; i < stakesCount/100; i++ {
+					pubkey := createTestCandidate(s)
+					pubkeys = append(pubkeys, pubkey)
+
+					for j := 0; j < 100; j++ {
+						var owner types.Address
+						rand.Read(owner[:])
+						s.Delegate(owner, pubkey, types.GetBaseCoin(), big.NewInt(1))
+					}
+				}
+
+				if split {
+					s.MigrateCandidatesStorage()
+				}
+
+				_, version, err := s.Commit()
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				owner := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					// state is opened from database, so candidates are loaded cold as in a new block
+					b.StopTimer()
+					s, err = New(uint64(version), memDB, false)
+					if err != nil {
+						b.Fatal(err)
+					}
+					b.StartTimer()
+
+					s.Delegate(owner, pubkeys[i%len(pubkeys)], types.GetBaseCoin(), big.NewInt(1))
+					if _, version, err = s.Commit(); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"io"
+
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rlp"
+)
+
+// stateCommissionChanges represents pending candidates' commission changes which are being modified.
+type stateCommissionChanges struct {
+	data CommissionChanges
+	db   *StateDB
+
+	onDirty func() // Callback method to mark a state object newly dirty
+}
+
+type CommissionChanges []CommissionChange
+
+// CommissionChange is a commission change requested by candidate's owner. It takes effect at given Height.
+type CommissionChange struct {
+	PubKey     types.Pubkey
+	Commission uint
+	Height     uint64
+}
+
+func (c CommissionChange) String() string {
+	return fmt.Sprintf("Commission change of %s to %d%% at height %d", c.PubKey, c.Commission, c.Height)
+}
+
+// newCommissionChanges creates a state object.
+func newCommissionChanges(db *StateDB, data CommissionChanges, onDirty func()) *stateCommissionChanges {
+	changes := &stateCommissionChanges{
+		db:      db,
+		data:    data,
+		onDirty: onDirty,
+	}
+
+	changes.onDirty()
+
+	return changes
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (c *stateCommissionChanges) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, c.data)
+}
+
+func (c *stateCommissionChanges) Data() CommissionChanges {
+	return c.data
+}
@@ -1,6 +1,7 @@
 package state
 
 import (
+	"bytes"
 	"github.com/MinterTeam/minter-go-node/eventsdb"
 	"github.com/MinterTeam/minter-go-node/eventsdb/events"
 	"github.com/MinterTeam/minter-go-node/formula"
@@ -72,6 +73,21 @@ func (c *stateFrozenFund) AddFund(address types.Address, candidateKey []byte, co
 	})
 }
 
+// replaceCandidateKey moves funds unbonded from candidate with retired public key to its new key
+func (c *stateFrozenFund) replaceCandidateKey(pubKey []byte, newPubKey []byte) {
+	changed := false
+	for i := range c.data.List {
+		if bytes.Equal(c.data.List[i].CandidateKey, pubKey) {
+			c.data.List[i].CandidateKey = newPubKey
+			changed = true
+		}
+	}
+
+	if changed {
+		c.onDirty(c.blockHeight)
+	}
+}
+
 func (c *stateFrozenFund) addFund(fund FrozenFund) {
 	c.data.List = append(c.data.List, fund)
 
@@ -81,12 +97,49 @@ func (c *stateFrozenFund) addFund(fund FrozenFund) {
 	}
 }
 
+// amountOf returns total value of funds of address unbonded from candidate in coin
+func (c *stateFrozenFund) amountOf(address types.Address, candidateKey []byte, coin types.CoinSymbol) *big.Int {
+	amount := big.NewInt(0)
+	for _, item := range c.data.List {
+		if item.Address == address && item.Coin == coin && bytes.Equal(item.CandidateKey, candidateKey) {
+			amount.Add(amount, item.Value)
+		}
+	}
+
+	return amount
+}
+
+// subAmount subtracts value from funds of address unbonded from candidate in coin, value should not exceed amountOf
+func (c *stateFrozenFund) subAmount(address types.Address, candidateKey []byte, coin types.CoinSymbol, value *big.Int) {
+	remaining := big.NewInt(0).Set(value)
+	for i := range c.data.List {
+		item := &c.data.List[i]
+		if remaining.Sign() == 0 {
+			break
+		}
+
+		if item.Address != address || item.Coin != coin || !bytes.Equal(item.CandidateKey, candidateKey) {
+			continue
+		}
+
+		amount := big.NewInt(0).Set(item.Value)
+		if remaining.Cmp(amount) < 0 {
+			amount.Set(remaining)
+		}
+
+		item.Value = big.NewInt(0).Sub(item.Value, amount)
+		remaining.Sub(remaining, amount)
+	}
+
+	c.db.MarkStateFrozenFundsDirty(c.blockHeight)
+}
+
 // punish fund with given candidate key (used in byzantine validator's punishment)
-func (c *stateFrozenFund) PunishFund(context *StateDB, candidateAddress [20]byte, fromBlock uint64) {
-	c.punishFund(context, candidateAddress, fromBlock)
+func (c *stateFrozenFund) PunishFund(context *StateDB, candidateAddress [20]byte, fromBlock uint64, evidenceHeight uint64) {
+	c.punishFund(context, candidateAddress, fromBlock, evidenceHeight)
 }
 
-func (c *stateFrozenFund) punishFund(context *StateDB, candidateAddress [20]byte, fromBlock uint64) {
+func (c *stateFrozenFund) punishFund(context *StateDB, candidateAddress [20]byte, fromBlock uint64, evidenceHeight uint64) {
 	edb := eventsdb.GetCurrent()
 
 	newList := make([]FrozenFund, len(c.data.List))
@@ -100,7 +153,7 @@ func (c *stateFrozenFund) punishFund(context *StateDB, candidateAddress [20]byte
 
 		if candidateAddress == address {
 			newValue := big.NewInt(0).Set(item.Value)
-			newValue.Mul(newValue, big.NewInt(95))
+			newValue.Mul(newValue, big.NewInt(int64(100-context.GetParams().ByzantineSlashPercent)))
 			newValue.Div(newValue, big.NewInt(100))
 
 			slashed := big.NewInt(0).Set(item.Value)
@@ -123,6 +176,8 @@ func (c *stateFrozenFund) punishFund(context *StateDB, candidateAddress [20]byte
 				Amount:          slashed.Bytes(),
 				Coin:            item.Coin,
 				ValidatorPubKey: item.CandidateKey,
+				Reason:          events.SlashReasonByzantine,
+				EvidenceHeight:  evidenceHeight,
 			})
 
 			item.Value = newValue
@@ -0,0 +1,229 @@
+package state
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"math/big"
+	"sort"
+)
+
+// Frozen funds are stored by release height only. To find pending funds of an address or of a candidate
+// without scanning all of them, release heights are indexed:
+//
+//	frozenFundsAddressPrefix + address + height        -> frozen funds at height contain fund of address
+//	frozenFundsCandidatePrefix + candidate key + height -> frozen funds at height contain fund of candidate
+//
+// Index is written together with frozen funds on Commit. Existing state is indexed by
+// MigrateFrozenFundsIndex at an upgrade height, before that lookups fall back to scanning.
+
+var indexValue = []byte{1}
+
+// PendingFrozenFund is a frozen fund together with its release height
+type PendingFrozenFund struct {
+	Height       uint64
+	Address      types.Address
+	CandidateKey types.Pubkey
+	Coin         types.CoinSymbol
+	Value        *big.Int
+}
+
+// GetFrozenFundsOfAddress returns all pending frozen funds of given address ordered by release height
+func (s *StateDB) GetFrozenFundsOfAddress(owner types.Address) []PendingFrozenFund {
+	return s.getPendingFrozenFunds(getFrozenFundsIndexPrefix(frozenFundsAddressPrefix, owner[:]), func(fund FrozenFund) bool {
+		return fund.Address == owner
+	})
+}
+
+// GetFrozenFundsOfCandidate returns all pending frozen funds unbonded from given candidate ordered by release height
+func (s *StateDB) GetFrozenFundsOfCandidate(pubkey types.Pubkey) []PendingFrozenFund {
+	return s.getPendingFrozenFunds(getFrozenFundsIndexPrefix(frozenFundsCandidatePrefix, pubkey), func(fund FrozenFund) bool {
+		return bytes.Equal(fund.CandidateKey, pubkey)
+	})
+}
+
+// MigrateFrozenFundsIndex indexes all stored frozen funds and enables maintaining of the index
+func (s *StateDB) MigrateFrozenFundsIndex() {
+	if s.isFrozenFundsIndexed() {
+		return
+	}
+
+	heights := s.getStoredFrozenFundsHeights()
+	for _, height := range heights {
+		for _, key := range getFrozenFundsIndexKeys(height, s.getStoredFrozenFunds(height).List) {
+			s.iavl.Set(key, indexValue)
+		}
+	}
+
+	s.iavl.Set(frozenFundsIndexedKey, indexValue)
+}
+
+func (s *StateDB) isFrozenFundsIndexed() bool {
+	_, enc := s.iavl.Get(frozenFundsIndexedKey)
+	return len(enc) != 0
+}
+
+// updateFrozenFundsIndex brings index of frozen funds at given height in line with the new list of funds.
+// Must be called before frozen funds are written.
+func (s *StateDB) updateFrozenFundsIndex(height uint64, list []FrozenFund) {
+	if !s.isFrozenFundsIndexed() {
+		return
+	}
+
+	oldKeys := getFrozenFundsIndexKeys(height, s.getStoredFrozenFunds(height).List)
+	newKeys := getFrozenFundsIndexKeys(height, list)
+
+	current := make(map[string]struct{}, len(newKeys))
+	for _, key := range newKeys {
+		current[string(key)] = struct{}{}
+	}
+
+	stored := make(map[string]struct{}, len(oldKeys))
+	for _, key := range oldKeys {
+		stored[string(key)] = struct{}{}
+		if _, has := current[string(key)]; !has {
+			s.iavl.Remove(key)
+		}
+	}
+
+	for _, key := range newKeys {
+		if _, has := stored[string(key)]; !has {
+			s.iavl.Set(key, indexValue)
+		}
+	}
+}
+
+// getPendingFrozenFunds returns funds matching filter. Heights are taken from the index under given
+// prefix if frozen funds are indexed, otherwise all frozen funds are scanned.
+func (s *StateDB) getPendingFrozenFunds(indexPrefix []byte, filter func(fund FrozenFund) bool) []PendingFrozenFund {
+	var heights []uint64
+	if s.isFrozenFundsIndexed() {
+		s.iavl.IterateRange(indexPrefix, prefixEnd(indexPrefix), true, func(key []byte, value []byte) bool {
+			heights = append(heights, binary.BigEndian.Uint64(key[len(key)-8:]))
+			return false
+		})
+	} else {
+		heights = s.getStoredFrozenFundsHeights()
+	}
+
+	// frozen funds which are not committed yet
+	known := make(map[uint64]struct{}, len(heights))
+	for _, height := range heights {
+		known[height] = struct{}{}
+	}
+	for height := range s.stateFrozenFunds {
+		if _, has := known[height]; !has {
+			heights = append(heights, height)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	var result []PendingFrozenFund
+	for _, height := range heights {
+		var list []FrozenFund
+		if obj := s.stateFrozenFunds[height]; obj != nil {
+			if obj.deleted {
+				continue
+			}
+			list = obj.data.List
+		} else {
+			list = s.getStoredFrozenFunds(height).List
+		}
+
+		for _, fund := range list {
+			if !filter(fund) {
+				continue
+			}
+
+			result = append(result, PendingFrozenFund{
+				Height:       height,
+				Address:      fund.Address,
+				CandidateKey: fund.CandidateKey,
+				Coin:         fund.Coin,
+				Value:        big.NewInt(0).Set(fund.Value),
+			})
+		}
+	}
+
+	return result
+}
+
+// getStoredFrozenFundsHeights returns release heights of all stored frozen funds in ascending order
+func (s *StateDB) getStoredFrozenFundsHeights() []uint64 {
+	var heights []uint64
+	s.iavl.IterateRange(frozenFundsPrefix, prefixEnd(frozenFundsPrefix), true, func(key []byte, value []byte) bool {
+		heights = append(heights, binary.BigEndian.Uint64(key[len(frozenFundsPrefix):]))
+		return false
+	})
+
+	return heights
+}
+
+// getStoredFrozenFunds reads frozen funds at given height bypassing live objects
+func (s *StateDB) getStoredFrozenFunds(height uint64) FrozenFunds {
+	var data FrozenFunds
+
+	_, enc := s.iavl.Get(getFrozenFundsKey(height))
+	if len(enc) == 0 {
+		return data
+	}
+
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		panic(fmt.Errorf("can't decode frozen funds at %d: %v", height, err))
+	}
+
+	return data
+}
+
+// getFrozenFundsIndexKeys returns sorted index keys of given funds
+func getFrozenFundsIndexKeys(height uint64, list []FrozenFund) [][]byte {
+	unique := make(map[string]struct{})
+	for _, fund := range list {
+		unique[string(getFrozenFundsIndexKey(frozenFundsAddressPrefix, fund.Address[:], height))] = struct{}{}
+		unique[string(getFrozenFundsIndexKey(frozenFundsCandidatePrefix, fund.CandidateKey, height))] = struct{}{}
+	}
+
+	keys := make([][]byte, 0, len(unique))
+	for key := range unique {
+		keys = append(keys, []byte(key))
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i], keys[j]) == -1
+	})
+
+	return keys
+}
+
+func getFrozenFundsIndexPrefix(prefix []byte, id []byte) []byte {
+	return append(append([]byte{}, prefix...), id...)
+}
+
+func getFrozenFundsIndexKey(prefix []byte, id []byte, height uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, height)
+	return append(getFrozenFundsIndexPrefix(prefix, id), key...)
+}
+
+func getFrozenFundsKey(height uint64) []byte {
+	key := make([]byte, len(frozenFundsPrefix)+8)
+	copy(key, frozenFundsPrefix)
+	binary.BigEndian.PutUint64(key[len(frozenFundsPrefix):], height)
+	return key
+}
+
+// prefixEnd returns the first key which is greater than all keys with given prefix
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+
+	return nil
+}
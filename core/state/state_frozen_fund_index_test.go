@@ -0,0 +1,56 @@
+package state
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"math/big"
+	"testing"
+)
+
+func TestStateDB_FrozenFundsIndex(t *testing.T) {
+	s := getState()
+
+	owner := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+	pubkeys := [][]byte{createTestCandidate(s), createTestCandidate(s)}
+	value := helpers.BipToPip(big.NewInt(10))
+
+	s.GetOrNewStateFrozenFunds(100).AddFund(owner, pubkeys[0], types.GetBaseCoin(), value)
+	if _, _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// lookup before migration scans all frozen funds
+	if funds := s.GetFrozenFundsOfAddress(owner); len(funds) != 1 || funds[0].Height != 100 {
+		t.Fatalf("Address should have 1 frozen fund at height 100, got %v", funds)
+	}
+
+	s.MigrateFrozenFundsIndex()
+	s.GetOrNewStateFrozenFunds(50).AddFund(owner, pubkeys[1], types.GetBaseCoin(), value)
+	s.GetOrNewStateFrozenFunds(50).AddFund(types.Address{}, pubkeys[0], types.GetBaseCoin(), value)
+	if _, _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	funds := s.GetFrozenFundsOfAddress(owner)
+	if len(funds) != 2 || funds[0].Height != 50 || funds[1].Height != 100 {
+		t.Fatalf("Frozen funds should be ordered by release height, got %v", funds)
+	}
+
+	funds = s.GetFrozenFundsOfCandidate(pubkeys[0])
+	if len(funds) != 2 || funds[0].Address != (types.Address{}) || funds[1].Address != owner {
+		t.Fatalf("Candidate should have 2 frozen funds, got %v", funds)
+	}
+
+	s.GetStateFrozenFunds(50).Delete()
+	if _, _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, enc := s.iavl.Get(getFrozenFundsIndexKey(frozenFundsCandidatePrefix, pubkeys[1], 50)); len(enc) != 0 {
+		t.Fatalf("Index of released frozen funds should be removed")
+	}
+
+	if funds := s.GetFrozenFundsOfCandidate(pubkeys[1]); len(funds) != 0 {
+		t.Fatalf("Candidate should not have frozen funds, got %v", funds)
+	}
+}
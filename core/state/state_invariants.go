@@ -0,0 +1,440 @@
+package state
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/rewards"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/core/validators"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/upgrades"
+	"math/big"
+	"sort"
+)
+
+// Names of built-in invariants
+const (
+	InvariantBipSupply    = "bip-supply"
+	InvariantCoinVolume   = "coin-volume"
+	InvariantCoinReserve  = "coin-reserve"
+	InvariantValidatorSet = "validator-set"
+)
+
+// criticalBipSupplyDelta is a difference of predicted and actual base coin supply which is treated as critical
+var criticalBipSupplyDelta = helpers.BipToPip(big.NewInt(1000))
+
+// Invariant is a rule which the state should always follow. Records of StateDB.ExportStream are passed to Add
+// one by one, then Check reports violations of the rule. A new Invariant is created for every check.
+type Invariant interface {
+	Add(kind string, value interface{})
+	Check(ctx InvariantContext) []Violation
+}
+
+// InvariantContext holds data invariants need besides records of the state
+type InvariantContext struct {
+	Height       uint64
+	GenesisAlloc *big.Int
+}
+
+// Violation describes broken invariant. Coin, Address and PubKey are set if the violation is related to them.
+type Violation struct {
+	Invariant string
+	Coin      types.CoinSymbol
+	Address   *types.Address
+	PubKey    types.Pubkey
+	Message   string
+	Critical  bool
+}
+
+// InvariantsReport holds violations found by a check of invariants
+type InvariantsReport struct {
+	Height     uint64
+	Violations []Violation
+}
+
+// Failed reports whether any invariant is violated
+func (r InvariantsReport) Failed() bool {
+	return len(r.Violations) > 0
+}
+
+// Critical reports whether any violation is critical
+func (r InvariantsReport) Critical() bool {
+	for _, violation := range r.Violations {
+		if violation.Critical {
+			return true
+		}
+	}
+
+	return false
+}
+
+type registeredInvariant struct {
+	name        string
+	constructor func() Invariant
+}
+
+var invariants []registeredInvariant
+
+// RegisterInvariant adds invariant with given name to the invariants checked by CheckInvariants
+func RegisterInvariant(name string, constructor func() Invariant) {
+	for _, invariant := range invariants {
+		if invariant.name == name {
+			panic(fmt.Sprintf("Invariant %s is already registered", name))
+		}
+	}
+
+	invariants = append(invariants, registeredInvariant{name: name, constructor: constructor})
+}
+
+// Invariants returns names of registered invariants in order of registration
+func Invariants() []string {
+	names := make([]string, len(invariants))
+	for i, invariant := range invariants {
+		names[i] = invariant.name
+	}
+
+	return names
+}
+
+func init() {
+	RegisterInvariant(InvariantBipSupply, func() Invariant { return &bipSupplyInvariant{totals: NewTotals()} })
+	RegisterInvariant(InvariantCoinVolume, newCoinVolumeInvariant)
+	RegisterInvariant(InvariantCoinReserve, func() Invariant { return &coinReserveInvariant{} })
+	RegisterInvariant(InvariantValidatorSet, func() Invariant {
+		return &validatorSetInvariant{candidates: map[string]bool{}, seen: map[string]bool{}}
+	})
+}
+
+// CheckInvariants checks all registered invariants in a single pass over the state. Base coin allocated in
+// genesis is needed to predict supply of base coin.
+func (s *StateDB) CheckInvariants(genesisAlloc *big.Int) (InvariantsReport, error) {
+	report := InvariantsReport{Height: s.height}
+	if s.height <= 1 {
+		return report, nil
+	}
+
+	checks := make([]Invariant, len(invariants))
+	for i, invariant := range invariants {
+		checks[i] = invariant.constructor()
+	}
+
+	err := s.ExportStream(0, func(kind string, value interface{}) error {
+		for _, check := range checks {
+			check.Add(kind, value)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return report, err
+	}
+
+	ctx := InvariantContext{
+		Height:       s.height,
+		GenesisAlloc: genesisAlloc,
+	}
+
+	for i, check := range checks {
+		for _, violation := range check.Check(ctx) {
+			violation.Invariant = invariants[i].name
+			report.Violations = append(report.Violations, violation)
+		}
+	}
+
+	return report, nil
+}
+
+// GenesisAlloc returns amount of base coin allocated in genesis app state
+func GenesisAlloc(appState types.AppState) *big.Int {
+	alloc := big.NewInt(0)
+	for _, account := range appState.Accounts {
+		for _, bal := range account.Balance {
+			if bal.Coin.IsBaseCoin() {
+				alloc.Add(alloc, bal.Value)
+			}
+		}
+	}
+
+	for _, candidate := range appState.Candidates {
+		for _, stake := range candidate.Stakes {
+			if stake.Coin.IsBaseCoin() {
+				alloc.Add(alloc, stake.Value)
+			}
+		}
+	}
+
+	for _, coin := range appState.Coins {
+		alloc.Add(alloc, coin.ReserveBalance)
+	}
+
+	for _, ff := range appState.FrozenFunds {
+		if ff.Coin.IsBaseCoin() {
+			alloc.Add(alloc, ff.Value)
+		}
+	}
+
+	return alloc
+}
+
+// bipSupplyInvariant checks that base coin held in the state is equal to the emitted amount
+type bipSupplyInvariant struct {
+	totals       *Totals
+	totalSlashed *big.Int
+}
+
+func (i *bipSupplyInvariant) Add(kind string, value interface{}) {
+	if kind == ExportTotalSlashed {
+		i.totalSlashed = value.(*big.Int)
+		return
+	}
+
+	i.totals.AddRecord(kind, value)
+}
+
+func (i *bipSupplyInvariant) Check(ctx InvariantContext) []Violation {
+	predicted := big.NewInt(0)
+	predicted.Add(predicted, rewards.BeforeGenesis)
+	for h := uint64(1); h < ctx.Height; h++ {
+		predicted.Add(predicted, rewards.GetRewardForBlock(h))
+	}
+	if i.totalSlashed != nil {
+		predicted.Sub(predicted, i.totalSlashed)
+	}
+	predicted.Add(predicted, ctx.GenesisAlloc)
+
+	if ctx.Height >= upgrades.UpgradeBlock0 {
+		d, _ := big.NewInt(0).SetString("35703071844419651412692", 10)
+		predicted.Sub(predicted, d)
+	}
+
+	delta := big.NewInt(0).Sub(predicted, i.totals.BaseCoin)
+	if delta.Sign() == 0 {
+		return nil
+	}
+
+	return []Violation{{
+		Coin: types.GetBaseCoin(),
+		Message: fmt.Sprintf("total supply of base coin is expected to be %s, got %s (delta %s)",
+			predicted, i.totals.BaseCoin, delta),
+		Critical: delta.Cmp(criticalBipSupplyDelta) == 1,
+	}}
+}
+
+// coinVolumeInvariant checks that volume of every custom coin is equal to the sum of its balances, stakes and
+// frozen funds, and that no negative amounts or amounts of unknown coins are held
+type coinVolumeInvariant struct {
+	volumes    map[types.CoinSymbol]*big.Int
+	owned      map[types.CoinSymbol]*coinHoldings
+	violations []Violation
+}
+
+// coinHoldings is a breakdown of owned amount of a coin. First holder is kept to report holders of unknown coins.
+type coinHoldings struct {
+	balances    *big.Int
+	stakes      *big.Int
+	frozenFunds *big.Int
+
+	address *types.Address
+	pubKey  types.Pubkey
+}
+
+func newCoinVolumeInvariant() Invariant {
+	return &coinVolumeInvariant{
+		volumes: map[types.CoinSymbol]*big.Int{},
+		owned:   map[types.CoinSymbol]*coinHoldings{},
+	}
+}
+
+func (i *coinVolumeInvariant) Add(kind string, value interface{}) {
+	switch kind {
+	case ExportAccounts:
+		account := value.(types.Account)
+		for _, balance := range account.Balance {
+			i.addOwned(balance.Coin, balance.Value, account.Address, nil, "balance")
+		}
+	case ExportCoins:
+		coin := value.(types.Coin)
+		i.volumes[coin.Symbol] = coin.Volume
+	case ExportFrozenFunds:
+		frozenFund := value.(types.FrozenFund)
+		i.addOwned(frozenFund.Coin, frozenFund.Value, frozenFund.Address, frozenFund.CandidateKey, "frozen fund")
+	case ExportCandidates:
+		candidate := value.(types.Candidate)
+		for _, stake := range candidate.Stakes {
+			i.addOwned(stake.Coin, stake.Value, stake.Owner, candidate.PubKey, "stake")
+		}
+	}
+}
+
+func (i *coinVolumeInvariant) addOwned(coin types.CoinSymbol, value *big.Int, address types.Address, pubKey types.Pubkey, source string) {
+	if value.Sign() < 0 {
+		i.violations = append(i.violations, Violation{
+			Coin:    coin,
+			Address: &address,
+			PubKey:  pubKey,
+			Message: fmt.Sprintf("negative %s %s", source, value),
+		})
+	}
+
+	if coin.IsBaseCoin() {
+		return
+	}
+
+	holdings := i.owned[coin]
+	if holdings == nil {
+		holdings = &coinHoldings{
+			balances:    big.NewInt(0),
+			stakes:      big.NewInt(0),
+			frozenFunds: big.NewInt(0),
+			address:     &address,
+			pubKey:      pubKey,
+		}
+		i.owned[coin] = holdings
+	}
+
+	switch source {
+	case "balance":
+		holdings.balances.Add(holdings.balances, value)
+	case "stake":
+		holdings.stakes.Add(holdings.stakes, value)
+	default:
+		holdings.frozenFunds.Add(holdings.frozenFunds, value)
+	}
+}
+
+func (i *coinVolumeInvariant) Check(ctx InvariantContext) []Violation {
+	violations := i.violations
+
+	coins := make([]types.CoinSymbol, 0, len(i.volumes))
+	for coin := range i.volumes {
+		coins = append(coins, coin)
+	}
+	for coin := range i.owned {
+		if _, exists := i.volumes[coin]; !exists {
+			coins = append(coins, coin)
+		}
+	}
+
+	sort.Slice(coins, func(a, b int) bool {
+		return coins[a].String() < coins[b].String()
+	})
+
+	for _, coin := range coins {
+		volume, holdings := i.volumes[coin], i.owned[coin]
+		if volume == nil {
+			violations = append(violations, Violation{
+				Coin:    coin,
+				Address: holdings.address,
+				PubKey:  holdings.pubKey,
+				Message: "coin does not exist but is held",
+			})
+			continue
+		}
+
+		owned := big.NewInt(0)
+		if holdings != nil {
+			owned.Add(owned, holdings.balances)
+			owned.Add(owned, holdings.stakes)
+			owned.Add(owned, holdings.frozenFunds)
+		}
+
+		if volume.Cmp(owned) != 0 {
+			message := fmt.Sprintf("volume %s does not match total owned %s", volume, owned)
+			if holdings != nil {
+				message += fmt.Sprintf(" (balances %s, stakes %s, frozen funds %s)",
+					holdings.balances, holdings.stakes, holdings.frozenFunds)
+			}
+
+			violations = append(violations, Violation{
+				Coin:    coin,
+				Message: message,
+			})
+		}
+	}
+
+	return violations
+}
+
+// coinReserveInvariant checks that reserves and volumes of custom coins are not negative
+type coinReserveInvariant struct {
+	violations []Violation
+}
+
+func (i *coinReserveInvariant) Add(kind string, value interface{}) {
+	if kind != ExportCoins {
+		return
+	}
+
+	coin := value.(types.Coin)
+	if coin.ReserveBalance.Sign() < 0 {
+		i.violations = append(i.violations, Violation{
+			Coin:    coin.Symbol,
+			Message: fmt.Sprintf("negative reserve %s", coin.ReserveBalance),
+		})
+	}
+
+	if coin.Volume.Sign() < 0 {
+		i.violations = append(i.violations, Violation{
+			Coin:    coin.Symbol,
+			Message: fmt.Sprintf("negative volume %s", coin.Volume),
+		})
+	}
+}
+
+func (i *coinReserveInvariant) Check(ctx InvariantContext) []Violation {
+	return i.violations
+}
+
+// validatorSetInvariant checks that numbers of candidates and validators are within limits of the height and
+// every validator is a unique candidate
+type validatorSetInvariant struct {
+	candidates map[string]bool
+	seen       map[string]bool
+	validators []types.Pubkey
+	violations []Violation
+}
+
+func (i *validatorSetInvariant) Add(kind string, value interface{}) {
+	switch kind {
+	case ExportCandidates:
+		i.candidates[string(value.(types.Candidate).PubKey)] = true
+	case ExportValidators:
+		pubKey := value.(types.Validator).PubKey
+		if i.seen[string(pubKey)] {
+			i.violations = append(i.violations, Violation{
+				PubKey:  pubKey,
+				Message: "validator is duplicated",
+			})
+			return
+		}
+
+		i.seen[string(pubKey)] = true
+		i.validators = append(i.validators, pubKey)
+	}
+}
+
+func (i *validatorSetInvariant) Check(ctx InvariantContext) []Violation {
+	violations := i.violations
+
+	if count, limit := len(i.candidates), validators.GetCandidatesCountForBlock(ctx.Height); count > limit {
+		violations = append(violations, Violation{
+			Message: fmt.Sprintf("too many candidates. Expected %d, got %d", limit, count),
+		})
+	}
+
+	if count, limit := len(i.validators), validators.GetValidatorsCountForBlock(ctx.Height); count > limit {
+		violations = append(violations, Violation{
+			Message: fmt.Sprintf("too many validators. Expected %d, got %d", limit, count),
+		})
+	}
+
+	for _, pubKey := range i.validators {
+		if !i.candidates[string(pubKey)] {
+			violations = append(violations, Violation{
+				PubKey:  pubKey,
+				Message: "validator is not a candidate",
+			})
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/tendermint/tendermint/libs/db"
+	"math/big"
+	"testing"
+)
+
+func getViolations(t *testing.T, s *StateDB, invariant string) []Violation {
+	report, err := s.CheckInvariants(big.NewInt(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var violations []Violation
+	for _, violation := range report.Violations {
+		if violation.Invariant == invariant {
+			violations = append(violations, violation)
+		}
+	}
+
+	return violations
+}
+
+func TestStateDB_CheckInvariants(t *testing.T) {
+	memDB := db.NewMemDB()
+	s, err := New(0, memDB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	symbol := types.StrToCoinSymbol("TEST")
+	s.CreateCoin(symbol, "TEST NAME", big.NewInt(30), 50, big.NewInt(1000))
+	s.AddBalance(types.Address{1}, symbol, big.NewInt(30))
+
+	if _, _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = New(1, memDB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, invariant := range []string{InvariantCoinVolume, InvariantCoinReserve, InvariantValidatorSet} {
+		if violations := getViolations(t, s, invariant); len(violations) != 0 {
+			t.Fatalf("Invariant %s should not be violated, got %v", invariant, violations)
+		}
+	}
+
+	unknown := types.StrToCoinSymbol("UNKNOWN")
+	s.AddBalance(types.Address{2}, symbol, big.NewInt(5))
+	s.AddBalance(types.Address{3}, unknown, big.NewInt(5))
+
+	if _, _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	violations := getViolations(t, s, InvariantCoinVolume)
+	if len(violations) != 2 {
+		t.Fatalf("Expected 2 violations of coin volume, got %v", violations)
+	}
+
+	if violations[0].Coin != symbol {
+		t.Fatalf("Expected violation of %s coin, got %s", symbol, violations[0].Coin)
+	}
+
+	if violations[1].Coin != unknown || violations[1].Address == nil || *violations[1].Address != (types.Address{3}) {
+		t.Fatalf("Expected violation of %s coin held by %s, got %v", unknown, types.Address{3}, violations[1])
+	}
+}
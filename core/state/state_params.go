@@ -0,0 +1,199 @@
+package state
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/dao"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"io"
+	"math/big"
+)
+
+// Names of parameters which can be changed by governance proposals
+const (
+	ParamUnbondPeriod                 = "unbond_period"
+	ParamValidatorMaxAbsentTimes      = "validator_max_absent_times"
+	ParamAbsentSlashPercent           = "absent_slash_percent"
+	ParamByzantineSlashPercent        = "byzantine_slash_percent"
+	ParamDAOCommission                = "dao_commission"
+	ParamValidatorsUpdatePeriod       = "validators_update_period"
+	ParamCommissionChangeNoticePeriod = "commission_change_notice_period"
+	ParamCommissionMultiplier         = "commission_multiplier"
+	ParamProposalVotingPeriod         = "proposal_voting_period"
+	ParamProposalQuorum               = "proposal_quorum"
+	ParamProposalThreshold            = "proposal_threshold"
+	ParamJailPeriod                   = "jail_period"
+	ParamEvidenceMaxAge               = "evidence_max_age"
+	ParamMaxValidatorStakeShare       = "max_validator_stake_share"
+	ParamMinSelfStake                 = "min_self_stake"
+)
+
+// Bounds of commission multiplier: commissions can be changed at most 10 times from default ones
+var (
+	minCommissionMultiplier = big.NewInt(10e13)
+	maxCommissionMultiplier = big.NewInt(10e15)
+)
+
+// maxMinSelfStake is an upper bound of minimal self-stake, so the parameter can't switch off all candidates
+var maxMinSelfStake = helpers.BipToPip(big.NewInt(1000000))
+
+// stateParams represents consensus parameters which are being modified.
+type stateParams struct {
+	data Params
+
+	onDirty func()
+}
+
+// Params holds consensus parameters which can be changed by governance
+type Params struct {
+	UnbondPeriod                 uint64
+	ValidatorMaxAbsentTimes      uint64
+	AbsentSlashPercent           uint64
+	ByzantineSlashPercent        uint64
+	DAOCommission                uint64
+	ValidatorsUpdatePeriod       uint64
+	CommissionChangeNoticePeriod uint64
+	CommissionMultiplier         *big.Int
+	ProposalVotingPeriod         uint64
+	ProposalQuorum               uint64
+	ProposalThreshold            uint64
+	JailPeriod                   uint64
+	EvidenceMaxAge               uint64
+	MaxValidatorStakeShare       uint64 // applies to delegations only, see StateDB.IsStakeShareExceeded
+	MinSelfStake                 *big.Int
+}
+
+// DefaultParams returns parameters which were hardcoded before governance was introduced
+func DefaultParams() Params {
+	return Params{
+		UnbondPeriod:                 UnbondPeriod,
+		ValidatorMaxAbsentTimes:      ValidatorMaxAbsentTimes,
+		AbsentSlashPercent:           1,
+		ByzantineSlashPercent:        5,
+		DAOCommission:                uint64(dao.Commission),
+		ValidatorsUpdatePeriod:       120,
+		CommissionChangeNoticePeriod: CommissionChangeNoticePeriod,
+		CommissionMultiplier:         big.NewInt(10e14),
+		ProposalVotingPeriod:         120960, // ~7 days
+		ProposalQuorum:               40,
+		ProposalThreshold:            50,
+		JailPeriod:                   17280, // ~1 day
+		EvidenceMaxAge:               0,     // evidences of any age are punished
+		MaxValidatorStakeShare:       0,     // share of stake is not limited
+		MinSelfStake:                 big.NewInt(0),
+	}
+}
+
+// Set changes parameter with given name. Returns an error if parameter is unknown or value is out of bounds.
+func (p *Params) Set(name string, value *big.Int) error {
+	if value == nil || value.Sign() < 0 {
+		return fmt.Errorf("value of %s should not be negative", name)
+	}
+
+	if name == ParamCommissionMultiplier {
+		if value.Cmp(minCommissionMultiplier) < 0 || value.Cmp(maxCommissionMultiplier) > 0 {
+			return fmt.Errorf("value of %s should be between %s and %s", name, minCommissionMultiplier,
+				maxCommissionMultiplier)
+		}
+
+		p.CommissionMultiplier = big.NewInt(0).Set(value)
+		return nil
+	}
+
+	if name == ParamMinSelfStake {
+		if value.Cmp(maxMinSelfStake) > 0 {
+			return fmt.Errorf("value of %s should be at most %s", name, maxMinSelfStake)
+		}
+
+		p.MinSelfStake = big.NewInt(0).Set(value)
+		return nil
+	}
+
+	if !value.IsUint64() {
+		return fmt.Errorf("value of %s is too large", name)
+	}
+
+	v := value.Uint64()
+
+	var field *uint64
+	var min, max uint64
+
+	switch name {
+	case ParamUnbondPeriod:
+		// unbond period can't be decreased: unbonds and redelegations are looked up within current period
+		field, min, max = &p.UnbondPeriod, p.UnbondPeriod, 10*UnbondPeriod
+	case ParamValidatorMaxAbsentTimes:
+		// validator is punished when absent times exceed the value, which can't happen if it is the whole window
+		field, min, max = &p.ValidatorMaxAbsentTimes, 1, ValidatorMaxAbsentWindow-1
+	case ParamAbsentSlashPercent:
+		field, min, max = &p.AbsentSlashPercent, 0, 100
+	case ParamByzantineSlashPercent:
+		field, min, max = &p.ByzantineSlashPercent, 0, 100
+	case ParamDAOCommission:
+		field, min, max = &p.DAOCommission, 0, 50
+	case ParamValidatorsUpdatePeriod:
+		field, min, max = &p.ValidatorsUpdatePeriod, 12, 17280
+	case ParamCommissionChangeNoticePeriod:
+		field, min, max = &p.CommissionChangeNoticePeriod, 0, 10*UnbondPeriod
+	case ParamProposalVotingPeriod:
+		field, min, max = &p.ProposalVotingPeriod, 120, 10*UnbondPeriod
+	case ParamProposalQuorum:
+		field, min, max = &p.ProposalQuorum, 1, 100
+	case ParamProposalThreshold:
+		field, min, max = &p.ProposalThreshold, 50, 100
+	case ParamJailPeriod:
+		field, min, max = &p.JailPeriod, 0, 10*UnbondPeriod
+	case ParamEvidenceMaxAge:
+		field, min, max = &p.EvidenceMaxAge, 0, UnbondPeriod
+	case ParamMaxValidatorStakeShare:
+		field, min, max = &p.MaxValidatorStakeShare, 0, 100
+	default:
+		return fmt.Errorf("unknown parameter %s", name)
+	}
+
+	if v < min || v > max {
+		return fmt.Errorf("value of %s should be between %d and %d", name, min, max)
+	}
+
+	*field = v
+	return nil
+}
+
+// newParams creates a state params object.
+func newParams(data Params, onDirty func()) *stateParams {
+	return &stateParams{
+		data:    data,
+		onDirty: onDirty,
+	}
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (p *stateParams) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, p.data)
+}
+
+func (p *stateParams) Data() Params {
+	return p.data
+}
+
+// GetParams returns current consensus parameters. Defaults are returned until parameters are changed by governance.
+func (s *StateDB) GetParams() Params {
+	params := s.getStateParams()
+	if params == nil {
+		return DefaultParams()
+	}
+
+	return params.Data()
+}
+
+func (s *StateDB) SetParams(data Params) {
+	params := s.getStateParams()
+	if params == nil {
+		params = newParams(data, s.MarkStateParamsDirty)
+	}
+
+	params.data = data
+
+	s.setStateParams(params)
+	s.MarkStateParamsDirty()
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestParams_SetUnbondPeriod(t *testing.T) {
+	params := DefaultParams()
+
+	if err := params.Set(ParamUnbondPeriod, big.NewInt(UnbondPeriod-1)); err == nil {
+		t.Fatal("Unbond period should not be decreased")
+	}
+
+	if err := params.Set(ParamUnbondPeriod, big.NewInt(2*UnbondPeriod)); err != nil {
+		t.Fatal(err)
+	}
+
+	if params.UnbondPeriod != 2*UnbondPeriod {
+		t.Fatalf("Unbond period should be %d, got %d", 2*UnbondPeriod, params.UnbondPeriod)
+	}
+}
+
+func TestParams_SetCommissionMultiplier(t *testing.T) {
+	params := DefaultParams()
+
+	for _, value := range []*big.Int{big.NewInt(0), big.NewInt(10e13 - 1), big.NewInt(10e15 + 1)} {
+		if err := params.Set(ParamCommissionMultiplier, value); err == nil {
+			t.Fatalf("Commission multiplier %s should be out of bounds", value)
+		}
+	}
+
+	if err := params.Set(ParamCommissionMultiplier, big.NewInt(10e15)); err != nil {
+		t.Fatal(err)
+	}
+
+	if params.CommissionMultiplier.Cmp(big.NewInt(10e15)) != 0 {
+		t.Fatalf("Commission multiplier should be %d, got %s", int64(10e15), params.CommissionMultiplier)
+	}
+}
+
+func TestParams_SetMinSelfStake(t *testing.T) {
+	params := DefaultParams()
+
+	tooLarge := big.NewInt(0).Add(maxMinSelfStake, big.NewInt(1))
+	if err := params.Set(ParamMinSelfStake, tooLarge); err == nil {
+		t.Fatalf("Min self-stake %s should be out of bounds", tooLarge)
+	}
+
+	if err := params.Set(ParamMinSelfStake, maxMinSelfStake); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParams_SetValidatorMaxAbsentTimes(t *testing.T) {
+	params := DefaultParams()
+
+	for _, value := range []int64{0, ValidatorMaxAbsentWindow} {
+		if err := params.Set(ParamValidatorMaxAbsentTimes, big.NewInt(value)); err == nil {
+			t.Fatalf("Validator max absent times %d should be out of bounds", value)
+		}
+	}
+
+	if err := params.Set(ParamValidatorMaxAbsentTimes, big.NewInt(ValidatorMaxAbsentWindow-1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if params.ValidatorMaxAbsentTimes != ValidatorMaxAbsentWindow-1 {
+		t.Fatalf("Validator max absent times should be %d, got %d", ValidatorMaxAbsentWindow-1,
+			params.ValidatorMaxAbsentTimes)
+	}
+}
@@ -0,0 +1,299 @@
+package state
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/eventsdb/events"
+	"github.com/MinterTeam/minter-go-node/log"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"io"
+	"math/big"
+)
+
+// Options of governance votes
+const (
+	VoteYes     byte = 1
+	VoteNo      byte = 2
+	VoteAbstain byte = 3
+)
+
+// stateProposals represents active governance proposals which are being modified.
+type stateProposals struct {
+	data Proposals
+
+	onDirty func()
+}
+
+type Proposals struct {
+	LastID uint64
+	List   []Proposal
+}
+
+// Proposal is a request to change consensus parameters and/or to schedule a software upgrade.
+// It is tallied at VotingEndHeight.
+type Proposal struct {
+	ID              uint64
+	Proposer        types.Address
+	Changes         []ParamChange
+	SubmitHeight    uint64
+	VotingEndHeight uint64
+	Votes           []Vote
+	Upgrade         []UpgradePlan `rlp:"tail"`
+}
+
+type ParamChange struct {
+	Name  string
+	Value *big.Int
+}
+
+type Vote struct {
+	Voter  types.Address
+	Option byte
+}
+
+// GetVote returns option voted by given address or 0 if address did not vote
+func (p Proposal) GetVote(voter types.Address) byte {
+	for _, vote := range p.Votes {
+		if vote.Voter == voter {
+			return vote.Option
+		}
+	}
+
+	return 0
+}
+
+// newProposals creates a state proposals object.
+func newProposals(data Proposals, onDirty func()) *stateProposals {
+	return &stateProposals{
+		data:    data,
+		onDirty: onDirty,
+	}
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (p *stateProposals) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, p.data)
+}
+
+func (p *stateProposals) Data() Proposals {
+	return p.data
+}
+
+// GetProposals returns active governance proposals
+func (s *StateDB) GetProposals() []Proposal {
+	proposals := s.getStateProposals()
+	if proposals == nil {
+		return nil
+	}
+
+	return proposals.data.List
+}
+
+func (s *StateDB) GetProposal(id uint64) *Proposal {
+	proposals := s.getStateProposals()
+	if proposals == nil {
+		return nil
+	}
+
+	for i := range proposals.data.List {
+		if proposals.data.List[i].ID == id {
+			return &proposals.data.List[i]
+		}
+	}
+
+	return nil
+}
+
+// AddProposal creates new proposal which voting ends after voting period. Returns ID of created proposal.
+func (s *StateDB) AddProposal(proposer types.Address, changes []ParamChange, upgrade []UpgradePlan, height uint64) uint64 {
+	proposals := s.getStateProposals()
+	if proposals == nil {
+		proposals = newProposals(Proposals{}, s.MarkStateProposalsDirty)
+	}
+
+	proposals.data.LastID++
+	proposals.data.List = append(proposals.data.List, Proposal{
+		ID:              proposals.data.LastID,
+		Proposer:        proposer,
+		Changes:         changes,
+		SubmitHeight:    height,
+		VotingEndHeight: height + s.GetParams().ProposalVotingPeriod,
+		Upgrade:         upgrade,
+	})
+
+	s.setStateProposals(proposals)
+	s.MarkStateProposalsDirty()
+
+	return proposals.data.LastID
+}
+
+// VoteProposal sets voter's option. Previous vote of the same voter is replaced.
+func (s *StateDB) VoteProposal(id uint64, voter types.Address, option byte) {
+	proposal := s.GetProposal(id)
+	if proposal == nil {
+		return
+	}
+
+	for i := range proposal.Votes {
+		if proposal.Votes[i].Voter == voter {
+			proposal.Votes[i].Option = option
+			s.MarkStateProposalsDirty()
+			return
+		}
+	}
+
+	proposal.Votes = append(proposal.Votes, Vote{
+		Voter:  voter,
+		Option: option,
+	})
+	s.MarkStateProposalsDirty()
+}
+
+// TallyProposals finishes proposals which voting period is over at given height and applies changes of accepted ones.
+//
+// Voting power is a bip value of stakes in current validators. Delegators who did not vote inherit the vote
+// of validator's owner.
+func (s *StateDB) TallyProposals(height uint64) {
+	proposals := s.getStateProposals()
+	if proposals == nil {
+		return
+	}
+
+	var active []Proposal
+	for _, proposal := range proposals.data.List {
+		if proposal.VotingEndHeight > height {
+			active = append(active, proposal)
+			continue
+		}
+
+		yes, no, abstain, total := s.tallyProposal(proposal)
+
+		params := s.GetParams()
+		voted := big.NewInt(0).Add(yes, no)
+		voted.Add(voted, abstain)
+
+		quorum := big.NewInt(0).Mul(total, big.NewInt(int64(params.ProposalQuorum)))
+		threshold := big.NewInt(0).Add(yes, no)
+		threshold.Mul(threshold, big.NewInt(int64(params.ProposalThreshold)))
+
+		passed := total.Sign() > 0 &&
+			big.NewInt(0).Mul(voted, big.NewInt(100)).Cmp(quorum) >= 0 &&
+			big.NewInt(0).Mul(yes, big.NewInt(100)).Cmp(threshold) > 0
+
+		if passed {
+			for _, change := range proposal.Changes {
+				if err := params.Set(change.Name, change.Value); err != nil {
+					log.Error("Cannot apply proposal", "id", proposal.ID, "err", err)
+					passed = false
+					break
+				}
+			}
+		}
+
+		if passed {
+			for _, plan := range proposal.Upgrade {
+				if plan.Height <= height || s.IsUpgradeApplied(plan.Name) {
+					log.Error("Cannot schedule upgrade", "id", proposal.ID, "name", plan.Name, "height", plan.Height)
+					passed = false
+					break
+				}
+			}
+		}
+
+		if passed {
+			if len(proposal.Changes) > 0 {
+				s.SetParams(params)
+			}
+
+			for _, plan := range proposal.Upgrade {
+				s.SetUpgradePlan(plan)
+			}
+		}
+
+		eventsdb.GetCurrent().AddEvent(height, events.ProposalResultEvent{
+			ProposalID: proposal.ID,
+			Passed:     passed,
+			Yes:        yes.Bytes(),
+			No:         no.Bytes(),
+			Abstain:    abstain.Bytes(),
+		})
+	}
+
+	if len(active) == len(proposals.data.List) {
+		return
+	}
+
+	proposals.data.List = active
+	s.setStateProposals(proposals)
+	s.MarkStateProposalsDirty()
+}
+
+func (s *StateDB) tallyProposal(proposal Proposal) (yes, no, abstain, total *big.Int) {
+	yes, no, abstain, total = big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0)
+
+	vals := s.getStateValidators()
+	if vals == nil {
+		return
+	}
+
+	votes := make(map[types.Address]byte, len(proposal.Votes))
+	for _, vote := range proposal.Votes {
+		votes[vote.Voter] = vote.Option
+	}
+
+	for _, val := range vals.data {
+		candidate := s.GetStateCandidate(val.PubKey)
+		if candidate == nil {
+			continue
+		}
+
+		validatorVote := votes[candidate.OwnerAddress]
+
+		for _, stake := range candidate.Stakes {
+			total.Add(total, stake.BipValue)
+
+			vote := votes[stake.Owner]
+			if vote == 0 {
+				vote = validatorVote
+			}
+
+			switch vote {
+			case VoteYes:
+				yes.Add(yes, stake.BipValue)
+			case VoteNo:
+				no.Add(no, stake.BipValue)
+			case VoteAbstain:
+				abstain.Add(abstain, stake.BipValue)
+			}
+		}
+	}
+
+	return
+}
+
+// IsVoter returns whether given address is an owner of a validator or has a stake in one
+func (s *StateDB) IsVoter(addr types.Address) bool {
+	vals := s.getStateValidators()
+	if vals == nil {
+		return false
+	}
+
+	for _, val := range vals.data {
+		candidate := s.GetStateCandidate(val.PubKey)
+		if candidate == nil {
+			continue
+		}
+
+		if candidate.OwnerAddress == addr {
+			return true
+		}
+
+		for _, stake := range candidate.Stakes {
+			if stake.Owner == addr {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,52 @@
+package state
+
+import (
+	"io"
+
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rlp"
+)
+
+// statePubKeyChanges represents pending candidates' public key changes which are being modified.
+type statePubKeyChanges struct {
+	data PubKeyChanges
+	db   *StateDB
+
+	onDirty func() // Callback method to mark a state object newly dirty
+}
+
+type PubKeyChanges []PubKeyChange
+
+// PubKeyChange is a replacement of candidate's consensus public key requested by candidate's owner.
+// It takes effect at the next update of validator set.
+type PubKeyChange struct {
+	PubKey    types.Pubkey
+	NewPubKey types.Pubkey
+}
+
+func (c PubKeyChange) String() string {
+	return fmt.Sprintf("Public key change of %s to %s", c.PubKey, c.NewPubKey)
+}
+
+// newPubKeyChanges creates a state object.
+func newPubKeyChanges(db *StateDB, data PubKeyChanges, onDirty func()) *statePubKeyChanges {
+	changes := &statePubKeyChanges{
+		db:      db,
+		data:    data,
+		onDirty: onDirty,
+	}
+
+	changes.onDirty()
+
+	return changes
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (c *statePubKeyChanges) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, c.data)
+}
+
+func (c *statePubKeyChanges) Data() PubKeyChanges {
+	return c.data
+}
@@ -0,0 +1,279 @@
+package state
+
+import (
+	"bytes"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/eventsdb/events"
+	"github.com/MinterTeam/minter-go-node/formula"
+	"io"
+
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"math/big"
+)
+
+// stateRedelegation represents a list of redelegations which are being modified.
+// Redelegated stake remains slashable for source candidate's misbehaviour until blockHeight.
+type stateRedelegation struct {
+	blockHeight uint64
+	deleted     bool
+	data        Redelegations
+	db          *StateDB
+
+	onDirty func(blockHeight uint64)
+}
+
+// Redelegation is a part of stake which was redelegated from FromCandidateKey. The part stays on ToCandidateKey
+// until it is unbonded, then it is kept in frozen funds of UnbondHeight.
+type Redelegation struct {
+	Address          types.Address
+	FromCandidateKey []byte
+	ToCandidateKey   []byte
+	Coin             types.CoinSymbol
+	Value            *big.Int
+	UnbondHeight     uint64
+}
+
+type Redelegations struct {
+	List []Redelegation
+}
+
+func (r Redelegations) String() string {
+	return fmt.Sprintf("Redelegations (%d items)", len(r.List))
+}
+
+// newRedelegation creates a state redelegation.
+func newRedelegation(db *StateDB, blockHeight uint64, data Redelegations,
+	onDirty func(blockHeight uint64)) *stateRedelegation {
+	redelegation := &stateRedelegation{
+		db:          db,
+		blockHeight: blockHeight,
+		data:        data,
+		onDirty:     onDirty,
+	}
+
+	redelegation.onDirty(redelegation.blockHeight)
+
+	return redelegation
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (c *stateRedelegation) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, c.data)
+}
+
+func (c *stateRedelegation) Delete() {
+	c.deleted = true
+	c.onDirty(c.blockHeight)
+}
+
+func (c *stateRedelegation) AddRedelegation(address types.Address, fromCandidateKey []byte, toCandidateKey []byte,
+	coin types.CoinSymbol, value *big.Int) {
+	c.addRedelegation(Redelegation{
+		Address:          address,
+		FromCandidateKey: fromCandidateKey,
+		ToCandidateKey:   toCandidateKey,
+		Coin:             coin,
+		Value:            big.NewInt(0).Set(value),
+	})
+}
+
+func (c *stateRedelegation) addRedelegation(redelegation Redelegation) {
+	c.data.List = append(c.data.List, redelegation)
+	c.onDirty(c.blockHeight)
+}
+
+// replaceCandidateKey moves redelegations from and to candidate with retired public key to its new key
+func (c *stateRedelegation) replaceCandidateKey(pubKey []byte, newPubKey []byte) {
+	changed := false
+	for i := range c.data.List {
+		item := &c.data.List[i]
+		if bytes.Equal(item.FromCandidateKey, pubKey) {
+			item.FromCandidateKey = newPubKey
+			changed = true
+		}
+
+		if bytes.Equal(item.ToCandidateKey, pubKey) {
+			item.ToCandidateKey = newPubKey
+			changed = true
+		}
+	}
+
+	if changed {
+		c.onDirty(c.blockHeight)
+	}
+}
+
+// carry moves redelegated parts of stake of address on candidate pubKey along with remaining value leaving the
+// candidate. Carried value is subtracted from remaining. Parts redelegated back to their source candidate are
+// dropped, as they are slashed as regular stakes of the candidate.
+func (c *stateRedelegation) carry(address types.Address, pubKey []byte, coin types.CoinSymbol, remaining *big.Int,
+	newPubKey []byte, unbondHeight uint64) {
+	toCandidateKey := pubKey
+	if newPubKey != nil {
+		toCandidateKey = newPubKey
+	}
+
+	var carried []Redelegation
+	changed := false
+	for i := range c.data.List {
+		if remaining.Sign() == 0 {
+			break
+		}
+
+		item := &c.data.List[i]
+		if item.Address != address || item.Coin != coin || item.UnbondHeight != 0 ||
+			!bytes.Equal(item.ToCandidateKey, pubKey) || item.Value.Sign() <= 0 {
+			continue
+		}
+
+		amount := big.NewInt(0).Set(item.Value)
+		if remaining.Cmp(amount) < 0 {
+			amount.Set(remaining)
+		}
+		remaining.Sub(remaining, amount)
+		item.Value.Sub(item.Value, amount)
+		changed = true
+
+		if bytes.Equal(item.FromCandidateKey, toCandidateKey) {
+			continue
+		}
+
+		carried = append(carried, Redelegation{
+			Address:          item.Address,
+			FromCandidateKey: item.FromCandidateKey,
+			ToCandidateKey:   toCandidateKey,
+			Coin:             item.Coin,
+			Value:            amount,
+			UnbondHeight:     unbondHeight,
+		})
+	}
+
+	if !changed {
+		return
+	}
+
+	// exhausted parts are removed
+	list := make([]Redelegation, 0, len(c.data.List)+len(carried))
+	for _, item := range c.data.List {
+		if item.Value.Sign() > 0 {
+			list = append(list, item)
+		}
+	}
+	c.data.List = append(list, carried...)
+
+	c.onDirty(c.blockHeight)
+}
+
+// punish stakes redelegated from candidate with given address (used in byzantine validator's punishment)
+func (c *stateRedelegation) PunishRedelegations(context *StateDB, candidateAddress [20]byte, fromBlock uint64, evidenceHeight uint64) {
+	edb := eventsdb.GetCurrent()
+
+	for i := range c.data.List {
+		item := &c.data.List[i]
+
+		var pubkey ed25519.PubKeyEd25519
+		copy(pubkey[:], item.FromCandidateKey)
+
+		var address [20]byte
+		copy(address[:], pubkey.Address().Bytes())
+
+		if candidateAddress != address {
+			continue
+		}
+
+		// redelegated value is either still staked or already unbonded to frozen funds
+		var available *big.Int
+		var sub func(value *big.Int)
+		if item.UnbondHeight == 0 {
+			candidate := context.GetStateCandidate(item.ToCandidateKey)
+			if candidate == nil {
+				continue
+			}
+
+			stake := candidate.GetStakeOfAddress(item.Address, item.Coin)
+			if stake == nil {
+				continue
+			}
+
+			available = stake.Value
+			sub = func(value *big.Int) {
+				stake.Value.Sub(stake.Value, value)
+				context.MarkStateCandidateDirty()
+			}
+		} else {
+			frozenFunds := context.GetStateFrozenFunds(item.UnbondHeight)
+			if frozenFunds == nil {
+				continue
+			}
+
+			available = frozenFunds.amountOf(item.Address, item.ToCandidateKey, item.Coin)
+			sub = func(value *big.Int) {
+				frozenFunds.subAmount(item.Address, item.ToCandidateKey, item.Coin, value)
+			}
+		}
+
+		// only redelegated part of the stake is slashable
+		slashable := big.NewInt(0).Set(item.Value)
+		if available.Cmp(slashable) < 0 {
+			slashable.Set(available)
+		}
+
+		newValue := big.NewInt(0).Set(slashable)
+		newValue.Mul(newValue, big.NewInt(int64(100-context.GetParams().ByzantineSlashPercent)))
+		newValue.Div(newValue, big.NewInt(100))
+
+		slashed := big.NewInt(0).Set(slashable)
+		slashed.Sub(slashed, newValue)
+
+		if slashed.Cmp(types.Big0) == 0 {
+			continue
+		}
+
+		if !item.Coin.IsBaseCoin() {
+			coin := context.GetStateCoin(item.Coin).Data()
+			ret := formula.CalculateSaleReturn(coin.Volume, coin.ReserveBalance, coin.Crr, slashed)
+
+			context.SubCoinVolume(coin.Symbol, slashed)
+			context.SubCoinReserve(coin.Symbol, ret)
+
+			context.AddTotalSlashed(ret)
+		} else {
+			context.AddTotalSlashed(slashed)
+		}
+
+		edb.AddEvent(fromBlock, events.SlashEvent{
+			Address:         item.Address,
+			Amount:          slashed.Bytes(),
+			Coin:            item.Coin,
+			ValidatorPubKey: item.FromCandidateKey,
+			Reason:          events.SlashReasonByzantine,
+			EvidenceHeight:  evidenceHeight,
+		})
+
+		sub(slashed)
+		item.Value.Sub(item.Value, slashed)
+
+		context.SanitizeCoin(item.Coin)
+	}
+
+	c.onDirty(c.blockHeight)
+}
+
+//
+// Attribute accessors
+//
+
+func (c *stateRedelegation) BlockHeight() uint64 {
+	return c.blockHeight
+}
+
+func (c *stateRedelegation) List() []Redelegation {
+	return c.data.List
+}
+
+func (c *stateRedelegation) Data() Redelegations {
+	return c.data
+}
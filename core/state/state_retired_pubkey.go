@@ -0,0 +1,108 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/log"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+// RetiredPubKey is a public key which candidate replaced with a new one. Retired keys can never be used again.
+// Evidences against a retired key are resolved to its candidate during evidence window after the replacement.
+type RetiredPubKey struct {
+	PubKey    types.Pubkey
+	NewPubKey types.Pubkey
+	Height    uint64 // zero for keys retired before genesis
+}
+
+// tmAddress returns tendermint address of consensus public key
+func tmAddress(pubkey types.Pubkey) [20]byte {
+	var key ed25519.PubKeyEd25519
+	copy(key[:], pubkey)
+
+	var address [20]byte
+	copy(address[:], key.Address().Bytes())
+
+	return address
+}
+
+// evidenceWindow returns number of blocks during which evidences against retired public key are expected. Tendermint
+// drops evidences older than unbond period, so they are not punished anyway.
+func (s *StateDB) evidenceWindow() uint64 {
+	params := s.GetParams()
+	if params.EvidenceMaxAge > 0 && params.EvidenceMaxAge < params.UnbondPeriod {
+		return params.EvidenceMaxAge
+	}
+
+	return params.UnbondPeriod
+}
+
+// getRetiredPubKey returns retired public key with given tendermint address or nil if there is no such key
+func (s *StateDB) getRetiredPubKey(address [20]byte) *RetiredPubKey {
+	_, enc := s.iavl.Get(append(retiredPubKeyPrefix, address[:]...))
+	if len(enc) == 0 {
+		return nil
+	}
+
+	var retired RetiredPubKey
+	if err := rlp.DecodeBytes(enc, &retired); err != nil {
+		log.Error("Failed to decode retired public key", "address", fmt.Sprintf("%x", address), "err", err)
+		return nil
+	}
+
+	return &retired
+}
+
+func (s *StateDB) setRetiredPubKey(retired RetiredPubKey) {
+	data, err := rlp.EncodeToBytes(retired)
+	if err != nil {
+		panic(fmt.Errorf("can't encode retired public key %s: %v", retired.PubKey, err))
+	}
+
+	address := tmAddress(retired.PubKey)
+	s.iavl.Set(append(retiredPubKeyPrefix, address[:]...), data)
+}
+
+// IsPubKeyRetired checks if given public key was replaced by its candidate
+func (s *StateDB) IsPubKeyRetired(pubkey types.Pubkey) bool {
+	retired := s.getRetiredPubKey(tmAddress(pubkey))
+	return retired != nil && bytes.Equal(retired.PubKey, pubkey)
+}
+
+// ResolveEvidenceAddress returns tendermint address of the candidate which is responsible for evidence against given
+// address. Address of a public key retired within evidence window is resolved to the current address of its
+// candidate, other addresses are returned as is.
+func (s *StateDB) ResolveEvidenceAddress(address [20]byte) [20]byte {
+	retired := s.getRetiredPubKey(address)
+	if retired == nil || retired.Height == 0 || s.height > retired.Height+s.evidenceWindow() {
+		return address
+	}
+
+	return tmAddress(retired.NewPubKey)
+}
+
+// IsPubKeyChangeLocked checks if candidate with given public key adopted it within evidence window. Such candidate
+// can't change its key until evidences against the previous key can no longer arrive, so the previous key is
+// always resolved to a key of existing candidate.
+func (s *StateDB) IsPubKeyChangeLocked(pubkey types.Pubkey) bool {
+	window := s.evidenceWindow()
+
+	locked := false
+	end := append([]byte{}, retiredPubKeyPrefix...)
+	end[len(end)-1]++
+	// public keys are changed rarely, so retired keys are not indexed by new ones
+	s.iavl.IterateRange(retiredPubKeyPrefix, end, true, func(key []byte, value []byte) bool {
+		var retired RetiredPubKey
+		if err := rlp.DecodeBytes(value, &retired); err != nil {
+			log.Error("Failed to decode retired public key", "key", fmt.Sprintf("%x", key), "err", err)
+			return false
+		}
+
+		locked = retired.Height != 0 && bytes.Equal(retired.NewPubKey, pubkey) && s.height <= retired.Height+window
+		return locked
+	})
+
+	return locked
+}
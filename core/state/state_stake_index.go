@@ -0,0 +1,151 @@
+package state
+
+import (
+	"bytes"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"math/big"
+	"sort"
+)
+
+// Stakes are stored within candidates only. To find stakes of an address without scanning all
+// candidates, stakes are indexed by owners:
+//
+//	stakesIndexPrefix + owner + candidate key + coin -> owner has a non-zero stake in coin
+//
+// Index is written directly to the tree by Delegate, SubStake, ClearStakes, ClearCandidates,
+// slashing and change of candidate's public key. Existing state is indexed by MigrateStakesIndex
+// at an upgrade height, before that lookups fall back to scanning.
+
+// AddressStake is a stake of an address together with candidate it is delegated to
+type AddressStake struct {
+	PubKey types.Pubkey
+	Coin   types.CoinSymbol
+	Value  *big.Int
+	// BipValue is recalculated together with total stakes of candidates
+	BipValue *big.Int
+}
+
+// GetStakesOfAddress returns all non-zero stakes of given address ordered by candidate key and coin
+func (s *StateDB) GetStakesOfAddress(owner types.Address) []AddressStake {
+	if !s.isStakesIndexed() {
+		return s.scanStakesOfAddress(owner)
+	}
+
+	var result []AddressStake
+	prefix := getStakesIndexPrefix(owner)
+	s.iavl.IterateRange(prefix, prefixEnd(prefix), true, func(key []byte, value []byte) bool {
+		pubkey := types.Pubkey(key[len(prefix) : len(key)-types.CoinSymbolLength])
+		var coin types.CoinSymbol
+		copy(coin[:], key[len(key)-types.CoinSymbolLength:])
+
+		candidate := s.GetStateCandidate(pubkey)
+		if candidate == nil {
+			return false
+		}
+
+		if stake := candidate.GetStakeOfAddress(owner, coin); stake != nil && stake.Value.Cmp(types.Big0) != 0 {
+			result = append(result, newAddressStake(candidate.PubKey, stake))
+		}
+
+		return false
+	})
+
+	return result
+}
+
+// scanStakesOfAddress looks through stakes of all candidates for stakes of given address
+func (s *StateDB) scanStakesOfAddress(owner types.Address) []AddressStake {
+	candidates := s.getStateCandidates()
+	if candidates == nil {
+		return nil
+	}
+
+	var result []AddressStake
+	for _, candidate := range candidates.data {
+		for i := range candidate.Stakes {
+			stake := &candidate.Stakes[i]
+			if stake.Owner == owner && stake.Value.Cmp(types.Big0) != 0 {
+				result = append(result, newAddressStake(candidate.PubKey, stake))
+			}
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if c := bytes.Compare(result[i].PubKey, result[j].PubKey); c != 0 {
+			return c == -1
+		}
+
+		return bytes.Compare(result[i].Coin[:], result[j].Coin[:]) == -1
+	})
+
+	return result
+}
+
+func newAddressStake(pubkey types.Pubkey, stake *Stake) AddressStake {
+	return AddressStake{
+		PubKey:   pubkey,
+		Coin:     stake.Coin,
+		Value:    big.NewInt(0).Set(stake.Value),
+		BipValue: big.NewInt(0).Set(stake.BipValue),
+	}
+}
+
+// MigrateStakesIndex indexes all non-zero stakes and enables maintaining of the index
+func (s *StateDB) MigrateStakesIndex() {
+	if s.isStakesIndexed() {
+		return
+	}
+
+	if candidates := s.getStateCandidates(); candidates != nil {
+		for _, candidate := range candidates.data {
+			for _, stake := range candidate.Stakes {
+				if stake.Value.Cmp(types.Big0) != 0 {
+					s.iavl.Set(getStakesIndexKey(stake.Owner, candidate.PubKey, stake.Coin), indexValue)
+				}
+			}
+		}
+	}
+
+	s.iavl.Set(stakesIndexedKey, indexValue)
+}
+
+func (s *StateDB) isStakesIndexed() bool {
+	_, enc := s.iavl.Get(stakesIndexedKey)
+	return len(enc) != 0
+}
+
+// addToStakeIndex adds stake to the index if stakes are indexed
+func (s *StateDB) addToStakeIndex(owner types.Address, pubkey types.Pubkey, coin types.CoinSymbol) {
+	if !s.isStakesIndexed() {
+		return
+	}
+
+	s.iavl.Set(getStakesIndexKey(owner, pubkey, coin), indexValue)
+}
+
+// removeFromStakeIndex removes stake from the index if stakes are indexed
+func (s *StateDB) removeFromStakeIndex(owner types.Address, pubkey types.Pubkey, coin types.CoinSymbol) {
+	if !s.isStakesIndexed() {
+		return
+	}
+
+	s.iavl.Remove(getStakesIndexKey(owner, pubkey, coin))
+}
+
+// moveStakesIndex moves index of candidate's stakes to the new public key of candidate
+func (s *StateDB) moveStakesIndex(candidate *Candidate, oldPubKey types.Pubkey) {
+	for _, stake := range candidate.Stakes {
+		s.removeFromStakeIndex(stake.Owner, oldPubKey, stake.Coin)
+		if stake.Value.Cmp(types.Big0) != 0 {
+			s.addToStakeIndex(stake.Owner, candidate.PubKey, stake.Coin)
+		}
+	}
+}
+
+func getStakesIndexPrefix(owner types.Address) []byte {
+	return append(append([]byte{}, stakesIndexPrefix...), owner[:]...)
+}
+
+func getStakesIndexKey(owner types.Address, pubkey types.Pubkey, coin types.CoinSymbol) []byte {
+	return append(append(getStakesIndexPrefix(owner), pubkey...), coin[:]...)
+}
@@ -0,0 +1,74 @@
+package state
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"math/big"
+	"testing"
+)
+
+func TestStateDB_GetStakesOfAddress(t *testing.T) {
+	s := getState()
+
+	owner := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+	pubkeys := [][]byte{createTestCandidate(s), createTestCandidate(s)}
+
+	// lookup before migration scans all candidates
+	s.Delegate(owner, pubkeys[0], types.GetBaseCoin(), helpers.BipToPip(big.NewInt(10)))
+	if stakes := s.GetStakesOfAddress(owner); len(stakes) != 1 {
+		t.Fatalf("Address should have 1 stake, got %d", len(stakes))
+	}
+
+	s.MigrateStakesIndex()
+	if !s.isStakesIndexed() {
+		t.Fatalf("Stakes should be indexed after migration")
+	}
+
+	// index should be updated by Delegate
+	s.Delegate(owner, pubkeys[1], types.GetBaseCoin(), helpers.BipToPip(big.NewInt(5)))
+	stakes := s.GetStakesOfAddress(owner)
+	if len(stakes) != 2 {
+		t.Fatalf("Address should have 2 stakes, got %d", len(stakes))
+	}
+
+	for _, stake := range stakes {
+		expected := helpers.BipToPip(big.NewInt(10))
+		if types.Pubkey(pubkeys[1]).Compare(stake.PubKey) == 0 {
+			expected = helpers.BipToPip(big.NewInt(5))
+		}
+
+		if stake.Value.Cmp(expected) != 0 {
+			t.Fatalf("Wrong stake %v", stake)
+		}
+	}
+
+	if stakes[0].PubKey.Compare(stakes[1].PubKey) != -1 {
+		t.Fatalf("Stakes should be ordered by candidate key")
+	}
+
+	s.SubStake(owner, pubkeys[0], types.GetBaseCoin(), helpers.BipToPip(big.NewInt(10)))
+	s.GetOrNewStateFrozenFunds(100).AddFund(owner, pubkeys[0], types.GetBaseCoin(), helpers.BipToPip(big.NewInt(10)))
+
+	stakes = s.GetStakesOfAddress(owner)
+	if len(stakes) != 1 || types.Pubkey(pubkeys[1]).Compare(stakes[0].PubKey) != 0 {
+		t.Fatalf("Unbonded stake should be removed from index")
+	}
+
+	if _, _, err := s.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, enc := s.iavl.Get(getStakesIndexKey(owner, pubkeys[0], types.GetBaseCoin())); len(enc) != 0 {
+		t.Fatalf("Unbonded stake should be removed from stored index")
+	}
+
+	frozenFunds := s.GetFrozenFundsOfAddress(owner)
+	if len(frozenFunds) != 1 || frozenFunds[0].Height != 100 {
+		t.Fatalf("Address should have 1 frozen fund at height 100, got %v", frozenFunds)
+	}
+
+	// self stakes of candidates are indexed by migration
+	if stakes := s.GetStakesOfAddress(types.Address{}); len(stakes) != 2 {
+		t.Fatalf("Candidates' owner should have 2 stakes, got %d", len(stakes))
+	}
+}
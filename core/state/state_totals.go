@@ -0,0 +1,97 @@
+package state
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"math/big"
+	"sort"
+)
+
+// Totals accumulates amounts of coins held in the state: base coin held in balances, stakes, frozen funds,
+// reserves and accumulated rewards, volumes of custom coins and amounts of custom coins owned in balances,
+// stakes and frozen funds.
+type Totals struct {
+	BaseCoin *big.Int
+	Volumes  map[types.CoinSymbol]*big.Int
+	Owned    map[types.CoinSymbol]*big.Int
+}
+
+func NewTotals() *Totals {
+	return &Totals{
+		BaseCoin: big.NewInt(0),
+		Volumes:  map[types.CoinSymbol]*big.Int{},
+		Owned:    map[types.CoinSymbol]*big.Int{},
+	}
+}
+
+// AddOwned adds amount of coin owned in a balance, a stake or a frozen fund
+func (t *Totals) AddOwned(coin types.CoinSymbol, value *big.Int) {
+	if coin.IsBaseCoin() {
+		t.BaseCoin.Add(t.BaseCoin, value)
+		return
+	}
+
+	if t.Owned[coin] == nil {
+		t.Owned[coin] = big.NewInt(0)
+	}
+	t.Owned[coin].Add(t.Owned[coin], value)
+}
+
+// AddCoin adds volume and reserve of a custom coin
+func (t *Totals) AddCoin(coin types.CoinSymbol, volume *big.Int, reserve *big.Int) {
+	t.BaseCoin.Add(t.BaseCoin, reserve)
+	t.Volumes[coin] = volume
+}
+
+// AddRecord adds amounts of a record passed by ExportStream
+func (t *Totals) AddRecord(kind string, value interface{}) {
+	switch kind {
+	case ExportAccounts:
+		for _, balance := range value.(types.Account).Balance {
+			t.AddOwned(balance.Coin, balance.Value)
+		}
+	case ExportCoins:
+		coin := value.(types.Coin)
+		t.AddCoin(coin.Symbol, coin.Volume, coin.ReserveBalance)
+	case ExportFrozenFunds:
+		frozenFund := value.(types.FrozenFund)
+		t.AddOwned(frozenFund.Coin, frozenFund.Value)
+	case ExportCandidates:
+		for _, stake := range value.(types.Candidate).Stakes {
+			t.AddOwned(stake.Coin, stake.Value)
+		}
+	case ExportValidators:
+		t.BaseCoin.Add(t.BaseCoin, value.(types.Validator).AccumReward)
+	}
+}
+
+// CheckVolumes checks that volume of every custom coin is equal to its owned amount
+func (t *Totals) CheckVolumes() error {
+	for _, coin := range t.Coins() {
+		owned := t.Owned[coin]
+		if owned == nil {
+			owned = big.NewInt(0)
+		}
+
+		if volume := t.Volumes[coin]; volume.Cmp(owned) != 0 {
+			return fmt.Errorf("smth wrong with %s coin in blockchain. Total supply (%s) does not match total owned (%s)",
+				coin, volume, owned)
+		}
+	}
+
+	return nil
+}
+
+// Coins returns custom coins of the totals in alphabetical order
+func (t *Totals) Coins() []types.CoinSymbol {
+	coins := make([]types.CoinSymbol, 0, len(t.Volumes))
+	for coin := range t.Volumes {
+		coins = append(coins, coin)
+	}
+
+	sort.Slice(coins, func(i, j int) bool {
+		return coins[i].String() < coins[j].String()
+	})
+
+	return coins
+}
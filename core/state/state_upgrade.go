@@ -0,0 +1,114 @@
+package state
+
+import (
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"io"
+)
+
+// stateUpgrade represents scheduled and applied software upgrades which are being modified.
+type stateUpgrade struct {
+	data Upgrade
+
+	onDirty func()
+}
+
+type Upgrade struct {
+	Plan    []UpgradePlan
+	Applied []AppliedUpgrade
+}
+
+// UpgradePlan is a software upgrade approved by governance. Node halts at Height if it does not know the upgrade.
+type UpgradePlan struct {
+	Name   string
+	Height uint64
+	Info   string
+}
+
+type AppliedUpgrade struct {
+	Name   string
+	Height uint64
+}
+
+// newUpgrade creates a state upgrade object.
+func newUpgrade(data Upgrade, onDirty func()) *stateUpgrade {
+	return &stateUpgrade{
+		data:    data,
+		onDirty: onDirty,
+	}
+}
+
+// EncodeRLP implements rlp.Encoder.
+func (u *stateUpgrade) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, u.data)
+}
+
+func (u *stateUpgrade) Data() Upgrade {
+	return u.data
+}
+
+// GetUpgradePlan returns scheduled software upgrade or nil if there is no one
+func (s *StateDB) GetUpgradePlan() *UpgradePlan {
+	upgrade := s.getStateUpgrade()
+	if upgrade == nil || len(upgrade.data.Plan) == 0 {
+		return nil
+	}
+
+	plan := upgrade.data.Plan[0]
+	return &plan
+}
+
+// SetUpgradePlan schedules software upgrade. Previously scheduled upgrade is replaced.
+func (s *StateDB) SetUpgradePlan(plan UpgradePlan) {
+	upgrade := s.getOrNewStateUpgrade()
+	upgrade.data.Plan = []UpgradePlan{plan}
+
+	s.setStateUpgrade(upgrade)
+	s.MarkStateUpgradeDirty()
+}
+
+// ApplyUpgradePlan marks scheduled software upgrade as applied at given height
+func (s *StateDB) ApplyUpgradePlan(height uint64) {
+	upgrade := s.getStateUpgrade()
+	if upgrade == nil || len(upgrade.data.Plan) == 0 {
+		return
+	}
+
+	upgrade.data.Applied = append(upgrade.data.Applied, AppliedUpgrade{
+		Name:   upgrade.data.Plan[0].Name,
+		Height: height,
+	})
+	upgrade.data.Plan = nil
+
+	s.setStateUpgrade(upgrade)
+	s.MarkStateUpgradeDirty()
+}
+
+// GetAppliedUpgrades returns software upgrades which were applied on this chain
+func (s *StateDB) GetAppliedUpgrades() []AppliedUpgrade {
+	upgrade := s.getStateUpgrade()
+	if upgrade == nil {
+		return nil
+	}
+
+	return upgrade.data.Applied
+}
+
+// IsUpgradeApplied checks if software upgrade with given name was applied on this chain
+func (s *StateDB) IsUpgradeApplied(name string) bool {
+	for _, applied := range s.GetAppliedUpgrades() {
+		if applied.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *StateDB) getOrNewStateUpgrade() *stateUpgrade {
+	upgrade := s.getStateUpgrade()
+	if upgrade == nil {
+		upgrade = newUpgrade(Upgrade{}, s.MarkStateUpgradeDirty)
+	}
+
+	return upgrade
+}
@@ -3,22 +3,16 @@ package state
 import (
 	"encoding/hex"
 	"fmt"
-	"github.com/MinterTeam/go-amino"
-	"github.com/MinterTeam/minter-go-node/cmd/utils"
-	"github.com/MinterTeam/minter-go-node/core/rewards"
 	"github.com/MinterTeam/minter-go-node/core/types"
 	"github.com/MinterTeam/minter-go-node/core/validators"
 	"github.com/MinterTeam/minter-go-node/eventsdb"
 	"github.com/MinterTeam/minter-go-node/eventsdb/events"
 	"github.com/MinterTeam/minter-go-node/formula"
-	"github.com/MinterTeam/minter-go-node/helpers"
 	"github.com/MinterTeam/minter-go-node/log"
 	"github.com/MinterTeam/minter-go-node/rlp"
 	"github.com/MinterTeam/minter-go-node/upgrades"
 	dbm "github.com/tendermint/tendermint/libs/db"
-	tmTypes "github.com/tendermint/tendermint/types"
 	"math/big"
-	"os"
 	"sync"
 
 	"bytes"
@@ -36,14 +30,37 @@ var (
 	ValidatorMaxAbsentWindow = 24
 	ValidatorMaxAbsentTimes  = 12
 
-	addressPrefix     = []byte("a")
-	coinPrefix        = []byte("c")
-	frozenFundsPrefix = []byte("f")
-	usedCheckPrefix   = []byte("u")
-	candidatesKey     = []byte("t")
-	validatorsKey     = []byte("v")
-	maxGasKey         = []byte("g")
-	totalSlashedKey   = []byte("s")
+	// CommissionChangeNoticePeriod is a number of blocks after which candidate's commission change takes effect
+	CommissionChangeNoticePeriod uint64 = UnbondPeriod
+
+	addressPrefix          = []byte("a")
+	coinPrefix             = []byte("c")
+	frozenFundsPrefix      = []byte("f")
+	usedCheckPrefix        = []byte("u")
+	candidatesKey          = []byte("t")
+	validatorsKey          = []byte("v")
+	maxGasKey              = []byte("g")
+	totalSlashedKey        = []byte("s")
+	commissionsKey         = []byte("m")
+	redelegationsPrefix    = []byte("r")
+	accountSettingsPrefix  = []byte("o")
+	candidateProfilePrefix = []byte("p")
+	candidateJailPrefix    = []byte("j")
+	paramsKey              = []byte("n")
+	proposalsKey           = []byte("q")
+	upgradeKey             = []byte("w")
+	pubKeyChangesKey       = []byte("k")
+	candidatesIndexKey     = []byte("i")
+	candidatePrefix        = []byte("e")
+	stakePrefix            = []byte("b")
+	retiredPubKeyPrefix    = []byte("l")
+
+	frozenFundsAddressPrefix   = []byte("x")
+	frozenFundsCandidatePrefix = []byte("y")
+	frozenFundsIndexedKey      = []byte("h")
+
+	stakesIndexPrefix = []byte("d")
+	stakesIndexedKey  = []byte("z")
 )
 
 type StateDB struct {
@@ -62,16 +79,46 @@ type StateDB struct {
 	stateFrozenFunds      map[uint64]*stateFrozenFund
 	stateFrozenFundsDirty map[uint64]struct{}
 
-	stateCandidates      *stateCandidates
-	stateCandidatesDirty bool
+	stateRedelegations      map[uint64]*stateRedelegation
+	stateRedelegationsDirty map[uint64]struct{}
+
+	stateAccountSettings      map[types.Address]*stateAccountSettings
+	stateAccountSettingsDirty map[types.Address]struct{}
+
+	stateCandidateProfiles      map[string]*stateCandidateProfile
+	stateCandidateProfilesDirty map[string]struct{}
+
+	stateCandidateJails      map[string]*stateCandidateJail
+	stateCandidateJailsDirty map[string]struct{}
+
+	stateCandidates          *stateCandidates
+	stateCandidatesDirty     bool
+	stateCandidatesDirtyAll  bool
+	stateCandidatesDirtyKeys map[string]struct{}
 
 	stateValidators      *stateValidators
 	stateValidatorsDirty bool
 
+	stateCommissionChanges      *stateCommissionChanges
+	stateCommissionChangesDirty bool
+
+	statePubKeyChanges      *statePubKeyChanges
+	statePubKeyChangesDirty bool
+
+	stateParams      *stateParams
+	stateParamsDirty bool
+
+	stateProposals      *stateProposals
+	stateProposalsDirty bool
+
+	stateUpgrade      *stateUpgrade
+	stateUpgradeDirty bool
+
 	totalSlashed      *big.Int
 	totalSlashedDirty bool
 
-	stakeCache map[types.CoinSymbol]StakeCache
+	stakeCache  map[types.CoinSymbol]StakeCache
+	stakeTotals map[types.CoinSymbol]*big.Int
 
 	lock             sync.Mutex
 	keepStateHistory bool
@@ -82,6 +129,24 @@ type StakeCache struct {
 	BipValue   *big.Int
 }
 
+// Rollback removes all versions of state above given height. Nothing is changed if state of the height is
+// pruned or its hash differs from the expected one.
+func Rollback(db dbm.DB, height uint64, hash []byte) error {
+	tree := NewMutableTree(db)
+
+	immutable, err := tree.GetImmutableAtHeight(int64(height))
+	if err != nil {
+		return fmt.Errorf("state of height %d is pruned, rollback requires node running with keep_state_history", height)
+	}
+
+	if !bytes.Equal(immutable.Hash(), hash) {
+		return fmt.Errorf("hash of state of height %d is %X, expected %X", height, immutable.Hash(), hash)
+	}
+
+	_, err = tree.LoadVersionForOverwriting(int64(height))
+	return err
+}
+
 func NewForCheck(height uint64, db dbm.DB) (*StateDB, error) {
 	tree := NewMutableTree(db)
 
@@ -91,43 +156,83 @@ func NewForCheck(height uint64, db dbm.DB) (*StateDB, error) {
 	}
 
 	return &StateDB{
-		db:                    db,
-		iavl:                  t,
-		height:                height,
-		stateAccounts:         make(map[types.Address]*stateAccount),
-		stateAccountsDirty:    make(map[types.Address]struct{}),
-		stateCoins:            make(map[types.CoinSymbol]*stateCoin),
-		stateCoinsDirty:       make(map[types.CoinSymbol]struct{}),
-		stateFrozenFunds:      make(map[uint64]*stateFrozenFund),
-		stateFrozenFundsDirty: make(map[uint64]struct{}),
-		stateCandidates:       nil,
-		stateCandidatesDirty:  false,
-		stateValidators:       nil,
-		stateValidatorsDirty:  false,
-		totalSlashed:          nil,
-		totalSlashedDirty:     false,
-		stakeCache:            make(map[types.CoinSymbol]StakeCache),
+		db:                          db,
+		iavl:                        t,
+		height:                      height,
+		stateAccounts:               make(map[types.Address]*stateAccount),
+		stateAccountsDirty:          make(map[types.Address]struct{}),
+		stateCoins:                  make(map[types.CoinSymbol]*stateCoin),
+		stateCoinsDirty:             make(map[types.CoinSymbol]struct{}),
+		stateFrozenFunds:            make(map[uint64]*stateFrozenFund),
+		stateFrozenFundsDirty:       make(map[uint64]struct{}),
+		stateRedelegations:          make(map[uint64]*stateRedelegation),
+		stateRedelegationsDirty:     make(map[uint64]struct{}),
+		stateAccountSettings:        make(map[types.Address]*stateAccountSettings),
+		stateAccountSettingsDirty:   make(map[types.Address]struct{}),
+		stateCandidateProfiles:      make(map[string]*stateCandidateProfile),
+		stateCandidateProfilesDirty: make(map[string]struct{}),
+		stateCandidateJails:         make(map[string]*stateCandidateJail),
+		stateCandidateJailsDirty:    make(map[string]struct{}),
+		stateCandidates:             nil,
+		stateCandidatesDirty:        false,
+		stateCandidatesDirtyAll:     false,
+		stateCandidatesDirtyKeys:    make(map[string]struct{}),
+		stateValidators:             nil,
+		stateValidatorsDirty:        false,
+		stateCommissionChanges:      nil,
+		stateCommissionChangesDirty: false,
+		statePubKeyChanges:          nil,
+		statePubKeyChangesDirty:     false,
+		stateParams:                 nil,
+		stateParamsDirty:            false,
+		stateProposals:              nil,
+		stateProposalsDirty:         false,
+		stateUpgrade:                nil,
+		stateUpgradeDirty:           false,
+		totalSlashed:                nil,
+		totalSlashedDirty:           false,
+		stakeCache:                  make(map[types.CoinSymbol]StakeCache),
 	}, nil
 }
 
 func NewForCheckFromDeliver(s *StateDB) *StateDB {
 	return &StateDB{
-		db:                    s.db,
-		iavl:                  s.iavl.GetImmutable(),
-		height:                s.height,
-		stateAccounts:         make(map[types.Address]*stateAccount),
-		stateAccountsDirty:    make(map[types.Address]struct{}),
-		stateCoins:            make(map[types.CoinSymbol]*stateCoin),
-		stateCoinsDirty:       make(map[types.CoinSymbol]struct{}),
-		stateFrozenFunds:      make(map[uint64]*stateFrozenFund),
-		stateFrozenFundsDirty: make(map[uint64]struct{}),
-		stateCandidates:       nil,
-		stateCandidatesDirty:  false,
-		stateValidators:       nil,
-		stateValidatorsDirty:  false,
-		totalSlashed:          nil,
-		totalSlashedDirty:     false,
-		stakeCache:            make(map[types.CoinSymbol]StakeCache),
+		db:                          s.db,
+		iavl:                        s.iavl.GetImmutable(),
+		height:                      s.height,
+		stateAccounts:               make(map[types.Address]*stateAccount),
+		stateAccountsDirty:          make(map[types.Address]struct{}),
+		stateCoins:                  make(map[types.CoinSymbol]*stateCoin),
+		stateCoinsDirty:             make(map[types.CoinSymbol]struct{}),
+		stateFrozenFunds:            make(map[uint64]*stateFrozenFund),
+		stateFrozenFundsDirty:       make(map[uint64]struct{}),
+		stateRedelegations:          make(map[uint64]*stateRedelegation),
+		stateRedelegationsDirty:     make(map[uint64]struct{}),
+		stateAccountSettings:        make(map[types.Address]*stateAccountSettings),
+		stateAccountSettingsDirty:   make(map[types.Address]struct{}),
+		stateCandidateProfiles:      make(map[string]*stateCandidateProfile),
+		stateCandidateProfilesDirty: make(map[string]struct{}),
+		stateCandidateJails:         make(map[string]*stateCandidateJail),
+		stateCandidateJailsDirty:    make(map[string]struct{}),
+		stateCandidates:             nil,
+		stateCandidatesDirty:        false,
+		stateCandidatesDirtyAll:     false,
+		stateCandidatesDirtyKeys:    make(map[string]struct{}),
+		stateValidators:             nil,
+		stateValidatorsDirty:        false,
+		stateCommissionChanges:      nil,
+		stateCommissionChangesDirty: false,
+		statePubKeyChanges:          nil,
+		statePubKeyChangesDirty:     false,
+		stateParams:                 nil,
+		stateParamsDirty:            false,
+		stateProposals:              nil,
+		stateProposalsDirty:         false,
+		stateUpgrade:                nil,
+		stateUpgradeDirty:           false,
+		totalSlashed:                nil,
+		totalSlashedDirty:           false,
+		stakeCache:                  make(map[types.CoinSymbol]StakeCache),
 	}
 }
 
@@ -141,23 +246,43 @@ func New(height uint64, db dbm.DB, keepState bool) (*StateDB, error) {
 	}
 
 	return &StateDB{
-		db:                    db,
-		height:                height + 1,
-		iavl:                  tree,
-		stateAccounts:         make(map[types.Address]*stateAccount),
-		stateAccountsDirty:    make(map[types.Address]struct{}),
-		stateCoins:            make(map[types.CoinSymbol]*stateCoin),
-		stateCoinsDirty:       make(map[types.CoinSymbol]struct{}),
-		stateFrozenFunds:      make(map[uint64]*stateFrozenFund),
-		stateFrozenFundsDirty: make(map[uint64]struct{}),
-		stateCandidates:       nil,
-		stateCandidatesDirty:  false,
-		stateValidators:       nil,
-		stateValidatorsDirty:  false,
-		totalSlashed:          nil,
-		totalSlashedDirty:     false,
-		stakeCache:            make(map[types.CoinSymbol]StakeCache),
-		keepStateHistory:      keepState,
+		db:                          db,
+		height:                      height + 1,
+		iavl:                        tree,
+		stateAccounts:               make(map[types.Address]*stateAccount),
+		stateAccountsDirty:          make(map[types.Address]struct{}),
+		stateCoins:                  make(map[types.CoinSymbol]*stateCoin),
+		stateCoinsDirty:             make(map[types.CoinSymbol]struct{}),
+		stateFrozenFunds:            make(map[uint64]*stateFrozenFund),
+		stateFrozenFundsDirty:       make(map[uint64]struct{}),
+		stateRedelegations:          make(map[uint64]*stateRedelegation),
+		stateRedelegationsDirty:     make(map[uint64]struct{}),
+		stateAccountSettings:        make(map[types.Address]*stateAccountSettings),
+		stateAccountSettingsDirty:   make(map[types.Address]struct{}),
+		stateCandidateProfiles:      make(map[string]*stateCandidateProfile),
+		stateCandidateProfilesDirty: make(map[string]struct{}),
+		stateCandidateJails:         make(map[string]*stateCandidateJail),
+		stateCandidateJailsDirty:    make(map[string]struct{}),
+		stateCandidates:             nil,
+		stateCandidatesDirty:        false,
+		stateCandidatesDirtyAll:     false,
+		stateCandidatesDirtyKeys:    make(map[string]struct{}),
+		stateValidators:             nil,
+		stateValidatorsDirty:        false,
+		stateCommissionChanges:      nil,
+		stateCommissionChangesDirty: false,
+		statePubKeyChanges:          nil,
+		statePubKeyChangesDirty:     false,
+		stateParams:                 nil,
+		stateParamsDirty:            false,
+		stateProposals:              nil,
+		stateProposalsDirty:         false,
+		stateUpgrade:                nil,
+		stateUpgradeDirty:           false,
+		totalSlashed:                nil,
+		totalSlashedDirty:           false,
+		stakeCache:                  make(map[types.CoinSymbol]StakeCache),
+		keepStateHistory:            keepState,
 	}, nil
 }
 
@@ -168,10 +293,35 @@ func (s *StateDB) Clear() {
 	s.stateCoinsDirty = make(map[types.CoinSymbol]struct{})
 	s.stateFrozenFunds = make(map[uint64]*stateFrozenFund)
 	s.stateFrozenFundsDirty = make(map[uint64]struct{})
-	s.stateCandidates = nil
+	s.stateRedelegations = make(map[uint64]*stateRedelegation)
+	s.stateRedelegationsDirty = make(map[uint64]struct{})
+	s.stateAccountSettings = make(map[types.Address]*stateAccountSettings)
+	s.stateAccountSettingsDirty = make(map[types.Address]struct{})
+	s.stateCandidateProfiles = make(map[string]*stateCandidateProfile)
+	s.stateCandidateProfilesDirty = make(map[string]struct{})
+	s.stateCandidateJails = make(map[string]*stateCandidateJail)
+	s.stateCandidateJailsDirty = make(map[string]struct{})
+	// candidates in split layout are equal to the stored ones after Commit, so they are kept
+	// instead of being reloaded key by key in the next block
+	if s.stateCandidates != nil && !s.stateCandidates.splitStorage {
+		s.stateCandidates = nil
+	}
 	s.stateCandidatesDirty = false
+	s.stateCandidatesDirtyAll = false
+	s.stateCandidatesDirtyKeys = make(map[string]struct{})
+	s.stakeTotals = nil
 	s.stateValidators = nil
 	s.stateValidatorsDirty = false
+	s.stateCommissionChanges = nil
+	s.stateCommissionChangesDirty = false
+	s.statePubKeyChanges = nil
+	s.statePubKeyChangesDirty = false
+	s.stateParams = nil
+	s.stateParamsDirty = false
+	s.stateProposals = nil
+	s.stateProposalsDirty = false
+	s.stateUpgrade = nil
+	s.stateUpgradeDirty = false
 	s.totalSlashed = nil
 	s.totalSlashedDirty = false
 	s.stakeCache = make(map[types.CoinSymbol]StakeCache)
@@ -228,6 +378,26 @@ func (s *StateDB) GetNonce(addr types.Address) uint64 {
 	return 0
 }
 
+// IsCompoundRewards returns whether delegator's rewards are re-delegated automatically
+func (s *StateDB) IsCompoundRewards(addr types.Address) bool {
+	settings := s.getStateAccountSettings(addr)
+	if settings != nil {
+		return settings.CompoundRewards()
+	}
+
+	return false
+}
+
+// GetRewardAddress returns address which receives delegator's rewards
+func (s *StateDB) GetRewardAddress(addr types.Address) types.Address {
+	settings := s.getStateAccountSettings(addr)
+	if settings != nil && settings.RewardAddress() != (types.Address{}) {
+		return settings.RewardAddress()
+	}
+
+	return addr
+}
+
 /*
  * SETTERS
  */
@@ -262,6 +432,18 @@ func (s *StateDB) SetNonce(addr types.Address, nonce uint64) {
 	}
 }
 
+func (s *StateDB) SetCompoundRewards(addr types.Address, compound bool) {
+	s.GetOrNewStateAccountSettings(addr).SetCompoundRewards(compound)
+}
+
+func (s *StateDB) SetRewardAddress(addr types.Address, rewardAddress types.Address) {
+	if rewardAddress == addr {
+		rewardAddress = types.Address{}
+	}
+
+	s.GetOrNewStateAccountSettings(addr).SetRewardAddress(rewardAddress)
+}
+
 //
 // Setting, updating & deleting state object methods
 //
@@ -286,9 +468,52 @@ func (s *StateDB) updateStateFrozenFund(stateFrozenFund *stateFrozenFund) {
 	height := make([]byte, 8)
 	binary.BigEndian.PutUint64(height, uint64(stateFrozenFund.blockHeight))
 
+	s.updateFrozenFundsIndex(blockHeight, stateFrozenFund.data.List)
 	s.iavl.Set(append(frozenFundsPrefix, height...), data)
 }
 
+func (s *StateDB) updateStateAccountSettings(settings *stateAccountSettings) {
+	addr := settings.Address()
+	data, err := rlp.EncodeToBytes(settings)
+	if err != nil {
+		panic(fmt.Errorf("can't encode account settings at %x: %v", addr[:], err))
+	}
+
+	s.iavl.Set(append(accountSettingsPrefix, addr[:]...), data)
+}
+
+func (s *StateDB) updateStateCandidateProfile(profile *stateCandidateProfile) {
+	pubkey := profile.PubKey()
+	data, err := rlp.EncodeToBytes(profile)
+	if err != nil {
+		panic(fmt.Errorf("can't encode candidate profile at %x: %v", pubkey[:], err))
+	}
+
+	s.iavl.Set(append(candidateProfilePrefix, pubkey...), data)
+}
+
+func (s *StateDB) updateStateCandidateJail(jail *stateCandidateJail) {
+	pubkey := jail.PubKey()
+	data, err := rlp.EncodeToBytes(jail)
+	if err != nil {
+		panic(fmt.Errorf("can't encode candidate jail at %x: %v", pubkey[:], err))
+	}
+
+	s.iavl.Set(append(candidateJailPrefix, pubkey...), data)
+}
+
+func (s *StateDB) updateStateRedelegation(stateRedelegation *stateRedelegation) {
+	blockHeight := stateRedelegation.BlockHeight()
+	data, err := rlp.EncodeToBytes(stateRedelegation)
+	if err != nil {
+		panic(fmt.Errorf("can't encode redelegations at %d: %v", blockHeight, err))
+	}
+	height := make([]byte, 8)
+	binary.BigEndian.PutUint64(height, blockHeight)
+
+	s.iavl.Set(append(redelegationsPrefix, height...), data)
+}
+
 func (s *StateDB) updateStateCoin(stateCoin *stateCoin) {
 	symbol := stateCoin.Symbol()
 
@@ -318,6 +543,51 @@ func (s *StateDB) updateStateValidators(validators *stateValidators) {
 	s.iavl.Set(validatorsKey, data)
 }
 
+func (s *StateDB) updateStateCommissionChanges(changes *stateCommissionChanges) {
+	data, err := rlp.EncodeToBytes(changes)
+	if err != nil {
+		panic(fmt.Errorf("can't encode commission changes: %v", err))
+	}
+
+	s.iavl.Set(commissionsKey, data)
+}
+
+func (s *StateDB) updateStatePubKeyChanges(changes *statePubKeyChanges) {
+	data, err := rlp.EncodeToBytes(changes)
+	if err != nil {
+		panic(fmt.Errorf("can't encode pubkey changes: %v", err))
+	}
+
+	s.iavl.Set(pubKeyChangesKey, data)
+}
+
+func (s *StateDB) updateStateParams(params *stateParams) {
+	data, err := rlp.EncodeToBytes(params)
+	if err != nil {
+		panic(fmt.Errorf("can't encode params: %v", err))
+	}
+
+	s.iavl.Set(paramsKey, data)
+}
+
+func (s *StateDB) updateStateProposals(proposals *stateProposals) {
+	data, err := rlp.EncodeToBytes(proposals)
+	if err != nil {
+		panic(fmt.Errorf("can't encode proposals: %v", err))
+	}
+
+	s.iavl.Set(proposalsKey, data)
+}
+
+func (s *StateDB) updateStateUpgrade(upgrade *stateUpgrade) {
+	data, err := rlp.EncodeToBytes(upgrade)
+	if err != nil {
+		panic(fmt.Errorf("can't encode upgrade: %v", err))
+	}
+
+	s.iavl.Set(upgradeKey, data)
+}
+
 func (s *StateDB) updateTotalSlashed(value *big.Int) {
 	data, err := rlp.EncodeToBytes(value)
 	if err != nil {
@@ -347,12 +617,150 @@ func (s *StateDB) deleteStateCoin(stateCoin *stateCoin) {
 // deleteStateObject removes the given object from the state trie.
 func (s *StateDB) deleteFrozenFunds(stateFrozenFund *stateFrozenFund) {
 	stateFrozenFund.deleted = true
+	s.updateFrozenFundsIndex(stateFrozenFund.blockHeight, nil)
+
 	height := make([]byte, 8)
 	binary.BigEndian.PutUint64(height, uint64(stateFrozenFund.blockHeight))
 	key := append(frozenFundsPrefix, height...)
 	s.iavl.Remove(key)
 }
 
+// deleteStateAccountSettings removes the given object from the state trie.
+func (s *StateDB) deleteStateAccountSettings(settings *stateAccountSettings) {
+	addr := settings.Address()
+	s.iavl.Remove(append(accountSettingsPrefix, addr[:]...))
+}
+
+// Retrieve a state account settings by address. Returns nil if not found.
+func (s *StateDB) getStateAccountSettings(addr types.Address) (settings *stateAccountSettings) {
+	// Prefer 'live' objects.
+	if obj := s.stateAccountSettings[addr]; obj != nil {
+		return obj
+	}
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(append(accountSettingsPrefix, addr[:]...))
+	if len(enc) == 0 {
+		return nil
+	}
+	var data AccountSettings
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		log.Error("Failed to decode account settings", "addr", addr, "err", err)
+		return nil
+	}
+	// Insert into the live set.
+	obj := newAccountSettings(addr, data, s.MarkStateAccountSettingsDirty)
+	s.setStateAccountSettings(obj)
+	return obj
+}
+
+// deleteStateCandidateProfile removes the given object from the state trie.
+func (s *StateDB) deleteStateCandidateProfile(profile *stateCandidateProfile) {
+	s.iavl.Remove(append(candidateProfilePrefix, profile.PubKey()...))
+}
+
+// Retrieve a state candidate profile by candidate's public key. Returns nil if not found.
+func (s *StateDB) getStateCandidateProfile(pubkey types.Pubkey) (profile *stateCandidateProfile) {
+	// Prefer 'live' objects.
+	if obj := s.stateCandidateProfiles[string(pubkey)]; obj != nil {
+		if obj.deleted {
+			return nil
+		}
+		return obj
+	}
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(append(candidateProfilePrefix, pubkey...))
+	if len(enc) == 0 {
+		return nil
+	}
+	var data CandidateProfile
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		log.Error("Failed to decode candidate profile", "pubkey", pubkey.String(), "err", err)
+		return nil
+	}
+	// Insert into the live set.
+	obj := newCandidateProfile(pubkey, data, s.MarkStateCandidateProfileDirty)
+	s.setStateCandidateProfile(obj)
+	return obj
+}
+
+// deleteStateCandidateJail removes the given object from the state trie.
+func (s *StateDB) deleteStateCandidateJail(jail *stateCandidateJail) {
+	s.iavl.Remove(append(candidateJailPrefix, jail.PubKey()...))
+}
+
+// Retrieve a state candidate jail by candidate's public key. Returns nil if not found.
+func (s *StateDB) getStateCandidateJail(pubkey types.Pubkey) (jail *stateCandidateJail) {
+	// Prefer 'live' objects.
+	if obj := s.stateCandidateJails[string(pubkey)]; obj != nil {
+		if obj.deleted {
+			return nil
+		}
+		return obj
+	}
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(append(candidateJailPrefix, pubkey...))
+	if len(enc) == 0 {
+		return nil
+	}
+	var data CandidateJail
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		log.Error("Failed to decode candidate jail", "pubkey", pubkey.String(), "err", err)
+		return nil
+	}
+	// Insert into the live set.
+	obj := newCandidateJail(pubkey, data, s.MarkStateCandidateJailDirty)
+	s.setStateCandidateJail(obj)
+	return obj
+}
+
+func (s *StateDB) getOrNewStateCandidateJail(pubkey types.Pubkey) *stateCandidateJail {
+	jail := s.getStateCandidateJail(pubkey)
+	if jail == nil {
+		jail = newCandidateJail(pubkey, CandidateJail{}, s.MarkStateCandidateJailDirty)
+		s.setStateCandidateJail(jail)
+	}
+
+	return jail
+}
+
+// deleteRedelegations removes the given object from the state trie.
+func (s *StateDB) deleteRedelegations(stateRedelegation *stateRedelegation) {
+	stateRedelegation.deleted = true
+	height := make([]byte, 8)
+	binary.BigEndian.PutUint64(height, stateRedelegation.blockHeight)
+	key := append(redelegationsPrefix, height...)
+	s.iavl.Remove(key)
+}
+
+// Retrieve a state redelegations by block height. Returns nil if not found.
+func (s *StateDB) getStateRedelegations(blockHeight uint64) (stateRedelegation *stateRedelegation) {
+	// Prefer 'live' objects.
+	if obj := s.stateRedelegations[blockHeight]; obj != nil {
+		return obj
+	}
+
+	height := make([]byte, 8)
+	binary.BigEndian.PutUint64(height, blockHeight)
+	key := append(redelegationsPrefix, height...)
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(key)
+	if len(enc) == 0 {
+		return nil
+	}
+	var data Redelegations
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		return nil
+	}
+	// Insert into the live set.
+	obj := newRedelegation(s, blockHeight, data, s.MarkStateRedelegationsDirty)
+	s.setStateRedelegations(obj)
+	return obj
+}
+
 // Retrieve a state frozen funds by block height. Returns nil if not found.
 func (s *StateDB) getStateFrozenFunds(blockHeight uint64) (stateFrozenFund *stateFrozenFund) {
 	// Prefer 'live' objects.
@@ -406,8 +814,19 @@ func (s *StateDB) GetStateCandidates() (stateCandidates *stateCandidates) {
 	return s.getStateCandidates()
 }
 
-// Retrieve a state candidates. Returns nil if not found.
+// Retrieve a state candidates with all candidates loaded. Returns nil if not found.
 func (s *StateDB) getStateCandidates() (stateCandidates *stateCandidates) {
+	stateCandidates = s.getStateCandidatesOnDemand()
+	if stateCandidates != nil {
+		stateCandidates.loadAll()
+	}
+
+	return stateCandidates
+}
+
+// getStateCandidatesOnDemand retrieves a state candidates. Candidates of split layout may be not loaded yet, so
+// the result should be accessed with stateCandidates.get. Returns nil if not found.
+func (s *StateDB) getStateCandidatesOnDemand() (stateCandidates *stateCandidates) {
 	// Prefer 'live' objects.
 	if s.stateCandidates != nil {
 		return s.stateCandidates
@@ -416,7 +835,8 @@ func (s *StateDB) getStateCandidates() (stateCandidates *stateCandidates) {
 	// Load the object from the database.
 	_, enc := s.iavl.Get(candidatesKey)
 	if len(enc) == 0 {
-		return nil
+		// candidates may be stored in per-candidate keys
+		return s.loadStateCandidatesSplit()
 	}
 	var data Candidates
 	if err := rlp.DecodeBytes(enc, &data); err != nil {
@@ -454,6 +874,113 @@ func (s *StateDB) getStateValidators() (stateValidators *stateValidators) {
 	return obj
 }
 
+// Retrieve a state commission changes. Returns nil if not found.
+func (s *StateDB) getStateCommissionChanges() (stateCommissionChanges *stateCommissionChanges) {
+	// Prefer 'live' objects.
+	if s.stateCommissionChanges != nil {
+		return s.stateCommissionChanges
+	}
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(commissionsKey)
+	if len(enc) == 0 {
+		return nil
+	}
+	var data CommissionChanges
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		panic(err)
+	}
+	// Insert into the live set.
+	obj := newCommissionChanges(s, data, s.MarkStateCommissionChangesDirty)
+	s.setStateCommissionChanges(obj)
+	return obj
+}
+
+// Retrieve a state pubkey changes. Returns nil if not found.
+func (s *StateDB) getStatePubKeyChanges() (statePubKeyChanges *statePubKeyChanges) {
+	// Prefer 'live' objects.
+	if s.statePubKeyChanges != nil {
+		return s.statePubKeyChanges
+	}
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(pubKeyChangesKey)
+	if len(enc) == 0 {
+		return nil
+	}
+	var data PubKeyChanges
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		panic(err)
+	}
+	// Insert into the live set.
+	obj := newPubKeyChanges(s, data, s.MarkStatePubKeyChangesDirty)
+	s.setStatePubKeyChanges(obj)
+	return obj
+}
+
+func (s *StateDB) getStateParams() (stateParams *stateParams) {
+	// Prefer 'live' objects.
+	if s.stateParams != nil {
+		return s.stateParams
+	}
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(paramsKey)
+	if len(enc) == 0 {
+		return nil
+	}
+	var data Params
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		panic(err)
+	}
+	// Insert into the live set.
+	obj := newParams(data, s.MarkStateParamsDirty)
+	s.setStateParams(obj)
+	return obj
+}
+
+func (s *StateDB) getStateProposals() (stateProposals *stateProposals) {
+	// Prefer 'live' objects.
+	if s.stateProposals != nil {
+		return s.stateProposals
+	}
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(proposalsKey)
+	if len(enc) == 0 {
+		return nil
+	}
+	var data Proposals
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		panic(err)
+	}
+	// Insert into the live set.
+	obj := newProposals(data, s.MarkStateProposalsDirty)
+	s.setStateProposals(obj)
+	return obj
+}
+
+func (s *StateDB) getStateUpgrade() (stateUpgrade *stateUpgrade) {
+	// Prefer 'live' objects.
+	if s.stateUpgrade != nil {
+		return s.stateUpgrade
+	}
+
+	// Load the object from the database.
+	_, enc := s.iavl.Get(upgradeKey)
+	if len(enc) == 0 {
+		return nil
+	}
+	var data Upgrade
+	if err := rlp.DecodeBytes(enc, &data); err != nil {
+		panic(err)
+	}
+	// Insert into the live set.
+	obj := newUpgrade(data, s.MarkStateUpgradeDirty)
+	s.setStateUpgrade(obj)
+	return obj
+}
+
 // Retrieve a state account given my the address. Returns nil if not found.
 func (s *StateDB) getStateAccount(addr types.Address) (stateObject *stateAccount) {
 	// Prefer 'live' objects.
@@ -516,31 +1043,94 @@ func (s *StateDB) setStateFrozenFunds(frozenFund *stateFrozenFund) {
 	s.stateFrozenFunds[frozenFund.BlockHeight()] = frozenFund
 }
 
-func (s *StateDB) setStateCandidates(candidates *stateCandidates) {
+func (s *StateDB) setStateAccountSettings(settings *stateAccountSettings) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.stateCandidates = candidates
+	s.stateAccountSettings[settings.Address()] = settings
 }
 
-func (s *StateDB) SetStateValidators(validators *stateValidators) {
-	s.setStateValidators(validators)
+func (s *StateDB) setStateCandidateProfile(profile *stateCandidateProfile) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateCandidateProfiles[string(profile.PubKey())] = profile
 }
 
-func (s *StateDB) setStateValidators(validators *stateValidators) {
+func (s *StateDB) setStateCandidateJail(jail *stateCandidateJail) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
-	s.stateValidators = validators
+	s.stateCandidateJails[string(jail.PubKey())] = jail
 }
 
-// Retrieve a state object or create a new state object if nil
-func (s *StateDB) GetOrNewStateObject(addr types.Address) *stateAccount {
-	stateObject := s.getStateAccount(addr)
-	if stateObject == nil || stateObject.deleted {
-		stateObject, _ = s.createAccount(addr)
-	}
-	return stateObject
+func (s *StateDB) setStateRedelegations(redelegation *stateRedelegation) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateRedelegations[redelegation.BlockHeight()] = redelegation
+}
+
+func (s *StateDB) setStateCandidates(candidates *stateCandidates) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateCandidates = candidates
+}
+
+func (s *StateDB) setStateCommissionChanges(changes *stateCommissionChanges) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateCommissionChanges = changes
+}
+
+func (s *StateDB) setStatePubKeyChanges(changes *statePubKeyChanges) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.statePubKeyChanges = changes
+}
+
+func (s *StateDB) setStateParams(params *stateParams) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateParams = params
+}
+
+func (s *StateDB) setStateProposals(proposals *stateProposals) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateProposals = proposals
+}
+
+func (s *StateDB) setStateUpgrade(upgrade *stateUpgrade) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateUpgrade = upgrade
+}
+
+func (s *StateDB) SetStateValidators(validators *stateValidators) {
+	s.setStateValidators(validators)
+}
+
+func (s *StateDB) setStateValidators(validators *stateValidators) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateValidators = validators
+}
+
+// Retrieve a state object or create a new state object if nil
+func (s *StateDB) GetOrNewStateObject(addr types.Address) *stateAccount {
+	stateObject := s.getStateAccount(addr)
+	if stateObject == nil || stateObject.deleted {
+		stateObject, _ = s.createAccount(addr)
+	}
+	return stateObject
 }
 
 func (s *StateDB) GetStateFrozenFunds(blockHeight uint64) *stateFrozenFund {
@@ -555,6 +1145,27 @@ func (s *StateDB) GetOrNewStateFrozenFunds(blockHeight uint64) *stateFrozenFund
 	return frozenFund
 }
 
+func (s *StateDB) GetOrNewStateAccountSettings(addr types.Address) *stateAccountSettings {
+	settings := s.getStateAccountSettings(addr)
+	if settings == nil {
+		settings = newAccountSettings(addr, AccountSettings{}, s.MarkStateAccountSettingsDirty)
+		s.setStateAccountSettings(settings)
+	}
+	return settings
+}
+
+func (s *StateDB) GetStateRedelegations(blockHeight uint64) *stateRedelegation {
+	return s.getStateRedelegations(blockHeight)
+}
+
+func (s *StateDB) GetOrNewStateRedelegations(blockHeight uint64) *stateRedelegation {
+	redelegation := s.getStateRedelegations(blockHeight)
+	if redelegation == nil {
+		redelegation, _ = s.createRedelegations(blockHeight)
+	}
+	return redelegation
+}
+
 // MarkStateObjectDirty adds the specified object to the dirty map to avoid costly
 // state object cache iteration to find a handful of modified ones.
 func (s *StateDB) MarkStateObjectDirty(addr types.Address) {
@@ -565,13 +1176,48 @@ func (s *StateDB) MarkStateObjectDirty(addr types.Address) {
 }
 
 func (s *StateDB) MarkStateCandidateDirty() {
+	// the whole index is written, candidates which are not loaded are kept as stored
+	if s.stateCandidates != nil {
+		s.stateCandidates.reindex()
+	}
+
+	s.stateCandidatesDirty = true
+	s.stateCandidatesDirtyAll = true
+	s.stakeTotals = nil
+}
+
+// markCandidateDirty marks a single candidate and its stakes as changed, so only they are
+// written on Commit. Adding or removing candidates requires MarkStateCandidateDirty. Cached totals of stakes
+// are kept, so values of stakes should be changed with Delegate and SubStake.
+func (s *StateDB) markCandidateDirty(pubkey types.Pubkey) {
 	s.stateCandidatesDirty = true
+	s.stateCandidatesDirtyKeys[string(pubkey)] = struct{}{}
 }
 
 func (s *StateDB) MarkStateValidatorsDirty() {
 	s.stateValidatorsDirty = true
 }
 
+func (s *StateDB) MarkStateCommissionChangesDirty() {
+	s.stateCommissionChangesDirty = true
+}
+
+func (s *StateDB) MarkStatePubKeyChangesDirty() {
+	s.statePubKeyChangesDirty = true
+}
+
+func (s *StateDB) MarkStateParamsDirty() {
+	s.stateParamsDirty = true
+}
+
+func (s *StateDB) MarkStateProposalsDirty() {
+	s.stateProposalsDirty = true
+}
+
+func (s *StateDB) MarkStateUpgradeDirty() {
+	s.stateUpgradeDirty = true
+}
+
 func (s *StateDB) MarkStateCoinDirty(symbol types.CoinSymbol) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -586,6 +1232,34 @@ func (s *StateDB) MarkStateFrozenFundsDirty(blockHeight uint64) {
 	s.stateFrozenFundsDirty[blockHeight] = struct{}{}
 }
 
+func (s *StateDB) MarkStateAccountSettingsDirty(addr types.Address) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateAccountSettingsDirty[addr] = struct{}{}
+}
+
+func (s *StateDB) MarkStateCandidateProfileDirty(pubkey types.Pubkey) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateCandidateProfilesDirty[string(pubkey)] = struct{}{}
+}
+
+func (s *StateDB) MarkStateCandidateJailDirty(pubkey types.Pubkey) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateCandidateJailsDirty[string(pubkey)] = struct{}{}
+}
+
+func (s *StateDB) MarkStateRedelegationsDirty(blockHeight uint64) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.stateRedelegationsDirty[blockHeight] = struct{}{}
+}
+
 func (s *StateDB) createAccount(addr types.Address) (newobj, prev *stateAccount) {
 	prev = s.getStateAccount(addr)
 	newobj = newObject(addr, Account{}, s.MarkStateObjectDirty)
@@ -611,6 +1285,13 @@ func (s *StateDB) createFrozenFunds(blockHeight uint64) (newobj, prev *stateFroz
 	return newobj, prev
 }
 
+func (s *StateDB) createRedelegations(blockHeight uint64) (newobj, prev *stateRedelegation) {
+	prev = s.getStateRedelegations(blockHeight)
+	newobj = newRedelegation(s, blockHeight, Redelegations{}, s.MarkStateRedelegationsDirty)
+	s.setStateRedelegations(newobj)
+	return newobj, prev
+}
+
 func (s *StateDB) CreateCoin(
 	symbol types.CoinSymbol,
 	name string,
@@ -736,10 +1417,63 @@ func (s *StateDB) Commit() (root []byte, version int64, err error) {
 		delete(s.stateFrozenFundsDirty, block)
 	}
 
+	// Commit account settings to the trie.
+	for _, addr := range getOrderedObjectsKeys(s.stateAccountSettingsDirty) {
+		settings := s.stateAccountSettings[addr]
+		if settings.empty() {
+			s.deleteStateAccountSettings(settings)
+		} else {
+			s.updateStateAccountSettings(settings)
+		}
+
+		delete(s.stateAccountSettingsDirty, addr)
+	}
+
+	// Commit candidates' profiles to the trie.
+	for _, key := range getOrderedCandidateProfilesKeys(s.stateCandidateProfilesDirty) {
+		profile := s.stateCandidateProfiles[key]
+		if profile.deleted || profile.Data().Size() == 0 {
+			s.deleteStateCandidateProfile(profile)
+		} else {
+			s.updateStateCandidateProfile(profile)
+		}
+
+		delete(s.stateCandidateProfilesDirty, key)
+	}
+
+	for _, key := range getOrderedCandidateProfilesKeys(s.stateCandidateJailsDirty) {
+		jail := s.stateCandidateJails[key]
+		if jail.deleted {
+			s.deleteStateCandidateJail(jail)
+		} else {
+			s.updateStateCandidateJail(jail)
+		}
+
+		delete(s.stateCandidateJailsDirty, key)
+	}
+
+	// Commit redelegations to the trie.
+	for _, block := range getOrderedFrozenFundsKeys(s.stateRedelegationsDirty) {
+		redelegation := s.stateRedelegations[block]
+		if redelegation.deleted {
+			s.deleteRedelegations(redelegation)
+		} else {
+			s.updateStateRedelegation(redelegation)
+		}
+
+		delete(s.stateRedelegationsDirty, block)
+	}
+
 	if s.stateCandidatesDirty {
 		s.clearStateCandidates()
-		s.updateStateCandidates(s.stateCandidates)
+		if s.stateCandidates.splitStorage {
+			s.updateStateCandidatesSplit(s.stateCandidates)
+		} else {
+			s.updateStateCandidates(s.stateCandidates)
+		}
 		s.stateCandidatesDirty = false
+		s.stateCandidatesDirtyAll = false
+		s.stateCandidatesDirtyKeys = make(map[string]struct{})
 	}
 
 	if s.stateValidatorsDirty {
@@ -747,6 +1481,31 @@ func (s *StateDB) Commit() (root []byte, version int64, err error) {
 		s.stateValidatorsDirty = false
 	}
 
+	if s.stateCommissionChangesDirty {
+		s.updateStateCommissionChanges(s.stateCommissionChanges)
+		s.stateCommissionChangesDirty = false
+	}
+
+	if s.statePubKeyChangesDirty {
+		s.updateStatePubKeyChanges(s.statePubKeyChanges)
+		s.statePubKeyChangesDirty = false
+	}
+
+	if s.stateParamsDirty {
+		s.updateStateParams(s.stateParams)
+		s.stateParamsDirty = false
+	}
+
+	if s.stateProposalsDirty {
+		s.updateStateProposals(s.stateProposals)
+		s.stateProposalsDirty = false
+	}
+
+	if s.stateUpgradeDirty {
+		s.updateStateUpgrade(s.stateUpgrade)
+		s.stateUpgradeDirty = false
+	}
+
 	if s.totalSlashedDirty {
 		s.updateTotalSlashed(s.totalSlashed)
 		s.totalSlashedDirty = false
@@ -794,6 +1553,17 @@ func getOrderedCoinsKeys(objects map[types.CoinSymbol]struct{}) []types.CoinSymb
 	return keys
 }
 
+func getOrderedCandidateProfilesKeys(objects map[string]struct{}) []string {
+	keys := make([]string, 0, len(objects))
+	for k := range objects {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
 func getOrderedFrozenFundsKeys(objects map[uint64]struct{}) []uint64 {
 	keys := make([]uint64, 0, len(objects))
 	for k := range objects {
@@ -818,35 +1588,16 @@ func (s *StateDB) CoinExists(symbol types.CoinSymbol) bool {
 }
 
 func (s *StateDB) CandidateExists(key types.Pubkey) bool {
-
-	stateCandidates := s.getStateCandidates()
-
-	if stateCandidates == nil {
-		return false
-	}
-
-	for _, candidate := range stateCandidates.data {
-		if bytes.Equal(candidate.PubKey, key) {
-			return true
-		}
-	}
-
-	return false
+	return s.GetStateCandidate(key) != nil
 }
 
 func (s *StateDB) GetStateCandidate(key types.Pubkey) *Candidate {
-	stateCandidates := s.getStateCandidates()
+	stateCandidates := s.getStateCandidatesOnDemand()
 	if stateCandidates == nil {
 		return nil
 	}
 
-	for i, candidate := range stateCandidates.data {
-		if bytes.Equal(candidate.PubKey, key) {
-			return &(stateCandidates.data[i])
-		}
-	}
-
-	return nil
+	return stateCandidates.get(key)
 }
 
 func (s *StateDB) GetStateCandidateByTmAddress(address [20]byte) *Candidate {
@@ -970,7 +1721,7 @@ func (s *StateDB) PayRewards() {
 
 			// pay commission to DAO
 			DAOReward := big.NewInt(0).Set(totalReward)
-			DAOReward.Mul(DAOReward, big.NewInt(int64(dao.Commission)))
+			DAOReward.Mul(DAOReward, big.NewInt(int64(s.GetParams().DAOCommission)))
 			DAOReward.Div(DAOReward, big.NewInt(100))
 			s.AddBalance(dao.Address, types.GetBaseCoin(), DAOReward)
 			remainder.Sub(remainder, DAOReward)
@@ -1013,6 +1764,10 @@ func (s *StateDB) PayRewards() {
 
 			candidate := s.GetStateCandidate(validator.PubKey)
 
+			// rewards of delegators with enabled compounding are delegated after all stakes are processed
+			compounded := map[types.Address]*big.Int{}
+			var compoundedOwners []types.Address
+
 			// pay rewards
 			for j := range candidate.Stakes {
 				stake := candidate.Stakes[j]
@@ -1030,17 +1785,36 @@ func (s *StateDB) PayRewards() {
 					continue
 				}
 
-				s.AddBalance(stake.Owner, types.GetBaseCoin(), reward)
 				remainder.Sub(remainder, reward)
 
+				recipient := stake.Owner
+
+				compound := s.IsCompoundRewards(stake.Owner)
+				if compound {
+					if _, has := compounded[stake.Owner]; !has {
+						compounded[stake.Owner] = big.NewInt(0)
+						compoundedOwners = append(compoundedOwners, stake.Owner)
+					}
+					compounded[stake.Owner].Add(compounded[stake.Owner], reward)
+				} else {
+					recipient = s.GetRewardAddress(stake.Owner)
+					s.AddBalance(recipient, types.GetBaseCoin(), reward)
+				}
+
 				edb.AddEvent(s.height, events.RewardEvent{
 					Role:            events.RoleDelegator,
 					Address:         stake.Owner,
 					Amount:          reward.Bytes(),
 					ValidatorPubKey: candidate.PubKey,
+					Compounded:      compound,
+					Recipient:       recipient,
 				})
 			}
 
+			for _, owner := range compoundedOwners {
+				s.Delegate(owner, candidate.PubKey, types.GetBaseCoin(), compounded[owner])
+			}
+
 			vals.data[i].AccumReward = big.NewInt(0)
 
 			if remainder.Cmp(big.NewInt(0)) > -1 {
@@ -1084,53 +1858,128 @@ func (s *StateDB) RecalculateTotalStakeValues() {
 
 	s.setStateCandidates(stateCandidates)
 	s.MarkStateCandidateDirty()
+
+	s.checkSelfStakes()
 }
 
-func (s *StateDB) Delegate(sender types.Address, pubkey []byte, coin types.CoinSymbol, value *big.Int) {
-	stateCandidates := s.getStateCandidates()
+// checkSelfStakes switches off candidates which self-stake is below the minimum
+func (s *StateDB) checkSelfStakes() {
+	minSelfStake := s.GetParams().MinSelfStake
+	if minSelfStake == nil || minSelfStake.Sign() == 0 {
+		return
+	}
 
+	stateCandidates := s.getStateCandidates()
 	for i := range stateCandidates.data {
 		candidate := &stateCandidates.data[i]
-		if candidate.PubKey.Compare(pubkey) == 0 {
-			exists := false
+		if candidate.Status != CandidateStatusOnline {
+			continue
+		}
 
-			for j := range candidate.Stakes {
-				stake := &candidate.Stakes[j]
-				if sender.Compare(stake.Owner) == 0 && stake.Coin.Compare(coin) == 0 {
-					stake.Value.Add(stake.Value, value)
-					exists = true
-					break
-				}
+		if selfStake := candidate.SelfStake(); selfStake.Cmp(minSelfStake) < 0 {
+			log.Info("Candidate's self-stake is too low", "pubkey", candidate.PubKey.String(), "self-stake", selfStake)
+			candidate.Status = CandidateStatusOffline
+		}
+	}
+
+	s.setStateCandidates(stateCandidates)
+	s.MarkStateCandidateDirty()
+}
+
+// IsSelfStakeSufficient checks if self-stake of candidate with given public key satisfies the minimum
+func (s *StateDB) IsSelfStakeSufficient(pubkey types.Pubkey) bool {
+	minSelfStake := s.GetParams().MinSelfStake
+	if minSelfStake == nil || minSelfStake.Sign() == 0 {
+		return true
+	}
+
+	candidate := s.GetStateCandidate(pubkey)
+	if candidate == nil {
+		return false
+	}
+
+	return candidate.SelfStake().Cmp(minSelfStake) >= 0
+}
+
+// IsStakeShareExceeded checks if candidate's share of total validators' stake exceeds the maximum
+// after delegating stake with given bip value. The cap applies at delegation time only and uses stakes
+// calculated by the last RecalculateTotalStakeValues: shares which drift above the cap because of changes
+// of coin prices, unbonds or slashes are not rechecked and such candidates are not switched off.
+func (s *StateDB) IsStakeShareExceeded(pubkey types.Pubkey, bipValue *big.Int) bool {
+	maxShare := s.GetParams().MaxValidatorStakeShare
+	if maxShare == 0 {
+		return false
+	}
+
+	candidate := s.GetStateCandidate(pubkey)
+	if candidate == nil {
+		return false
+	}
+
+	total := big.NewInt(0).Set(bipValue)
+	if vals := s.getStateValidators(); vals != nil {
+		for _, val := range vals.data {
+			if !bytes.Equal(val.PubKey, pubkey) {
+				total.Add(total, val.TotalBipStake)
 			}
+		}
+	}
 
-			if !exists {
-				candidate.Stakes = append(candidate.Stakes, Stake{
-					Owner:    sender,
-					Coin:     coin,
-					Value:    value,
-					BipValue: big.NewInt(0),
-				})
+	stake := big.NewInt(0).Add(candidate.TotalBipStake, bipValue)
+	total.Add(total, candidate.TotalBipStake)
+
+	// stake * 100 > total * maxShare
+	return stake.Mul(stake, big.NewInt(100)).Cmp(total.Mul(total, big.NewInt(int64(maxShare)))) > 0
+}
+
+func (s *StateDB) Delegate(sender types.Address, pubkey []byte, coin types.CoinSymbol, value *big.Int) {
+	stateCandidates := s.getStateCandidatesOnDemand()
+
+	if candidate := stateCandidates.get(pubkey); candidate != nil {
+		s.addTotalStaked(coin, value)
+
+		exists := false
+		for j := range candidate.Stakes {
+			stake := &candidate.Stakes[j]
+			if sender.Compare(stake.Owner) == 0 && stake.Coin.Compare(coin) == 0 {
+				stake.Value.Add(stake.Value, value)
+				exists = true
+				break
 			}
 		}
+
+		if !exists {
+			candidate.Stakes = append(candidate.Stakes, Stake{
+				Owner:    sender,
+				Coin:     coin,
+				Value:    value,
+				BipValue: big.NewInt(0),
+			})
+		}
+
+		s.addToStakeIndex(sender, candidate.PubKey, coin)
 	}
 
 	s.setStateCandidates(stateCandidates)
-	s.MarkStateCandidateDirty()
+	s.markCandidateDirty(pubkey)
 }
 
 func (s *StateDB) SubStake(sender types.Address, pubkey []byte, coin types.CoinSymbol, value *big.Int) {
-	stateCandidates := s.getStateCandidates()
+	stateCandidates := s.getStateCandidatesOnDemand()
 
-	for i := range stateCandidates.data {
-		candidate := &stateCandidates.data[i]
-		if candidate.PubKey.Compare(pubkey) == 0 {
-			currentStakeValue := candidate.GetStakeOfAddress(sender, coin).Value
-			currentStakeValue.Sub(currentStakeValue, value)
+	if candidate := stateCandidates.get(pubkey); candidate != nil {
+		s.addTotalStaked(coin, big.NewInt(0).Neg(value))
+
+		currentStakeValue := candidate.GetStakeOfAddress(sender, coin).Value
+		currentStakeValue.Sub(currentStakeValue, value)
+
+		if currentStakeValue.Cmp(types.Big0) == 0 {
+			s.removeFromStakeIndex(sender, candidate.PubKey, coin)
 		}
 	}
 
 	s.setStateCandidates(stateCandidates)
-	s.MarkStateCandidateDirty()
+	s.markCandidateDirty(pubkey)
 }
 
 func (s *StateDB) IsCheckUsed(check *check.Check) bool {
@@ -1160,20 +2009,359 @@ func (s *StateDB) EditCandidate(pubkey []byte, newRewardAddress types.Address, n
 			candidate.OwnerAddress = newOwnerAddress
 			break
 		}
-	}
-	s.setStateCandidates(stateCandidates)
-	s.MarkStateCandidateDirty()
+	}
+	s.setStateCandidates(stateCandidates)
+	s.markCandidateDirty(pubkey)
+
+	vals := s.getStateValidators()
+	for i := range vals.data {
+		validator := &vals.data[i]
+		if bytes.Equal(validator.PubKey, pubkey) {
+			validator.RewardAddress = newRewardAddress
+			break
+		}
+	}
+	s.setStateValidators(vals)
+	s.MarkStateValidatorsDirty()
+}
+
+// GetCandidateProfile returns candidate's profile or nil if it is not set
+func (s *StateDB) GetCandidateProfile(pubkey types.Pubkey) *CandidateProfile {
+	profile := s.getStateCandidateProfile(pubkey)
+	if profile == nil {
+		return nil
+	}
+
+	data := profile.Data()
+	return &data
+}
+
+func (s *StateDB) SetCandidateProfile(pubkey types.Pubkey, data CandidateProfile) {
+	profile := s.getStateCandidateProfile(pubkey)
+	if profile == nil {
+		profile = newCandidateProfile(pubkey, data, s.MarkStateCandidateProfileDirty)
+		s.setStateCandidateProfile(profile)
+	}
+
+	profile.SetData(data)
+}
+
+func (s *StateDB) RemoveCandidateProfile(pubkey types.Pubkey) {
+	profile := s.getStateCandidateProfile(pubkey)
+	if profile == nil {
+		return
+	}
+
+	profile.Delete()
+}
+
+// GetCandidateJail returns candidate's jail status and history or nil if candidate was never jailed
+func (s *StateDB) GetCandidateJail(pubkey types.Pubkey) *CandidateJail {
+	jail := s.getStateCandidateJail(pubkey)
+	if jail == nil {
+		return nil
+	}
+
+	data := jail.Data()
+	return &data
+}
+
+// IsCandidateTombstoned checks if candidate with given public key was permanently jailed for double signing
+func (s *StateDB) IsCandidateTombstoned(pubkey types.Pubkey) bool {
+	jail := s.getStateCandidateJail(pubkey)
+	return jail != nil && jail.Data().Tombstoned
+}
+
+// JailCandidate sets candidate offline and forbids switching it on until jail period is over.
+// Byzantine candidates are tombstoned and can never leave the jail.
+func (s *StateDB) JailCandidate(pubkey types.Pubkey, reason byte) {
+	jailedUntil := s.height + s.GetParams().JailPeriod
+
+	jail := s.getOrNewStateCandidateJail(pubkey)
+	jail.Jail(s.height, jailedUntil, reason)
+	if reason == JailReasonByzantine {
+		jail.Tombstone()
+	}
+
+	s.SetCandidateOffline(pubkey)
+
+	eventsdb.GetCurrent().AddEvent(s.height, events.JailEvent{
+		ValidatorPubKey: pubkey,
+		JailedUntil:     jailedUntil,
+		Reason:          reason,
+		Tombstoned:      jail.Data().Tombstoned,
+	})
+}
+
+// UnjailCandidate releases candidate from the jail and sets it online
+func (s *StateDB) UnjailCandidate(pubkey types.Pubkey) {
+	jail := s.getStateCandidateJail(pubkey)
+	if jail == nil {
+		return
+	}
+
+	jail.Unjail()
+	s.SetCandidateOnline(pubkey)
+
+	eventsdb.GetCurrent().AddEvent(s.height, events.UnjailEvent{
+		ValidatorPubKey: pubkey,
+	})
+}
+
+func (s *StateDB) removeCandidateJail(pubkey types.Pubkey) {
+	jail := s.getStateCandidateJail(pubkey)
+	if jail == nil || jail.Data().Tombstoned {
+		return
+	}
+
+	jail.Delete()
+}
+
+func (s *StateDB) GetCandidateCommissionChange(pubkey types.Pubkey) *CommissionChange {
+	changes := s.getStateCommissionChanges()
+	if changes == nil {
+		return nil
+	}
+
+	for i, change := range changes.data {
+		if bytes.Equal(change.PubKey, pubkey) {
+			return &(changes.data[i])
+		}
+	}
+
+	return nil
+}
+
+// SetCandidateCommissionChange schedules candidate's commission change at given height.
+// Previously scheduled change of the same candidate is replaced.
+func (s *StateDB) SetCandidateCommissionChange(pubkey types.Pubkey, commission uint, height uint64) {
+	s.RemoveCandidateCommissionChange(pubkey)
+
+	changes := s.getStateCommissionChanges()
+	if changes == nil {
+		changes = newCommissionChanges(s, CommissionChanges{}, s.MarkStateCommissionChangesDirty)
+	}
+
+	changes.data = append(changes.data, CommissionChange{
+		PubKey:     pubkey,
+		Commission: commission,
+		Height:     height,
+	})
+
+	s.setStateCommissionChanges(changes)
+	s.MarkStateCommissionChangesDirty()
+}
+
+func (s *StateDB) RemoveCandidateCommissionChange(pubkey types.Pubkey) {
+	changes := s.getStateCommissionChanges()
+	if changes == nil {
+		return
+	}
+
+	var newChanges CommissionChanges
+	for _, change := range changes.data {
+		if bytes.Equal(change.PubKey, pubkey) {
+			continue
+		}
+
+		newChanges = append(newChanges, change)
+	}
+
+	if len(newChanges) == len(changes.data) {
+		return
+	}
+
+	changes.data = newChanges
+	s.setStateCommissionChanges(changes)
+	s.MarkStateCommissionChangesDirty()
+}
+
+// ApplyCandidateCommissionChanges sets new commissions of candidates and validators which
+// notice period is over at given height
+func (s *StateDB) ApplyCandidateCommissionChanges(height uint64) {
+	changes := s.getStateCommissionChanges()
+	if changes == nil {
+		return
+	}
+
+	var pending CommissionChanges
+	for _, change := range changes.data {
+		if change.Height > height {
+			pending = append(pending, change)
+			continue
+		}
+
+		if candidate := s.GetStateCandidate(change.PubKey); candidate != nil {
+			candidate.Commission = change.Commission
+			s.markCandidateDirty(change.PubKey)
+		}
+
+		if vals := s.getStateValidators(); vals != nil {
+			for i := range vals.data {
+				if bytes.Equal(vals.data[i].PubKey, change.PubKey) {
+					vals.data[i].Commission = change.Commission
+					s.MarkStateValidatorsDirty()
+					break
+				}
+			}
+		}
+	}
+
+	if len(pending) == len(changes.data) {
+		return
+	}
+
+	changes.data = pending
+	s.setStateCommissionChanges(changes)
+	s.MarkStateCommissionChangesDirty()
+}
+
+func (s *StateDB) GetCandidatePubKeyChange(pubkey types.Pubkey) *PubKeyChange {
+	changes := s.getStatePubKeyChanges()
+	if changes == nil {
+		return nil
+	}
+
+	for i, change := range changes.data {
+		if bytes.Equal(change.PubKey, pubkey) {
+			return &(changes.data[i])
+		}
+	}
+
+	return nil
+}
+
+// IsPubKeyInUse checks if given public key belongs to a candidate, is tombstoned, is retired or
+// is requested as a new public key of some candidate
+func (s *StateDB) IsPubKeyInUse(pubkey types.Pubkey) bool {
+	if s.CandidateExists(pubkey) || s.IsCandidateTombstoned(pubkey) || s.IsPubKeyRetired(pubkey) {
+		return true
+	}
+
+	if changes := s.getStatePubKeyChanges(); changes != nil {
+		for _, change := range changes.data {
+			if bytes.Equal(change.NewPubKey, pubkey) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// SetCandidatePubKeyChange schedules replacement of candidate's public key at the next update of validator set.
+// Previously scheduled change of the same candidate is replaced.
+func (s *StateDB) SetCandidatePubKeyChange(pubkey types.Pubkey, newPubKey types.Pubkey) {
+	changes := s.getStatePubKeyChanges()
+	if changes == nil {
+		changes = newPubKeyChanges(s, PubKeyChanges{}, s.MarkStatePubKeyChangesDirty)
+	}
+
+	var newChanges PubKeyChanges
+	for _, change := range changes.data {
+		if !bytes.Equal(change.PubKey, pubkey) {
+			newChanges = append(newChanges, change)
+		}
+	}
+
+	changes.data = append(newChanges, PubKeyChange{
+		PubKey:    pubkey,
+		NewPubKey: newPubKey,
+	})
+
+	s.setStatePubKeyChanges(changes)
+	s.MarkStatePubKeyChangesDirty()
+}
+
+// ApplyCandidatePubKeyChanges replaces public keys of candidates and validators. Candidate's stakes,
+// commission, reward address, profile and jail history are kept under the new key. Old keys are retired.
+func (s *StateDB) ApplyCandidatePubKeyChanges() {
+	changes := s.getStatePubKeyChanges()
+	if changes == nil || len(changes.data) == 0 {
+		return
+	}
+
+	for _, change := range changes.data {
+		candidate := s.GetStateCandidate(change.PubKey)
+		if candidate == nil {
+			continue
+		}
+
+		candidate.PubKey = change.NewPubKey
+		candidate.tmAddress = nil
+		s.MarkStateCandidateDirty()
+		s.moveStakesIndex(candidate, change.PubKey)
+		s.moveRedelegationsAndFrozenFunds(change.PubKey, change.NewPubKey)
+
+		// keep accumulated reward and absent times of validator
+		if vals := s.getStateValidators(); vals != nil {
+			for i := range vals.data {
+				if bytes.Equal(vals.data[i].PubKey, change.PubKey) {
+					vals.data[i].PubKey = change.NewPubKey
+					s.MarkStateValidatorsDirty()
+					break
+				}
+			}
+		}
+
+		if profile := s.GetCandidateProfile(change.PubKey); profile != nil {
+			s.SetCandidateProfile(change.NewPubKey, *profile)
+			s.RemoveCandidateProfile(change.PubKey)
+		}
+
+		if jail := s.getStateCandidateJail(change.PubKey); jail != nil {
+			newJail := newCandidateJail(change.NewPubKey, jail.Data(), s.MarkStateCandidateJailDirty)
+			s.setStateCandidateJail(newJail)
+			s.MarkStateCandidateJailDirty(change.NewPubKey)
+			jail.Delete()
+		}
+
+		if commissionChange := s.GetCandidateCommissionChange(change.PubKey); commissionChange != nil {
+			commission, height := commissionChange.Commission, commissionChange.Height
+			s.RemoveCandidateCommissionChange(change.PubKey)
+			s.SetCandidateCommissionChange(change.NewPubKey, commission, height)
+		}
+
+		s.setRetiredPubKey(RetiredPubKey{
+			PubKey:    change.PubKey,
+			NewPubKey: change.NewPubKey,
+			Height:    s.height,
+		})
+
+		eventsdb.GetCurrent().AddEvent(s.height, events.PubKeyChangeEvent{
+			OldPubKey: change.PubKey,
+			NewPubKey: change.NewPubKey,
+		})
+	}
+
+	changes.data = PubKeyChanges{}
+	s.setStatePubKeyChanges(changes)
+	s.MarkStatePubKeyChangesDirty()
+}
+
+// moveRedelegationsAndFrozenFunds moves pending redelegations and frozen funds of candidate to its new public key,
+// so they stay slashable for evidences which are resolved to the new key
+func (s *StateDB) moveRedelegationsAndFrozenFunds(pubKey []byte, newPubKey []byte) {
+	for _, height := range s.getRedelegationsHeights(s.height, s.height+s.GetParams().UnbondPeriod) {
+		redelegation := s.getStateRedelegations(height)
+		if redelegation == nil || redelegation.deleted {
+			continue
+		}
+
+		redelegation.replaceCandidateKey(pubKey, newPubKey)
+	}
+
+	moved := map[uint64]struct{}{}
+	for _, fund := range s.GetFrozenFundsOfCandidate(pubKey) {
+		if _, has := moved[fund.Height]; has {
+			continue
+		}
+		moved[fund.Height] = struct{}{}
 
-	vals := s.getStateValidators()
-	for i := range vals.data {
-		validator := &vals.data[i]
-		if bytes.Equal(validator.PubKey, pubkey) {
-			validator.RewardAddress = newRewardAddress
-			break
+		if frozenFunds := s.getStateFrozenFunds(fund.Height); frozenFunds != nil {
+			frozenFunds.replaceCandidateKey(pubKey, newPubKey)
 		}
 	}
-	s.setStateValidators(vals)
-	s.MarkStateValidatorsDirty()
 }
 
 func (s *StateDB) SetCandidateOnline(pubkey []byte) {
@@ -1187,7 +2375,7 @@ func (s *StateDB) SetCandidateOnline(pubkey []byte) {
 	}
 
 	s.setStateCandidates(stateCandidates)
-	s.MarkStateCandidateDirty()
+	s.markCandidateDirty(pubkey)
 }
 
 func (s *StateDB) SetCandidateOffline(pubkey []byte) {
@@ -1199,7 +2387,7 @@ func (s *StateDB) SetCandidateOffline(pubkey []byte) {
 		}
 	}
 	s.setStateCandidates(stateCandidates)
-	s.MarkStateCandidateDirty()
+	s.markCandidateDirty(pubkey)
 
 	vals := s.getStateValidators()
 	for i := range vals.data {
@@ -1252,7 +2440,8 @@ func (s *StateDB) SetValidatorAbsent(address [20]byte) {
 
 			validator.AbsentTimes.SetIndex(int(s.height)%ValidatorMaxAbsentWindow, true)
 
-			if validator.CountAbsentTimes() > ValidatorMaxAbsentTimes {
+			params := s.GetParams()
+			if uint64(validator.CountAbsentTimes()) > params.ValidatorMaxAbsentTimes {
 				candidate.Status = CandidateStatusOffline
 				validator.AbsentTimes = types.NewBitArray(ValidatorMaxAbsentWindow)
 				validator.toDrop = true
@@ -1261,7 +2450,7 @@ func (s *StateDB) SetValidatorAbsent(address [20]byte) {
 
 				for j, stake := range candidate.Stakes {
 					newValue := big.NewInt(0).Set(stake.Value)
-					newValue.Mul(newValue, big.NewInt(99))
+					newValue.Mul(newValue, big.NewInt(int64(100-params.AbsentSlashPercent)))
 					newValue.Div(newValue, big.NewInt(100))
 
 					slashed := big.NewInt(0).Set(stake.Value)
@@ -1285,6 +2474,8 @@ func (s *StateDB) SetValidatorAbsent(address [20]byte) {
 						Amount:          slashed.Bytes(),
 						Coin:            stake.Coin,
 						ValidatorPubKey: candidate.PubKey,
+						Reason:          events.SlashReasonAbsent,
+						EvidenceHeight:  s.height,
 					})
 
 					candidate.Stakes[j] = Stake{
@@ -1294,9 +2485,17 @@ func (s *StateDB) SetValidatorAbsent(address [20]byte) {
 						BipValue: big.NewInt(0),
 					}
 					totalStake.Add(totalStake, newValue)
+
+					if newValue.Cmp(types.Big0) == 0 {
+						s.removeFromStakeIndex(stake.Owner, candidate.PubKey, stake.Coin)
+					}
 				}
 
 				validator.TotalBipStake = totalStake
+
+				if s.height >= upgrades.UpgradeBlock2 {
+					s.JailCandidate(candidate.PubKey, JailReasonAbsent)
+				}
 			}
 
 			s.setStateCandidates(candidates)
@@ -1308,7 +2507,7 @@ func (s *StateDB) SetValidatorAbsent(address [20]byte) {
 	s.MarkStateValidatorsDirty()
 }
 
-func (s *StateDB) PunishByzantineValidator(address [20]byte) {
+func (s *StateDB) PunishByzantineValidator(address [20]byte, evidenceHeight uint64) {
 	edb := eventsdb.GetCurrent()
 	vals := s.getStateValidators()
 
@@ -1328,9 +2527,10 @@ func (s *StateDB) PunishByzantineValidator(address [20]byte) {
 				return
 			}
 
+			params := s.GetParams()
 			for _, stake := range candidate.Stakes {
 				newValue := big.NewInt(0).Set(stake.Value)
-				newValue.Mul(newValue, big.NewInt(95))
+				newValue.Mul(newValue, big.NewInt(int64(100-params.ByzantineSlashPercent)))
 				newValue.Div(newValue, big.NewInt(100))
 
 				slashed := big.NewInt(0).Set(stake.Value)
@@ -1353,11 +2553,16 @@ func (s *StateDB) PunishByzantineValidator(address [20]byte) {
 					Amount:          slashed.Bytes(),
 					Coin:            stake.Coin,
 					ValidatorPubKey: candidate.PubKey,
+					Reason:          events.SlashReasonByzantine,
+					EvidenceHeight:  evidenceHeight,
 				})
 
-				s.GetOrNewStateFrozenFunds(s.height+UnbondPeriod).AddFund(stake.Owner, candidate.PubKey,
+				s.GetOrNewStateFrozenFunds(s.height+params.UnbondPeriod).AddFund(stake.Owner, candidate.PubKey,
 					stake.Coin, newValue)
+				s.CarryRedelegations(stake.Owner, candidate.PubKey, stake.Coin, newValue, nil,
+					s.height+params.UnbondPeriod)
 				s.SanitizeCoin(stake.Coin)
+				s.removeFromStakeIndex(stake.Owner, candidate.PubKey, stake.Coin)
 			}
 
 			candidate.Stakes = []Stake{}
@@ -1365,6 +2570,10 @@ func (s *StateDB) PunishByzantineValidator(address [20]byte) {
 			validator.TotalBipStake = big.NewInt(0)
 			validator.toDrop = true
 
+			if s.height >= upgrades.UpgradeBlock2 {
+				s.JailCandidate(candidate.PubKey, JailReasonByzantine)
+			}
+
 			s.setStateCandidates(candidates)
 			s.MarkStateCandidateDirty()
 		}
@@ -1374,7 +2583,7 @@ func (s *StateDB) PunishByzantineValidator(address [20]byte) {
 	s.MarkStateValidatorsDirty()
 }
 
-func (s *StateDB) PunishFrozenFundsWithAddress(fromBlock uint64, toBlock uint64, address [20]byte) {
+func (s *StateDB) PunishFrozenFundsWithAddress(fromBlock uint64, toBlock uint64, address [20]byte, evidenceHeight uint64) {
 	for i := fromBlock; i <= toBlock; i++ {
 		frozenFund := s.getStateFrozenFunds(i)
 
@@ -1382,8 +2591,75 @@ func (s *StateDB) PunishFrozenFundsWithAddress(fromBlock uint64, toBlock uint64,
 			continue
 		}
 
-		frozenFund.PunishFund(s, address, fromBlock)
+		frozenFund.PunishFund(s, address, fromBlock, evidenceHeight)
+	}
+}
+
+// PunishRedelegationsWithAddress slashes stakes which were redelegated from candidate with given address
+// and are still slashable for its misbehaviour
+func (s *StateDB) PunishRedelegationsWithAddress(fromBlock uint64, toBlock uint64, address [20]byte, evidenceHeight uint64) {
+	for _, height := range s.getRedelegationsHeights(fromBlock, toBlock) {
+		redelegation := s.getStateRedelegations(height)
+
+		if redelegation == nil || redelegation.deleted {
+			continue
+		}
+
+		redelegation.PunishRedelegations(s, address, fromBlock, evidenceHeight)
+	}
+}
+
+// CarryRedelegations keeps value of address' stake leaving candidate slashable for misbehaviour of candidates it
+// was redelegated from. The value is either redelegated to newPubKey or unbonded to frozen funds of unbondHeight
+// (newPubKey is nil then). Redelegated parts of the stake are considered leaving first.
+func (s *StateDB) CarryRedelegations(address types.Address, pubKey []byte, coin types.CoinSymbol, value *big.Int,
+	newPubKey []byte, unbondHeight uint64) {
+	remaining := big.NewInt(0).Set(value)
+	for _, height := range s.getRedelegationsHeights(s.height, s.height+s.GetParams().UnbondPeriod) {
+		if remaining.Sign() == 0 {
+			return
+		}
+
+		redelegation := s.getStateRedelegations(height)
+		if redelegation == nil || redelegation.deleted {
+			continue
+		}
+
+		redelegation.carry(address, pubKey, coin, remaining, newPubKey, unbondHeight)
+	}
+}
+
+// getRedelegationsHeights returns ordered heights in range [from, to] which have redelegations, committed or not
+func (s *StateDB) getRedelegationsHeights(from uint64, to uint64) []uint64 {
+	start, end := make([]byte, 8), make([]byte, 8)
+	binary.BigEndian.PutUint64(start, from)
+	binary.BigEndian.PutUint64(end, to+1)
+
+	found := map[uint64]struct{}{}
+	s.iavl.IterateRange(append(redelegationsPrefix, start...), append(redelegationsPrefix, end...), true,
+		func(key []byte, value []byte) bool {
+			found[binary.BigEndian.Uint64(key[1:])] = struct{}{}
+			return false
+		})
+
+	s.lock.Lock()
+	for height := range s.stateRedelegations {
+		if height >= from && height <= to {
+			found[height] = struct{}{}
+		}
+	}
+	s.lock.Unlock()
+
+	heights := make([]uint64, 0, len(found))
+	for height := range found {
+		heights = append(heights, height)
 	}
+
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] < heights[j]
+	})
+
+	return heights
 }
 
 func (s *StateDB) SetNewValidators(candidates []Candidate) {
@@ -1437,24 +2713,32 @@ func (s *StateDB) RemoveCurrentValidator(pubkey types.Pubkey) {
 
 // remove 0-valued stakes
 func (s *StateDB) clearStateCandidates() {
-	stateCandidates := s.getStateCandidates()
+	stateCandidates := s.getStateCandidatesOnDemand()
 
 	for i := range stateCandidates.data {
+		// stakes of candidates which are not loaded are not changed
+		if !stateCandidates.isLoaded(i) {
+			continue
+		}
+
 		candidate := &stateCandidates.data[i]
 
 		var newStakes []Stake
 		for _, stake := range candidate.Stakes {
 			if stake.Value.Cmp(types.Big0) == 0 {
+				s.removeFromStakeIndex(stake.Owner, candidate.PubKey, stake.Coin)
 				continue
 			}
 			newStakes = append(newStakes, stake)
 		}
 
-		candidate.Stakes = newStakes
+		if len(newStakes) != len(candidate.Stakes) {
+			candidate.Stakes = newStakes
+			s.markCandidateDirty(candidate.PubKey)
+		}
 	}
 
 	s.setStateCandidates(stateCandidates)
-	s.MarkStateCandidateDirty()
 }
 
 func (s *StateDB) CreateMultisig(weights []uint, addresses []types.Address, threshold uint) types.Address {
@@ -1522,11 +2806,16 @@ func (s *StateDB) ClearCandidates() {
 		dropped := candidates.data[maxCandidates:]
 		candidates.data = candidates.data[:maxCandidates]
 
-		unbondAtBlock := s.height + UnbondPeriod
+		unbondAtBlock := s.height + s.GetParams().UnbondPeriod
 		for _, candidate := range dropped {
 			for _, stake := range candidate.Stakes {
 				s.GetOrNewStateFrozenFunds(unbondAtBlock).AddFund(stake.Owner, candidate.PubKey, stake.Coin, stake.Value)
+				s.CarryRedelegations(stake.Owner, candidate.PubKey, stake.Coin, stake.Value, nil, unbondAtBlock)
+				s.removeFromStakeIndex(stake.Owner, candidate.PubKey, stake.Coin)
 			}
+
+			s.RemoveCandidateProfile(candidate.PubKey)
+			s.removeCandidateJail(candidate.PubKey)
 		}
 	}
 
@@ -1556,6 +2845,7 @@ func (s *StateDB) ClearStakes() {
 					ValidatorPubKey: candidate.PubKey,
 				})
 				s.AddBalance(stake.Owner, stake.Coin, stake.Value)
+				s.removeFromStakeIndex(stake.Owner, candidate.PubKey, stake.Coin)
 			}
 		}
 	}
@@ -1777,77 +3067,93 @@ func (s *StateDB) deleteCoin(symbol types.CoinSymbol) {
 	s.MarkStateCoinDirty(symbol)
 }
 
+// Kinds of exported records. Kinds are named after fields of types.AppState which records belong to.
+const (
+	ExportAccounts          = "accounts"
+	ExportCoins             = "coins"
+	ExportFrozenFunds       = "frozen_funds"
+	ExportUsedChecks        = "used_checks"
+	ExportCandidateJails    = "candidate_jails"
+	ExportAccountSettings   = "account_settings"
+	ExportRedelegations     = "redelegations"
+	ExportCandidates        = "candidates"
+	ExportValidators        = "validators"
+	ExportCommissionChanges = "commission_changes"
+	ExportPubKeyChanges     = "pub_key_changes"
+	ExportRetiredPubKeys    = "retired_pub_keys"
+	ExportParams            = "params"
+	ExportProposals         = "proposals"
+	ExportUpgradePlan       = "upgrade_plan"
+	ExportAppliedUpgrades   = "applied_upgrades"
+	ExportMaxGas            = "max_gas"
+	ExportStartHeight       = "start_height"
+	ExportTotalSlashed      = "total_slashed"
+)
+
 func (s *StateDB) Export(currentHeight uint64) types.AppState {
 	appState := types.AppState{}
 
-	s.iavl.Iterate(func(key []byte, value []byte) bool {
-		// export accounts
-		if key[0] == addressPrefix[0] {
-			account := s.GetOrNewStateObject(types.BytesToAddress(key[1:]))
-
-			balance := make([]types.Balance, len(account.Balances().Data))
-			i := 0
-			for coin, value := range account.Balances().Data {
-				balance[i] = types.Balance{
-					Coin:  coin,
-					Value: value,
-				}
-				i++
-			}
-
-			acc := types.Account{
-				Address: account.address,
-				Balance: balance,
-				Nonce:   account.data.Nonce,
-			}
-
-			if account.IsMultisig() {
-				acc.MultisigData = &types.Multisig{
-					Weights:   account.data.MultisigData.Weights,
-					Threshold: account.data.MultisigData.Threshold,
-					Addresses: account.data.MultisigData.Addresses,
-				}
-			}
-
-			appState.Accounts = append(appState.Accounts, acc)
-		}
-
-		// export coins
-		if key[0] == coinPrefix[0] {
-			coin := s.GetStateCoin(types.StrToCoinSymbol(string(key[1:])))
-
-			appState.Coins = append(appState.Coins, types.Coin{
-				Name:           coin.Name(),
-				Symbol:         coin.Symbol(),
-				Volume:         coin.Volume(),
-				Crr:            coin.Crr(),
-				ReserveBalance: coin.ReserveBalance(),
-			})
+	_ = s.ExportStream(currentHeight, func(kind string, value interface{}) error {
+		switch kind {
+		case ExportAccounts:
+			appState.Accounts = append(appState.Accounts, value.(types.Account))
+		case ExportCoins:
+			appState.Coins = append(appState.Coins, value.(types.Coin))
+		case ExportFrozenFunds:
+			appState.FrozenFunds = append(appState.FrozenFunds, value.(types.FrozenFund))
+		case ExportUsedChecks:
+			appState.UsedChecks = append(appState.UsedChecks, value.(types.UsedCheck))
+		case ExportCandidateJails:
+			appState.CandidateJails = append(appState.CandidateJails, value.(types.CandidateJail))
+		case ExportAccountSettings:
+			appState.AccountSettings = append(appState.AccountSettings, value.(types.AccountSettings))
+		case ExportRedelegations:
+			appState.Redelegations = append(appState.Redelegations, value.(types.Redelegation))
+		case ExportCandidates:
+			appState.Candidates = append(appState.Candidates, value.(types.Candidate))
+		case ExportValidators:
+			appState.Validators = append(appState.Validators, value.(types.Validator))
+		case ExportCommissionChanges:
+			appState.CommissionChanges = append(appState.CommissionChanges, value.(types.CommissionChange))
+		case ExportPubKeyChanges:
+			appState.PubKeyChanges = append(appState.PubKeyChanges, value.(types.PubKeyChange))
+		case ExportRetiredPubKeys:
+			appState.RetiredPubKeys = append(appState.RetiredPubKeys, value.(types.PubKeyChange))
+		case ExportParams:
+			appState.Params = value.(*types.Params)
+		case ExportProposals:
+			appState.Proposals = append(appState.Proposals, value.(types.Proposal))
+		case ExportUpgradePlan:
+			appState.UpgradePlan = value.(*types.UpgradePlan)
+		case ExportAppliedUpgrades:
+			appState.AppliedUpgrades = append(appState.AppliedUpgrades, value.(string))
+		case ExportMaxGas:
+			appState.MaxGas = value.(uint64)
+		case ExportStartHeight:
+			appState.StartHeight = value.(uint64)
+		case ExportTotalSlashed:
+			appState.TotalSlashed = value.(*big.Int)
 		}
 
-		// export used checks
-		if key[0] == usedCheckPrefix[0] {
-			appState.UsedChecks = append(appState.UsedChecks, types.UsedCheck(fmt.Sprintf("%x", key[1:])))
-		}
+		return nil
+	})
 
-		// export frozen funds
-		if key[0] == frozenFundsPrefix[0] {
-			height := binary.BigEndian.Uint64(key[1:])
-			frozenFunds := s.GetStateFrozenFunds(height)
-
-			for _, frozenFund := range frozenFunds.List() {
-				appState.FrozenFunds = append(appState.FrozenFunds, types.FrozenFund{
-					Height:       height - uint64(currentHeight),
-					Address:      frozenFund.Address,
-					CandidateKey: frozenFund.CandidateKey,
-					Coin:         frozenFund.Coin,
-					Value:        frozenFund.Value,
-				})
-			}
-		}
+	return appState
+}
 
-		return false
+// ExportStream walks the state and calls fn for every exported record with its kind. Records of the same kind
+// are passed one after another. Unlike other getters, records of the state tree are decoded right from the tree
+// and not kept in the live set, so memory usage does not grow with the number of accounts. StateDB should have
+// no uncommitted changes.
+func (s *StateDB) ExportStream(currentHeight uint64, fn func(kind string, value interface{}) error) error {
+	var err error
+	s.iavl.Iterate(func(key []byte, value []byte) bool {
+		err = exportTreeRecord(key, value, currentHeight, fn)
+		return err != nil
 	})
+	if err != nil {
+		return err
+	}
 
 	candidates := s.getStateCandidates()
 	for _, candidate := range candidates.data {
@@ -1861,7 +3167,18 @@ func (s *StateDB) Export(currentHeight uint64) types.AppState {
 			})
 		}
 
-		appState.Candidates = append(appState.Candidates, types.Candidate{
+		var profile *types.CandidateProfile
+		if p := s.GetCandidateProfile(candidate.PubKey); p != nil {
+			profile = &types.CandidateProfile{
+				Moniker:         p.Moniker,
+				Website:         p.Website,
+				Description:     p.Description,
+				SecurityContact: p.SecurityContact,
+				LogoHash:        p.LogoHash,
+			}
+		}
+
+		err := fn(ExportCandidates, types.Candidate{
 			RewardAddress:  candidate.RewardAddress,
 			OwnerAddress:   candidate.OwnerAddress,
 			TotalBipStake:  candidate.TotalBipStake,
@@ -1870,12 +3187,16 @@ func (s *StateDB) Export(currentHeight uint64) types.AppState {
 			Stakes:         stakes,
 			CreatedAtBlock: candidate.CreatedAtBlock,
 			Status:         candidate.Status,
+			Profile:        profile,
 		})
+		if err != nil {
+			return err
+		}
 	}
 
 	vals := s.getStateValidators()
 	for _, val := range vals.data {
-		appState.Validators = append(appState.Validators, types.Validator{
+		err := fn(ExportValidators, types.Validator{
 			RewardAddress: val.RewardAddress,
 			TotalBipStake: val.TotalBipStake,
 			PubKey:        val.PubKey,
@@ -1883,13 +3204,273 @@ func (s *StateDB) Export(currentHeight uint64) types.AppState {
 			AccumReward:   val.AccumReward,
 			AbsentTimes:   val.AbsentTimes,
 		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if changes := s.getStateCommissionChanges(); changes != nil {
+		for _, change := range changes.data {
+			err := fn(ExportCommissionChanges, types.CommissionChange{
+				PubKey:     change.PubKey,
+				Commission: change.Commission,
+				Height:     change.Height - currentHeight,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if changes := s.getStatePubKeyChanges(); changes != nil {
+		for _, change := range changes.data {
+			err := fn(ExportPubKeyChanges, types.PubKeyChange{
+				PubKey:    change.PubKey,
+				NewPubKey: change.NewPubKey,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if params := s.getStateParams(); params != nil {
+		data := params.Data()
+		err := fn(ExportParams, &types.Params{
+			UnbondPeriod:                 data.UnbondPeriod,
+			ValidatorMaxAbsentTimes:      data.ValidatorMaxAbsentTimes,
+			AbsentSlashPercent:           data.AbsentSlashPercent,
+			ByzantineSlashPercent:        data.ByzantineSlashPercent,
+			DAOCommission:                data.DAOCommission,
+			ValidatorsUpdatePeriod:       data.ValidatorsUpdatePeriod,
+			CommissionChangeNoticePeriod: data.CommissionChangeNoticePeriod,
+			CommissionMultiplier:         data.CommissionMultiplier,
+			ProposalVotingPeriod:         data.ProposalVotingPeriod,
+			ProposalQuorum:               data.ProposalQuorum,
+			ProposalThreshold:            data.ProposalThreshold,
+			JailPeriod:                   data.JailPeriod,
+			EvidenceMaxAge:               data.EvidenceMaxAge,
+			MaxValidatorStakeShare:       data.MaxValidatorStakeShare,
+			MinSelfStake:                 data.MinSelfStake,
+		})
+		if err != nil {
+			return err
+		}
 	}
 
-	appState.MaxGas = s.GetMaxGas()
-	appState.StartHeight = s.height
-	appState.TotalSlashed = s.GetTotalSlashed()
+	for _, proposal := range s.GetProposals() {
+		changes := make([]types.ParamChange, len(proposal.Changes))
+		for i, change := range proposal.Changes {
+			changes[i] = types.ParamChange{
+				Name:  change.Name,
+				Value: change.Value,
+			}
+		}
+
+		votes := make([]types.Vote, len(proposal.Votes))
+		for i, vote := range proposal.Votes {
+			votes[i] = types.Vote{
+				Voter:  vote.Voter,
+				Option: vote.Option,
+			}
+		}
 
-	return appState
+		var upgrade *types.UpgradePlan
+		for _, plan := range proposal.Upgrade {
+			upgrade = &types.UpgradePlan{
+				Name:   plan.Name,
+				Height: plan.Height - currentHeight,
+				Info:   plan.Info,
+			}
+		}
+
+		err := fn(ExportProposals, types.Proposal{
+			ID:              proposal.ID,
+			Proposer:        proposal.Proposer,
+			Changes:         changes,
+			SubmitHeight:    0,
+			VotingEndHeight: proposal.VotingEndHeight - currentHeight,
+			Votes:           votes,
+			Upgrade:         upgrade,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if plan := s.GetUpgradePlan(); plan != nil {
+		err := fn(ExportUpgradePlan, &types.UpgradePlan{
+			Name:   plan.Name,
+			Height: plan.Height - currentHeight,
+			Info:   plan.Info,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, applied := range s.GetAppliedUpgrades() {
+		if err := fn(ExportAppliedUpgrades, applied.Name); err != nil {
+			return err
+		}
+	}
+
+	if err := fn(ExportMaxGas, s.GetMaxGas()); err != nil {
+		return err
+	}
+
+	if err := fn(ExportStartHeight, s.height); err != nil {
+		return err
+	}
+
+	return fn(ExportTotalSlashed, s.GetTotalSlashed())
+}
+
+// exportTreeRecord decodes record of the state tree and passes it to fn if the record is exported
+func exportTreeRecord(key []byte, value []byte, currentHeight uint64, fn func(kind string, value interface{}) error) error {
+	switch key[0] {
+	case addressPrefix[0]:
+		var data Account
+		if err := rlp.DecodeBytes(value, &data); err != nil {
+			return fmt.Errorf("can't decode account %x: %v", key[1:], err)
+		}
+
+		coins := data.Balance.getCoins()
+		balance := make([]types.Balance, len(coins))
+		for i, coin := range coins {
+			balance[i] = types.Balance{
+				Coin:  coin,
+				Value: data.Balance.Data[coin],
+			}
+		}
+
+		acc := types.Account{
+			Address: types.BytesToAddress(key[1:]),
+			Balance: balance,
+			Nonce:   data.Nonce,
+		}
+
+		if len(data.MultisigData.Weights) > 0 {
+			acc.MultisigData = &types.Multisig{
+				Weights:   data.MultisigData.Weights,
+				Threshold: data.MultisigData.Threshold,
+				Addresses: data.MultisigData.Addresses,
+			}
+		}
+
+		return fn(ExportAccounts, acc)
+	case candidateJailPrefix[0]:
+		var jail CandidateJail
+		if err := rlp.DecodeBytes(value, &jail); err != nil {
+			return fmt.Errorf("can't decode candidate jail %x: %v", key[1:], err)
+		}
+
+		if !jail.IsJailed() {
+			return nil
+		}
+
+		// jail period is counted from the start of exported state
+		jailedUntil := uint64(1)
+		if jail.JailedUntil > currentHeight {
+			jailedUntil = jail.JailedUntil - currentHeight
+		}
+
+		return fn(ExportCandidateJails, types.CandidateJail{
+			PubKey:      types.Pubkey(append([]byte{}, key[1:]...)),
+			JailedUntil: jailedUntil,
+			Tombstoned:  jail.Tombstoned,
+		})
+	case coinPrefix[0]:
+		var coin Coin
+		if err := rlp.DecodeBytes(value, &coin); err != nil {
+			return fmt.Errorf("can't decode coin %s: %v", key[1:], err)
+		}
+
+		return fn(ExportCoins, types.Coin{
+			Name:           coin.Name,
+			Symbol:         coin.Symbol,
+			Volume:         coin.Volume,
+			Crr:            coin.Crr,
+			ReserveBalance: coin.ReserveBalance,
+		})
+	case usedCheckPrefix[0]:
+		return fn(ExportUsedChecks, types.UsedCheck(fmt.Sprintf("%x", key[1:])))
+	case retiredPubKeyPrefix[0]:
+		var retired RetiredPubKey
+		if err := rlp.DecodeBytes(value, &retired); err != nil {
+			return fmt.Errorf("can't decode retired public key %x: %v", key[1:], err)
+		}
+
+		// evidences of the exported chain are not expected, so only the replacement is exported
+		return fn(ExportRetiredPubKeys, types.PubKeyChange{
+			PubKey:    retired.PubKey,
+			NewPubKey: retired.NewPubKey,
+		})
+	case frozenFundsPrefix[0]:
+		height := binary.BigEndian.Uint64(key[1:])
+
+		var frozenFunds FrozenFunds
+		if err := rlp.DecodeBytes(value, &frozenFunds); err != nil {
+			return fmt.Errorf("can't decode frozen funds of height %d: %v", height, err)
+		}
+
+		for _, frozenFund := range frozenFunds.List {
+			err := fn(ExportFrozenFunds, types.FrozenFund{
+				Height:       height - currentHeight,
+				Address:      frozenFund.Address,
+				CandidateKey: frozenFund.CandidateKey,
+				Coin:         frozenFund.Coin,
+				Value:        frozenFund.Value,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	case accountSettingsPrefix[0]:
+		var settings AccountSettings
+		if err := rlp.DecodeBytes(value, &settings); err != nil {
+			return fmt.Errorf("can't decode account settings %x: %v", key[1:], err)
+		}
+
+		return fn(ExportAccountSettings, types.AccountSettings{
+			Address:         types.BytesToAddress(key[1:]),
+			CompoundRewards: settings.CompoundRewards,
+			RewardAddress:   settings.RewardAddress,
+		})
+	case redelegationsPrefix[0]:
+		height := binary.BigEndian.Uint64(key[1:])
+
+		var redelegations Redelegations
+		if err := rlp.DecodeBytes(value, &redelegations); err != nil {
+			return fmt.Errorf("can't decode redelegations of height %d: %v", height, err)
+		}
+
+		for _, redelegation := range redelegations.List {
+			err := fn(ExportRedelegations, types.Redelegation{
+				Height:           height - currentHeight,
+				Address:          redelegation.Address,
+				FromCandidateKey: redelegation.FromCandidateKey,
+				ToCandidateKey:   redelegation.ToCandidateKey,
+				Coin:             redelegation.Coin,
+				Value:            redelegation.Value,
+				UnbondHeight:     exportUnbondHeight(redelegation.UnbondHeight, currentHeight),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// exportUnbondHeight converts unbond height of redelegation to the height of exported state, 0 means not unbonded
+func exportUnbondHeight(height uint64, currentHeight uint64) uint64 {
+	if height == 0 {
+		return 0
+	}
+
+	return height - currentHeight
 }
 
 func (s *StateDB) Import(appState types.AppState) {
@@ -1954,10 +3535,29 @@ func (s *StateDB) Import(appState types.AppState) {
 			CreatedAtBlock: 1,
 			Status:         c.Status,
 		})
+
+		if c.Profile != nil {
+			s.SetCandidateProfile(c.PubKey, CandidateProfile{
+				Moniker:         c.Profile.Moniker,
+				Website:         c.Profile.Website,
+				Description:     c.Profile.Description,
+				SecurityContact: c.Profile.SecurityContact,
+				LogoHash:        c.Profile.LogoHash,
+			})
+		}
 	}
 	s.setStateCandidates(cands)
 	s.MarkStateCandidateDirty()
 
+	for _, j := range appState.CandidateJails {
+		jail := newCandidateJail(j.PubKey, CandidateJail{
+			JailedUntil: j.JailedUntil,
+			Tombstoned:  j.Tombstoned,
+		}, s.MarkStateCandidateJailDirty)
+		s.setStateCandidateJail(jail)
+		s.MarkStateCandidateJailDirty(j.PubKey)
+	}
+
 	for _, hashString := range appState.UsedChecks {
 		hash, _ := hex.DecodeString(string(hashString))
 		s.useCheckHash(hash)
@@ -1968,165 +3568,124 @@ func (s *StateDB) Import(appState types.AppState) {
 		frozenFunds.AddFund(ff.Address, ff.CandidateKey, ff.Coin, ff.Value)
 		s.setStateFrozenFunds(frozenFunds)
 	}
-}
-
-func (s *StateDB) CheckForInvariants() error {
-	height := s.height
-	if height <= 1 {
-		return nil
-	}
-
-	genesisFile := utils.GetMinterHome() + "/config/genesis.json"
-	genesis, err := tmTypes.GenesisDocFromFile(genesisFile)
-	if err != nil {
-		panic(err)
-	}
-
-	var genesisState types.AppState
-	_ = amino.UnmarshalJSON(genesis.AppState, &genesisState)
-
-	GenesisAlloc := big.NewInt(0)
-	for _, account := range genesisState.Accounts {
-		for _, bal := range account.Balance {
-			if bal.Coin.IsBaseCoin() {
-				GenesisAlloc.Add(GenesisAlloc, bal.Value)
-			}
-		}
-	}
-
-	for _, candidate := range genesisState.Candidates {
-		for _, stake := range candidate.Stakes {
-			if stake.Coin.IsBaseCoin() {
-				GenesisAlloc.Add(GenesisAlloc, stake.Value)
-			}
-		}
-	}
 
-	for _, coin := range genesisState.Coins {
-		GenesisAlloc.Add(GenesisAlloc, coin.ReserveBalance)
-	}
-
-	for _, ff := range genesisState.FrozenFunds {
-		if ff.Coin.IsBaseCoin() {
-			GenesisAlloc.Add(GenesisAlloc, ff.Value)
-		}
+	if appState.Params != nil {
+		s.SetParams(Params{
+			UnbondPeriod:                 appState.Params.UnbondPeriod,
+			ValidatorMaxAbsentTimes:      appState.Params.ValidatorMaxAbsentTimes,
+			AbsentSlashPercent:           appState.Params.AbsentSlashPercent,
+			ByzantineSlashPercent:        appState.Params.ByzantineSlashPercent,
+			DAOCommission:                appState.Params.DAOCommission,
+			ValidatorsUpdatePeriod:       appState.Params.ValidatorsUpdatePeriod,
+			CommissionChangeNoticePeriod: appState.Params.CommissionChangeNoticePeriod,
+			CommissionMultiplier:         appState.Params.CommissionMultiplier,
+			ProposalVotingPeriod:         appState.Params.ProposalVotingPeriod,
+			ProposalQuorum:               appState.Params.ProposalQuorum,
+			ProposalThreshold:            appState.Params.ProposalThreshold,
+			JailPeriod:                   appState.Params.JailPeriod,
+			EvidenceMaxAge:               appState.Params.EvidenceMaxAge,
+			MaxValidatorStakeShare:       appState.Params.MaxValidatorStakeShare,
+			MinSelfStake:                 appState.Params.MinSelfStake,
+		})
 	}
 
-	totalBasecoinVolume := big.NewInt(0)
-
-	coinSupplies := map[types.CoinSymbol]*big.Int{}
-	coinTotalOwned := map[types.CoinSymbol]*big.Int{}
-
-	s.iavl.Iterate(func(key []byte, value []byte) bool {
-		if key[0] == addressPrefix[0] {
-			account := s.GetOrNewStateObject(types.BytesToAddress(key[1:]))
-
-			for coin, value := range account.Balances().Data {
-				if coin.IsBaseCoin() {
-					totalBasecoinVolume.Add(totalBasecoinVolume, value)
-					continue
+	if len(appState.Proposals) > 0 {
+		proposals := newProposals(Proposals{}, s.MarkStateProposalsDirty)
+		for _, p := range appState.Proposals {
+			changes := make([]ParamChange, len(p.Changes))
+			for i, change := range p.Changes {
+				changes[i] = ParamChange{
+					Name:  change.Name,
+					Value: change.Value,
 				}
+			}
 
-				if coinTotalOwned[coin] == nil {
-					coinTotalOwned[coin] = big.NewInt(0)
+			votes := make([]Vote, len(p.Votes))
+			for i, vote := range p.Votes {
+				votes[i] = Vote{
+					Voter:  vote.Voter,
+					Option: vote.Option,
 				}
-				coinTotalOwned[coin].Add(coinTotalOwned[coin], value)
 			}
 
-		}
-
-		if key[0] == coinPrefix[0] {
-			coin := s.GetStateCoin(types.StrToCoinSymbol(string(key[1:])))
-
-			totalBasecoinVolume.Add(totalBasecoinVolume, coin.ReserveBalance())
-			coinSupplies[coin.symbol] = coin.Volume()
-		}
-
-		if key[0] == frozenFundsPrefix[0] {
-			height := binary.BigEndian.Uint64(key[1:])
-			frozenFunds := s.GetStateFrozenFunds(height)
+			var upgrade []UpgradePlan
+			if p.Upgrade != nil {
+				upgrade = append(upgrade, UpgradePlan{
+					Name:   p.Upgrade.Name,
+					Height: p.Upgrade.Height,
+					Info:   p.Upgrade.Info,
+				})
+			}
 
-			for _, frozenFund := range frozenFunds.List() {
-				if frozenFund.Coin.IsBaseCoin() {
-					totalBasecoinVolume.Add(totalBasecoinVolume, frozenFund.Value)
-					continue
-				}
+			proposals.data.List = append(proposals.data.List, Proposal{
+				ID:              p.ID,
+				Proposer:        p.Proposer,
+				Changes:         changes,
+				SubmitHeight:    p.SubmitHeight,
+				VotingEndHeight: p.VotingEndHeight,
+				Votes:           votes,
+				Upgrade:         upgrade,
+			})
 
-				if coinTotalOwned[frozenFund.Coin] == nil {
-					coinTotalOwned[frozenFund.Coin] = big.NewInt(0)
-				}
-				coinTotalOwned[frozenFund.Coin].Add(coinTotalOwned[frozenFund.Coin], frozenFund.Value)
+			if p.ID > proposals.data.LastID {
+				proposals.data.LastID = p.ID
 			}
 		}
-
-		return false
-	})
-
-	candidates := s.getStateCandidates()
-	if candsCount := len(candidates.data); candsCount > validators.GetCandidatesCountForBlock(height) {
-		return fmt.Errorf("too many candidates in blockchain. Expected %d, got %d",
-			validators.GetCandidatesCountForBlock(height), candsCount)
+		s.setStateProposals(proposals)
+		s.MarkStateProposalsDirty()
 	}
 
-	for _, candidate := range candidates.data {
-		for _, stake := range candidate.Stakes {
-			if stake.Coin.IsBaseCoin() {
-				totalBasecoinVolume.Add(totalBasecoinVolume, stake.Value)
-				continue
-			}
+	if len(appState.AppliedUpgrades) > 0 || appState.UpgradePlan != nil {
+		upgrade := newUpgrade(Upgrade{}, s.MarkStateUpgradeDirty)
+		for _, name := range appState.AppliedUpgrades {
+			upgrade.data.Applied = append(upgrade.data.Applied, AppliedUpgrade{
+				Name:   name,
+				Height: 0,
+			})
+		}
 
-			if coinTotalOwned[stake.Coin] == nil {
-				coinTotalOwned[stake.Coin] = big.NewInt(0)
-			}
-			coinTotalOwned[stake.Coin].Add(coinTotalOwned[stake.Coin], stake.Value)
+		if appState.UpgradePlan != nil {
+			upgrade.data.Plan = []UpgradePlan{{
+				Name:   appState.UpgradePlan.Name,
+				Height: appState.UpgradePlan.Height,
+				Info:   appState.UpgradePlan.Info,
+			}}
 		}
-	}
 
-	vals := s.getStateValidators()
-	if valsCount := len(vals.data); valsCount > validators.GetValidatorsCountForBlock(height) {
-		return fmt.Errorf("too many validators in blockchain. Expected %d, got %d",
-			validators.GetValidatorsCountForBlock(height), valsCount)
+		s.setStateUpgrade(upgrade)
+		s.MarkStateUpgradeDirty()
 	}
 
-	for _, val := range vals.data {
-		totalBasecoinVolume.Add(totalBasecoinVolume, val.AccumReward)
+	for _, settings := range appState.AccountSettings {
+		s.SetCompoundRewards(settings.Address, settings.CompoundRewards)
+		s.SetRewardAddress(settings.Address, settings.RewardAddress)
 	}
 
-	predictedBasecoinVolume := big.NewInt(0)
-	predictedBasecoinVolume.Add(predictedBasecoinVolume, rewards.BeforeGenesis)
-	for i := uint64(1); i < height; i++ {
-		predictedBasecoinVolume.Add(predictedBasecoinVolume, rewards.GetRewardForBlock(i))
+	for _, r := range appState.Redelegations {
+		s.GetOrNewStateRedelegations(r.Height).addRedelegation(Redelegation{
+			Address:          r.Address,
+			FromCandidateKey: r.FromCandidateKey,
+			ToCandidateKey:   r.ToCandidateKey,
+			Coin:             r.Coin,
+			Value:            big.NewInt(0).Set(r.Value),
+			UnbondHeight:     r.UnbondHeight,
+		})
 	}
-	predictedBasecoinVolume.Sub(predictedBasecoinVolume, s.GetTotalSlashed())
-	predictedBasecoinVolume.Add(predictedBasecoinVolume, GenesisAlloc)
 
-	if height >= upgrades.UpgradeBlock0 {
-		d, _ := big.NewInt(0).SetString("35703071844419651412692", 10)
-		predictedBasecoinVolume.Sub(predictedBasecoinVolume, d)
+	for _, change := range appState.CommissionChanges {
+		s.SetCandidateCommissionChange(change.PubKey, change.Commission, change.Height)
 	}
 
-	delta := big.NewInt(0).Sub(predictedBasecoinVolume, totalBasecoinVolume)
-
-	if delta.Cmp(big.NewInt(0)) != 0 {
-		e := fmt.Errorf("smth wrong with total base coins in blockchain. Expected total supply to be %s, got %s",
-			predictedBasecoinVolume, totalBasecoinVolume)
-
-		if delta.Cmp(helpers.BipToPip(big.NewInt(1000))) == 1 {
-			println(fmt.Sprintf("CRITICAL INVARIANTS FAILURE (H:%d): %s", height, e))
-			os.Exit(1)
-		}
-
-		return e
+	for _, change := range appState.PubKeyChanges {
+		s.SetCandidatePubKeyChange(change.PubKey, change.NewPubKey)
 	}
 
-	for coin, volume := range coinSupplies {
-		if volume.Cmp(coinTotalOwned[coin]) != 0 {
-			return fmt.Errorf("smth wrong with %s coin in blockchain. Total supply (%s) does not match total owned (%s)",
-				coin, volume, coinTotalOwned[coin])
-		}
+	for _, retired := range appState.RetiredPubKeys {
+		s.setRetiredPubKey(RetiredPubKey{
+			PubKey:    retired.PubKey,
+			NewPubKey: retired.NewPubKey,
+		})
 	}
-
-	return nil
 }
 
 func (s *StateDB) Height() uint64 {
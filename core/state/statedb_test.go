@@ -3,7 +3,9 @@ package state
 import (
 	"bytes"
 	"encoding/hex"
+	"github.com/MinterTeam/minter-go-node/config"
 	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
 	"github.com/MinterTeam/minter-go-node/helpers"
 	"github.com/tendermint/tendermint/libs/db"
 	"math/big"
@@ -135,3 +137,214 @@ func TestStateDB_GetEmptyBalances(t *testing.T) {
 		t.Errorf("Balances of %s are not like expected", address.String())
 	}
 }
+
+func TestStateDB_PayRewardsCompounded(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	state := getState()
+
+	address := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+	pubkey := make([]byte, 32)
+	stake := helpers.BipToPip(big.NewInt(100))
+
+	state.CreateCandidate(address, address, pubkey, 0, 0, types.GetBaseCoin(), stake)
+	state.CreateValidator(address, pubkey, 0, 0, types.GetBaseCoin(), stake)
+	state.SetCompoundRewards(address, true)
+	state.AddAccumReward(pubkey, helpers.BipToPip(big.NewInt(100)))
+
+	state.PayRewards()
+
+	// 10% goes to DAO and 10% to developers
+	expected := helpers.BipToPip(big.NewInt(180))
+	value := state.GetStateCandidate(pubkey).GetStakeOfAddress(address, types.GetBaseCoin()).Value
+	if value.Cmp(expected) != 0 {
+		t.Errorf("Stake of %s should be %s, got %s", address.String(), expected, value)
+	}
+
+	balance := state.GetBalance(address, types.GetBaseCoin())
+	if balance.Cmp(types.Big0) != 0 {
+		t.Errorf("Balance of %s should be 0, got %s", address.String(), balance)
+	}
+}
+
+func TestStateDB_PayRewardsToRewardAddress(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	state := getState()
+
+	address := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+	rewardAddress := types.Address{1}
+	pubkey := make([]byte, 32)
+	stake := helpers.BipToPip(big.NewInt(100))
+
+	state.CreateCandidate(address, address, pubkey, 0, 0, types.GetBaseCoin(), stake)
+	state.CreateValidator(address, pubkey, 0, 0, types.GetBaseCoin(), stake)
+	state.SetRewardAddress(address, rewardAddress)
+	state.AddAccumReward(pubkey, helpers.BipToPip(big.NewInt(100)))
+
+	state.PayRewards()
+
+	// 10% goes to DAO and 10% to developers
+	expected := helpers.BipToPip(big.NewInt(80))
+	balance := state.GetBalance(rewardAddress, types.GetBaseCoin())
+	if balance.Cmp(expected) != 0 {
+		t.Errorf("Balance of %s should be %s, got %s", rewardAddress.String(), expected, balance)
+	}
+
+	balance = state.GetBalance(address, types.GetBaseCoin())
+	if balance.Cmp(types.Big0) != 0 {
+		t.Errorf("Balance of %s should be 0, got %s", address.String(), balance)
+	}
+}
+
+func TestStateDB_TallyProposals(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	state := getState()
+
+	address := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+	pubkey := make([]byte, 32)
+	stake := helpers.BipToPip(big.NewInt(100))
+
+	state.CreateCandidate(address, address, pubkey, 0, 0, types.GetBaseCoin(), stake)
+	state.CreateValidator(address, pubkey, 0, 0, types.GetBaseCoin(), stake)
+
+	id := state.AddProposal(address, []ParamChange{
+		{Name: ParamUnbondPeriod, Value: big.NewInt(2 * UnbondPeriod)},
+	}, nil, 1)
+
+	state.VoteProposal(id, address, VoteYes)
+
+	proposal := state.GetProposal(id)
+	if proposal == nil {
+		t.Fatalf("Proposal %d not found", id)
+	}
+
+	state.TallyProposals(proposal.VotingEndHeight - 1)
+	if state.GetProposal(id) == nil {
+		t.Fatalf("Proposal %d should be active until the end of voting period", id)
+	}
+
+	state.TallyProposals(proposal.VotingEndHeight)
+	if state.GetProposal(id) != nil {
+		t.Fatalf("Proposal %d should be finished", id)
+	}
+
+	if state.GetParams().UnbondPeriod != 2*UnbondPeriod {
+		t.Errorf("Unbond period should be %d, got %d", 2*UnbondPeriod, state.GetParams().UnbondPeriod)
+	}
+}
+
+func TestStateDB_TallyUpgradeProposal(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	state := getState()
+
+	address := types.HexToAddress("Mx02003587993aba5276925c058ba082d209e61cbb")
+	pubkey := make([]byte, 32)
+	stake := helpers.BipToPip(big.NewInt(100))
+
+	state.CreateCandidate(address, address, pubkey, 0, 0, types.GetBaseCoin(), stake)
+	state.CreateValidator(address, pubkey, 0, 0, types.GetBaseCoin(), stake)
+
+	plan := UpgradePlan{
+		Name:   "v2",
+		Height: DefaultParams().ProposalVotingPeriod + 100,
+		Info:   "https://github.com/MinterTeam/minter-go-node/releases",
+	}
+
+	id := state.AddProposal(address, nil, []UpgradePlan{plan}, 1)
+	state.VoteProposal(id, address, VoteYes)
+	state.TallyProposals(state.GetProposal(id).VotingEndHeight)
+
+	scheduled := state.GetUpgradePlan()
+	if scheduled == nil || *scheduled != plan {
+		t.Fatalf("Upgrade plan is not correct. Expected %v, got %v", plan, scheduled)
+	}
+
+	state.ApplyUpgradePlan(plan.Height)
+
+	if state.GetUpgradePlan() != nil {
+		t.Fatalf("Upgrade plan should be removed after applying")
+	}
+
+	if !state.IsUpgradeApplied(plan.Name) {
+		t.Fatalf("Upgrade %s should be applied", plan.Name)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	memDB := db.NewMemDB()
+	state, err := New(0, memDB, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := types.Address{1}
+	var hashes [][]byte
+	for i := 0; i < 3; i++ {
+		state.AddBalance(address, types.GetBaseCoin(), big.NewInt(1))
+
+		hash, _, err := state.Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if err := Rollback(memDB, 2, hashes[0]); err == nil {
+		t.Fatal("Rollback with wrong hash should fail")
+	}
+
+	if err := Rollback(memDB, 2, hashes[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := New(3, memDB, true); err == nil {
+		t.Fatal("State above rollback height should be removed")
+	}
+
+	state, err = New(2, memDB, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if balance := state.GetBalance(address, types.GetBaseCoin()); balance.Cmp(big.NewInt(2)) != 0 {
+		t.Fatalf("Balance should be 2, got %s", balance)
+	}
+}
+
+func TestRollback_Pruned(t *testing.T) {
+	memDB := db.NewMemDB()
+	state, err := New(0, memDB, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hashes [][]byte
+	for i := 0; i < 3; i++ {
+		state.AddBalance(types.Address{1}, types.GetBaseCoin(), big.NewInt(1))
+
+		hash, _, err := state.Commit()
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	if err := Rollback(memDB, 2, hashes[1]); err == nil {
+		t.Fatal("Rollback to pruned state should fail")
+	}
+
+	if _, err := New(3, memDB, false); err != nil {
+		t.Fatalf("State should not be changed by failed rollback: %v", err)
+	}
+}
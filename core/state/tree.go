@@ -12,6 +12,7 @@ type Tree interface {
 	Remove(key []byte) ([]byte, bool)
 	LoadVersion(targetVersion int64) (int64, error)
 	LazyLoadVersion(targetVersion int64) (int64, error)
+	LoadVersionForOverwriting(targetVersion int64) (int64, error)
 	SaveVersion() ([]byte, int64, error)
 	DeleteVersion(version int64) error
 	GetImmutable() *ImmutableTree
@@ -19,6 +20,7 @@ type Tree interface {
 	Version() int64
 	Hash() []byte
 	Iterate(fn func(key []byte, value []byte) bool) (stopped bool)
+	IterateRange(start, end []byte, ascending bool, fn func(key []byte, value []byte) bool) (stopped bool)
 }
 
 func NewMutableTree(db dbm.DB) *MutableTree {
@@ -48,6 +50,10 @@ func (t *MutableTree) Iterate(fn func(key []byte, value []byte) bool) (stopped b
 	return t.tree.Iterate(fn)
 }
 
+func (t *MutableTree) IterateRange(start, end []byte, ascending bool, fn func(key []byte, value []byte) bool) (stopped bool) {
+	return t.tree.IterateRange(start, end, ascending, fn)
+}
+
 func (t *MutableTree) Hash() []byte {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
@@ -106,6 +112,13 @@ func (t *MutableTree) LazyLoadVersion(targetVersion int64) (int64, error) {
 	return t.tree.LazyLoadVersion(targetVersion)
 }
 
+func (t *MutableTree) LoadVersionForOverwriting(targetVersion int64) (int64, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.tree.LoadVersionForOverwriting(targetVersion)
+}
+
 func (t *MutableTree) SaveVersion() ([]byte, int64, error) {
 	t.lock.Lock()
 	defer t.lock.Unlock()
@@ -134,6 +147,10 @@ func (t *ImmutableTree) Iterate(fn func(key []byte, value []byte) bool) (stopped
 	return t.tree.Iterate(fn)
 }
 
+func (t *ImmutableTree) IterateRange(start, end []byte, ascending bool, fn func(key []byte, value []byte) bool) (stopped bool) {
+	return t.tree.IterateRange(start, end, ascending, fn)
+}
+
 func (t *ImmutableTree) Hash() []byte {
 	return t.tree.Hash()
 }
@@ -170,6 +187,10 @@ func (t *ImmutableTree) LazyLoadVersion(targetVersion int64) (int64, error) {
 	panic("Not implemented")
 }
 
+func (t *ImmutableTree) LoadVersionForOverwriting(targetVersion int64) (int64, error) {
+	panic("Not implemented")
+}
+
 func (t *ImmutableTree) SaveVersion() ([]byte, int64, error) {
 	panic("Not implemented")
 }
@@ -108,7 +108,7 @@ func (data CreateCoinData) Run(tx *Transaction, context *state.StateDB, isCheck
 	}
 
 	commissionInBaseCoin := big.NewInt(0).Mul(big.NewInt(int64(tx.GasPrice)), big.NewInt(data.Gas()))
-	commissionInBaseCoin.Mul(commissionInBaseCoin, CommissionMultiplier)
+	commissionInBaseCoin.Mul(commissionInBaseCoin, tx.getCommissionMultiplier())
 	commission := big.NewInt(0).Set(commissionInBaseCoin)
 
 	if tx.GasCoin != types.GetBaseCoin() {
@@ -22,6 +22,9 @@ type DeclareCandidacyData struct {
 	Commission uint             `json:"commission"`
 	Coin       types.CoinSymbol `json:"coin"`
 	Stake      *big.Int         `json:"stake"`
+
+	// Profile is optional, at most one item is allowed
+	Profile []CandidateProfile `json:"profile,omitempty" rlp:"tail"`
 }
 
 func (data DeclareCandidacyData) TotalSpend(tx *Transaction, context *state.StateDB) (TotalSpends, []Conversion, *big.Int, *Response) {
@@ -53,12 +56,37 @@ func (data DeclareCandidacyData) BasicCheck(tx *Transaction, context *state.Stat
 			Log:  fmt.Sprintf("Candidate with such public key (%s) already exists", data.PubKey.String())}
 	}
 
+	if context.IsCandidateTombstoned(data.PubKey) {
+		return &Response{
+			Code: code.CandidateTombstoned,
+			Log:  fmt.Sprintf("Candidate with such public key (%s) is tombstoned for double signing", data.PubKey.String())}
+	}
+
+	// key may be reserved by pending key change of another candidate
+	if context.IsPubKeyInUse(data.PubKey) {
+		return &Response{
+			Code: code.PubKeyInUse,
+			Log:  fmt.Sprintf("Public key (%s) is already in use", data.PubKey.String())}
+	}
+
 	if data.Commission < minCommission || data.Commission > maxCommission {
 		return &Response{
 			Code: code.WrongCommission,
 			Log:  fmt.Sprintf("Commission should be between 0 and 100")}
 	}
 
+	if len(data.Profile) > 1 {
+		return &Response{
+			Code: code.InvalidCandidateProfile,
+			Log:  fmt.Sprintf("Only one candidate profile is allowed")}
+	}
+
+	for _, profile := range data.Profile {
+		if response := profile.check(); response != nil {
+			return response
+		}
+	}
+
 	return nil
 }
 
@@ -68,7 +96,12 @@ func (data DeclareCandidacyData) String() string {
 }
 
 func (data DeclareCandidacyData) Gas() int64 {
-	return commissions.DeclareCandidacyTx
+	gas := commissions.DeclareCandidacyTx
+	for _, profile := range data.Profile {
+		gas += profile.gas()
+	}
+
+	return gas
 }
 
 func (data DeclareCandidacyData) Run(tx *Transaction, context *state.StateDB, isCheck bool, rewardPool *big.Int, currentBlock uint64) Response {
@@ -87,8 +120,14 @@ func (data DeclareCandidacyData) Run(tx *Transaction, context *state.StateDB, is
 			Log:  fmt.Sprintf("Given stake is too low")}
 	}
 
+	if minSelfStake := context.GetParams().MinSelfStake; minSelfStake != nil && stakeBipValue(context, data.Coin, data.Stake).Cmp(minSelfStake) < 0 {
+		return Response{
+			Code: code.SelfStakeTooLow,
+			Log:  fmt.Sprintf("Self-stake should be at least %s bip", minSelfStake)}
+	}
+
 	commissionInBaseCoin := big.NewInt(0).Mul(big.NewInt(int64(tx.GasPrice)), big.NewInt(tx.Gas()))
-	commissionInBaseCoin.Mul(commissionInBaseCoin, CommissionMultiplier)
+	commissionInBaseCoin.Mul(commissionInBaseCoin, tx.getCommissionMultiplier())
 	commission := big.NewInt(0).Set(commissionInBaseCoin)
 
 	if !tx.GasCoin.IsBaseCoin() {
@@ -136,6 +175,9 @@ func (data DeclareCandidacyData) Run(tx *Transaction, context *state.StateDB, is
 		context.SubBalance(sender, data.Coin, data.Stake)
 		context.SubBalance(sender, tx.GasCoin, commission)
 		context.CreateCandidate(data.Address, sender, data.PubKey, data.Commission, uint(currentBlock), data.Coin, data.Stake)
+		for _, profile := range data.Profile {
+			context.SetCandidateProfile(data.PubKey, profile.toState())
+		}
 		context.SetNonce(sender, tx.Nonce)
 	}
 
@@ -1,11 +1,13 @@
 package transaction
 
 import (
+	"github.com/MinterTeam/minter-go-node/core/code"
 	"github.com/MinterTeam/minter-go-node/core/state"
 	"github.com/MinterTeam/minter-go-node/core/types"
 	"github.com/MinterTeam/minter-go-node/crypto"
 	"github.com/MinterTeam/minter-go-node/helpers"
 	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/MinterTeam/minter-go-node/upgrades"
 	"math/big"
 	"sync"
 	"testing"
@@ -98,3 +100,62 @@ func TestDeclareCandidacyTx(t *testing.T) {
 		t.Fatalf("Incorrect candidate status")
 	}
 }
+
+func TestDeclareCandidacyTxProfileBeforeUpgrade(t *testing.T) {
+	makeTx := func(profiles []CandidateProfile) ([]byte, *state.StateDB) {
+		cState := getState()
+
+		privateKey, _ := crypto.GenerateKey()
+		addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+		cState.AddBalance(addr, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1000000)))
+
+		pkey, _ := crypto.GenerateKey()
+		encodedData, err := rlp.EncodeToBytes(DeclareCandidacyData{
+			Address:    addr,
+			PubKey:     crypto.FromECDSAPub(&pkey.PublicKey)[:32],
+			Commission: 10,
+			Coin:       types.GetBaseCoin(),
+			Stake:      helpers.BipToPip(big.NewInt(100)),
+			Profile:    profiles,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tx := Transaction{
+			Nonce:         1,
+			GasPrice:      1,
+			ChainID:       types.CurrentChainID,
+			GasCoin:       types.GetBaseCoin(),
+			Type:          TypeDeclareCandidacy,
+			Data:          encodedData,
+			SignatureType: SigTypeSingle,
+		}
+
+		if err := tx.Sign(privateKey); err != nil {
+			t.Fatal(err)
+		}
+
+		encodedTx, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return encodedTx, cState
+	}
+
+	profile := CandidateProfile{Moniker: "validator"}
+
+	// before the upgrade profile is rejected as undecodable data before any other check
+	encodedTx, cState := makeTx([]CandidateProfile{profile, profile})
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), 0, sync.Map{}, 0)
+	if response.Code != code.DecodeError {
+		t.Fatalf("Response code is not correct. Expected %d, got %d", code.DecodeError, response.Code)
+	}
+
+	encodedTx, cState = makeTx([]CandidateProfile{profile})
+	response = RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+	if response.Code != code.OK {
+		t.Fatalf("Response code is not 0. Error %s", response.Log)
+	}
+}
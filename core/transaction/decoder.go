@@ -26,6 +26,15 @@ func init() {
 	TxDecoder.RegisterType(TypeCreateMultisig, CreateMultisigData{})
 	TxDecoder.RegisterType(TypeMultisend, MultisendData{})
 	TxDecoder.RegisterType(TypeEditCandidate, EditCandidateData{})
+	TxDecoder.RegisterType(TypeEditCommission, EditCommissionData{})
+	TxDecoder.RegisterType(TypeRedelegate, RedelegateData{})
+	TxDecoder.RegisterType(TypeSetCompoundRewards, SetCompoundRewardsData{})
+	TxDecoder.RegisterType(TypeSetRewardAddress, SetRewardAddressData{})
+	TxDecoder.RegisterType(TypeEditCandidateProfile, EditCandidateProfileData{})
+	TxDecoder.RegisterType(TypeSubmitProposal, SubmitProposalData{})
+	TxDecoder.RegisterType(TypeVoteProposal, VoteProposalData{})
+	TxDecoder.RegisterType(TypeUnjail, UnjailData{})
+	TxDecoder.RegisterType(TypeEditCandidatePubKey, EditCandidatePubKeyData{})
 }
 
 type Decoder struct {
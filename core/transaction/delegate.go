@@ -56,9 +56,25 @@ func (data DelegateData) BasicCheck(tx *Transaction, context *state.StateDB) *Re
 			Log:  fmt.Sprintf("Stake is too low")}
 	}
 
+	if context.IsStakeShareExceeded(data.PubKey, stakeBipValue(context, data.Coin, data.Value)) {
+		return &Response{
+			Code: code.StakeShareExceeded,
+			Log:  fmt.Sprintf("Candidate's share of total stake would exceed %d%%", context.GetParams().MaxValidatorStakeShare)}
+	}
+
 	return nil
 }
 
+// stakeBipValue returns bip value of stake with given coin and value as if it is delegated
+func stakeBipValue(context *state.StateDB, coin types.CoinSymbol, value *big.Int) *big.Int {
+	stake := state.Stake{
+		Coin:  coin,
+		Value: value,
+	}
+
+	return stake.CalcSimulatedBipValue(context)
+}
+
 func (data DelegateData) String() string {
 	return fmt.Sprintf("DELEGATE pubkey:%s ",
 		hexutil.Encode(data.PubKey))
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"github.com/MinterTeam/minter-go-node/core/code"
 	"github.com/MinterTeam/minter-go-node/core/state"
 	"github.com/MinterTeam/minter-go-node/core/types"
 	"github.com/MinterTeam/minter-go-node/crypto"
@@ -92,3 +93,58 @@ func TestDelegateTx(t *testing.T) {
 		t.Fatalf("Stake value is not corrent. Expected %s, got %s", value, stake.Value)
 	}
 }
+
+func TestDelegateTxWithExceededStakeShare(t *testing.T) {
+	cState := getState()
+
+	pubkey := createTestCandidate(cState)
+
+	params := cState.GetParams()
+	params.MaxValidatorStakeShare = 50
+	cState.SetParams(params)
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	coin := types.GetBaseCoin()
+
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	data := DelegateData{
+		PubKey: pubkey,
+		Coin:   coin,
+		Value:  helpers.BipToPip(big.NewInt(100)),
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeDelegate,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), 0, sync.Map{}, 0)
+
+	if response.Code != code.StakeShareExceeded {
+		t.Fatalf("Response code is not %d. Got %d", code.StakeShareExceeded, response.Code)
+	}
+}
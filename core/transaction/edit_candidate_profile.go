@@ -0,0 +1,157 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/commissions"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/formula"
+	"github.com/tendermint/tendermint/libs/common"
+	"math/big"
+	"unicode/utf8"
+)
+
+const (
+	maxMonikerLength         = 64
+	maxWebsiteLength         = 128
+	maxDescriptionLength     = 512
+	maxSecurityContactLength = 128
+	maxLogoHashLength        = 64
+)
+
+type CandidateProfile struct {
+	Moniker         string `json:"moniker"`
+	Website         string `json:"website"`
+	Description     string `json:"description"`
+	SecurityContact string `json:"security_contact"`
+	LogoHash        string `json:"logo_hash"`
+}
+
+func (profile CandidateProfile) check() *Response {
+	fields := []struct {
+		name   string
+		value  string
+		maxLen int
+	}{
+		{"moniker", profile.Moniker, maxMonikerLength},
+		{"website", profile.Website, maxWebsiteLength},
+		{"description", profile.Description, maxDescriptionLength},
+		{"security contact", profile.SecurityContact, maxSecurityContactLength},
+		{"logo hash", profile.LogoHash, maxLogoHashLength},
+	}
+
+	for _, field := range fields {
+		if len(field.value) > field.maxLen {
+			return &Response{
+				Code: code.InvalidCandidateProfile,
+				Log:  fmt.Sprintf("Candidate's %s should not be longer than %d bytes", field.name, field.maxLen)}
+		}
+
+		if !utf8.ValidString(field.value) {
+			return &Response{
+				Code: code.InvalidCandidateProfile,
+				Log:  fmt.Sprintf("Candidate's %s is not a valid UTF-8 string", field.name)}
+		}
+	}
+
+	return nil
+}
+
+func (profile CandidateProfile) gas() int64 {
+	return int64(profile.toState().Size()) * commissions.CandidateProfileByte
+}
+
+func (profile CandidateProfile) toState() state.CandidateProfile {
+	return state.CandidateProfile{
+		Moniker:         profile.Moniker,
+		Website:         profile.Website,
+		Description:     profile.Description,
+		SecurityContact: profile.SecurityContact,
+		LogoHash:        profile.LogoHash,
+	}
+}
+
+type EditCandidateProfileData struct {
+	PubKey  types.Pubkey     `json:"pub_key"`
+	Profile CandidateProfile `json:"profile"`
+}
+
+func (data EditCandidateProfileData) GetPubKey() types.Pubkey {
+	return data.PubKey
+}
+
+func (data EditCandidateProfileData) TotalSpend(tx *Transaction, context *state.StateDB) (TotalSpends, []Conversion, *big.Int, *Response) {
+	panic("implement me")
+}
+
+func (data EditCandidateProfileData) BasicCheck(tx *Transaction, context *state.StateDB) *Response {
+	if response := checkCandidateOwnership(data, tx, context); response != nil {
+		return response
+	}
+
+	return data.Profile.check()
+}
+
+func (data EditCandidateProfileData) String() string {
+	return fmt.Sprintf("EDIT CANDIDATE PROFILE pubkey: %x",
+		data.PubKey)
+}
+
+func (data EditCandidateProfileData) Gas() int64 {
+	return commissions.EditCandidateProfile + data.Profile.gas()
+}
+
+func (data EditCandidateProfileData) Run(tx *Transaction, context *state.StateDB, isCheck bool, rewardPool *big.Int, currentBlock uint64) Response {
+	sender, _ := tx.Sender()
+
+	response := data.BasicCheck(tx, context)
+	if response != nil {
+		return *response
+	}
+
+	commissionInBaseCoin := tx.CommissionInBaseCoin()
+	commission := big.NewInt(0).Set(commissionInBaseCoin)
+
+	if !tx.GasCoin.IsBaseCoin() {
+		coin := context.GetStateCoin(tx.GasCoin)
+
+		if coin.ReserveBalance().Cmp(commissionInBaseCoin) < 0 {
+			return Response{
+				Code: code.CoinReserveNotSufficient,
+				Log:  fmt.Sprintf("Coin reserve balance is not sufficient for transaction. Has: %s, required %s", coin.ReserveBalance().String(), commissionInBaseCoin.String())}
+		}
+
+		commission = formula.CalculateSaleAmount(coin.Volume(), coin.ReserveBalance(), coin.Data().Crr, commissionInBaseCoin)
+	}
+
+	if context.GetBalance(sender, tx.GasCoin).Cmp(commission) < 0 {
+		return Response{
+			Code: code.InsufficientFunds,
+			Log:  fmt.Sprintf("Insufficient funds for sender account: %s. Wanted %s %s", sender.String(), commission, tx.GasCoin)}
+	}
+
+	if !isCheck {
+		rewardPool.Add(rewardPool, commissionInBaseCoin)
+
+		context.SubCoinReserve(tx.GasCoin, commissionInBaseCoin)
+		context.SubCoinVolume(tx.GasCoin, commission)
+
+		context.SubBalance(sender, tx.GasCoin, commission)
+		context.SetCandidateProfile(data.PubKey, data.Profile.toState())
+		context.SetNonce(sender, tx.Nonce)
+	}
+
+	tags := common.KVPairs{
+		common.KVPair{Key: []byte("tx.type"), Value: []byte(hex.EncodeToString([]byte{byte(TypeEditCandidateProfile)}))},
+		common.KVPair{Key: []byte("tx.from"), Value: []byte(hex.EncodeToString(sender[:]))},
+	}
+
+	return Response{
+		Code:      code.OK,
+		GasUsed:   tx.Gas(),
+		GasWanted: tx.Gas(),
+		Tags:      tags,
+	}
+}
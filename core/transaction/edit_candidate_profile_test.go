@@ -0,0 +1,137 @@
+package transaction
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/crypto"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/MinterTeam/minter-go-node/upgrades"
+	"math/big"
+	"math/rand"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestEditCandidateProfileTx(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	data := EditCandidateProfileData{
+		PubKey: pubkey,
+		Profile: CandidateProfile{
+			Moniker: "Validator",
+			Website: "https://example.com",
+		},
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeEditCandidateProfile,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+
+	if response.Code != 0 {
+		t.Fatalf("Response code is not 0. Error %s", response.Log)
+	}
+
+	targetBalance, _ := big.NewInt(0).SetString("999998944000000000000000", 10)
+	balance := cState.GetBalance(addr, coin)
+	if balance.Cmp(targetBalance) != 0 {
+		t.Fatalf("Target %s balance is not correct. Expected %s, got %s", coin, targetBalance, balance)
+	}
+
+	profile := cState.GetCandidateProfile(pubkey)
+	if profile == nil {
+		t.Fatalf("Candidate profile not found")
+	}
+
+	if profile.Moniker != data.Profile.Moniker || profile.Website != data.Profile.Website {
+		t.Fatalf("Candidate profile is not correct")
+	}
+}
+
+func TestEditCandidateProfileTxTooLong(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	data := EditCandidateProfileData{
+		PubKey: pubkey,
+		Profile: CandidateProfile{
+			Moniker: strings.Repeat("a", maxMonikerLength+1),
+		},
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeEditCandidateProfile,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+
+	if response.Code != code.InvalidCandidateProfile {
+		t.Fatalf("Response code is not %d. Got %d", code.InvalidCandidateProfile, response.Code)
+	}
+}
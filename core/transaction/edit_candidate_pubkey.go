@@ -0,0 +1,123 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/commissions"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/formula"
+	"github.com/tendermint/tendermint/libs/common"
+	"math/big"
+)
+
+type EditCandidatePubKeyData struct {
+	PubKey    types.Pubkey `json:"pub_key"`
+	NewPubKey types.Pubkey `json:"new_pub_key"`
+}
+
+func (data EditCandidatePubKeyData) GetPubKey() types.Pubkey {
+	return data.PubKey
+}
+
+func (data EditCandidatePubKeyData) TotalSpend(tx *Transaction, context *state.StateDB) (TotalSpends, []Conversion, *big.Int, *Response) {
+	panic("implement me")
+}
+
+func (data EditCandidatePubKeyData) BasicCheck(tx *Transaction, context *state.StateDB) *Response {
+	if response := checkCandidateOwnership(data, tx, context); response != nil {
+		return response
+	}
+
+	if len(data.NewPubKey) != 32 {
+		return &Response{
+			Code: code.IncorrectPubKey,
+			Log:  fmt.Sprintf("Incorrect PubKey")}
+	}
+
+	if context.IsCandidateTombstoned(data.PubKey) {
+		return &Response{
+			Code: code.CandidateTombstoned,
+			Log:  fmt.Sprintf("Candidate is tombstoned for double signing and can not change its public key")}
+	}
+
+	if context.IsPubKeyChangeLocked(data.PubKey) {
+		return &Response{
+			Code: code.PubKeyChangeLocked,
+			Log:  fmt.Sprintf("Public key was changed recently, evidences against the previous one are still expected")}
+	}
+
+	if context.IsPubKeyInUse(data.NewPubKey) {
+		return &Response{
+			Code: code.PubKeyInUse,
+			Log:  fmt.Sprintf("Public key (%s) is already in use", data.NewPubKey.String())}
+	}
+
+	return nil
+}
+
+func (data EditCandidatePubKeyData) String() string {
+	return fmt.Sprintf("EDIT CANDIDATE PUBKEY pubkey: %x new pubkey: %x",
+		data.PubKey, data.NewPubKey)
+}
+
+func (data EditCandidatePubKeyData) Gas() int64 {
+	return commissions.EditCandidatePubKey
+}
+
+func (data EditCandidatePubKeyData) Run(tx *Transaction, context *state.StateDB, isCheck bool, rewardPool *big.Int, currentBlock uint64) Response {
+	sender, _ := tx.Sender()
+
+	response := data.BasicCheck(tx, context)
+	if response != nil {
+		return *response
+	}
+
+	commissionInBaseCoin := tx.CommissionInBaseCoin()
+	commission := big.NewInt(0).Set(commissionInBaseCoin)
+
+	if !tx.GasCoin.IsBaseCoin() {
+		coin := context.GetStateCoin(tx.GasCoin)
+
+		if coin.ReserveBalance().Cmp(commissionInBaseCoin) < 0 {
+			return Response{
+				Code: code.CoinReserveNotSufficient,
+				Log:  fmt.Sprintf("Coin reserve balance is not sufficient for transaction. Has: %s, required %s", coin.ReserveBalance().String(), commissionInBaseCoin.String())}
+		}
+
+		commission = formula.CalculateSaleAmount(coin.Volume(), coin.ReserveBalance(), coin.Data().Crr, commissionInBaseCoin)
+	}
+
+	if context.GetBalance(sender, tx.GasCoin).Cmp(commission) < 0 {
+		return Response{
+			Code: code.InsufficientFunds,
+			Log:  fmt.Sprintf("Insufficient funds for sender account: %s. Wanted %s %s", sender.String(), commission, tx.GasCoin)}
+	}
+
+	if !isCheck {
+		rewardPool.Add(rewardPool, commissionInBaseCoin)
+
+		context.SubCoinReserve(tx.GasCoin, commissionInBaseCoin)
+		context.SubCoinVolume(tx.GasCoin, commission)
+
+		context.SubBalance(sender, tx.GasCoin, commission)
+
+		// new key replaces the old one at the next update of validator set
+		context.SetCandidatePubKeyChange(data.PubKey, data.NewPubKey)
+
+		context.SetNonce(sender, tx.Nonce)
+	}
+
+	tags := common.KVPairs{
+		common.KVPair{Key: []byte("tx.type"), Value: []byte(hex.EncodeToString([]byte{byte(TypeEditCandidatePubKey)}))},
+		common.KVPair{Key: []byte("tx.from"), Value: []byte(hex.EncodeToString(sender[:]))},
+	}
+
+	return Response{
+		Code:      code.OK,
+		GasUsed:   tx.Gas(),
+		GasWanted: tx.Gas(),
+		Tags:      tags,
+	}
+}
@@ -0,0 +1,149 @@
+package transaction
+
+import (
+	"crypto/ecdsa"
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/crypto"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/MinterTeam/minter-go-node/upgrades"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestEditCandidatePubKeyTx(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	newPubkey := make([]byte, 32)
+	rand.Read(newPubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+	cState.SetCandidateProfile(pubkey, state.CandidateProfile{Moniker: "validator"})
+
+	response := runEditCandidatePubKeyTx(t, cState, privateKey, pubkey, newPubkey)
+
+	if response.Code != 0 {
+		t.Fatalf("Response code is not 0. Error %s", response.Log)
+	}
+
+	change := cState.GetCandidatePubKeyChange(pubkey)
+	if change == nil || change.NewPubKey.Compare(newPubkey) != 0 {
+		t.Fatalf("Pubkey change is not scheduled")
+	}
+
+	if !cState.IsPubKeyInUse(newPubkey) {
+		t.Fatalf("New pubkey should be reserved")
+	}
+
+	oldAddress := cState.GetStateCandidate(pubkey).GetAddress()
+
+	cState.ApplyCandidatePubKeyChanges()
+
+	if cState.CandidateExists(pubkey) {
+		t.Fatalf("Candidate with old pubkey should not exist")
+	}
+
+	candidate := cState.GetStateCandidate(newPubkey)
+	if candidate == nil {
+		t.Fatalf("Candidate with new pubkey not found")
+	}
+
+	if candidate.OwnerAddress != addr || candidate.Commission != 10 || len(candidate.Stakes) != 1 {
+		t.Fatalf("Candidate is not kept after pubkey change")
+	}
+
+	if profile := cState.GetCandidateProfile(newPubkey); profile == nil || profile.Moniker != "validator" {
+		t.Fatalf("Candidate profile is not moved to new pubkey")
+	}
+
+	if cState.GetCandidatePubKeyChange(pubkey) != nil {
+		t.Fatalf("Pubkey change should be applied")
+	}
+
+	if !cState.IsPubKeyInUse(pubkey) {
+		t.Fatalf("Old pubkey should be retired")
+	}
+
+	if cState.ResolveEvidenceAddress(oldAddress) != candidate.GetAddress() {
+		t.Fatalf("Evidence against old pubkey should be resolved to candidate")
+	}
+
+	if !cState.IsPubKeyChangeLocked(newPubkey) {
+		t.Fatalf("Pubkey change should be locked during evidence window")
+	}
+}
+
+func TestEditCandidatePubKeyTxToKeyInUse(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	otherPubkey := createTestCandidate(cState)
+
+	response := runEditCandidatePubKeyTx(t, cState, privateKey, pubkey, otherPubkey)
+
+	if response.Code != code.PubKeyInUse {
+		t.Fatalf("Response code is not %d. Got %d", code.PubKeyInUse, response.Code)
+	}
+}
+
+func runEditCandidatePubKeyTx(t *testing.T, cState *state.StateDB, privateKey *ecdsa.PrivateKey, pubkey []byte, newPubkey []byte) Response {
+	data := EditCandidatePubKeyData{
+		PubKey:    pubkey,
+		NewPubKey: newPubkey,
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       types.GetBaseCoin(),
+		Type:          TypeEditCandidatePubKey,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+}
@@ -0,0 +1,145 @@
+package transaction
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/crypto"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/MinterTeam/minter-go-node/upgrades"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestEditCommissionTx(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+	cState.CreateValidator(addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	data := EditCommissionData{
+		PubKey:     pubkey,
+		Commission: 20,
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeEditCommission,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	currentBlock := uint64(upgrades.UpgradeBlock2)
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), currentBlock, sync.Map{}, 0)
+
+	if response.Code != 0 {
+		t.Fatalf("Response code is not 0. Error %s", response.Log)
+	}
+
+	targetBalance, _ := big.NewInt(0).SetString("999990000000000000000000", 10)
+	balance := cState.GetBalance(addr, coin)
+	if balance.Cmp(targetBalance) != 0 {
+		t.Fatalf("Target %s balance is not correct. Expected %s, got %s", coin, targetBalance, balance)
+	}
+
+	change := cState.GetCandidateCommissionChange(pubkey)
+	if change == nil {
+		t.Fatalf("Commission change not found")
+	}
+
+	if change.Commission != 20 || change.Height != currentBlock+cState.GetParams().CommissionChangeNoticePeriod {
+		t.Fatalf("Commission change is not correct. Got %d at %d", change.Commission, change.Height)
+	}
+
+	cState.ApplyCandidateCommissionChanges(change.Height - 1)
+	if cState.GetStateCandidate(pubkey).Commission != 10 {
+		t.Fatalf("Commission has changed before notice period is over")
+	}
+
+	cState.ApplyCandidateCommissionChanges(change.Height)
+	if cState.GetStateCandidate(pubkey).Commission != 20 {
+		t.Fatalf("Commission has not changed")
+	}
+
+	if cState.GetCandidateCommissionChange(pubkey) != nil {
+		t.Fatalf("Commission change has not been removed")
+	}
+}
+
+func TestEditCommissionTxBeforeUpgrade(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	encodedData, err := rlp.EncodeToBytes(EditCommissionData{
+		PubKey:     pubkey,
+		Commission: 20,
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeEditCommission,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), 0, sync.Map{}, 0)
+
+	if response.Code != code.DecodeError {
+		t.Fatalf("Response code is not %d. Got %d", code.DecodeError, response.Code)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/MinterTeam/minter-go-node/core/state"
 	"github.com/MinterTeam/minter-go-node/core/types"
 	"github.com/MinterTeam/minter-go-node/log"
+	"github.com/MinterTeam/minter-go-node/upgrades"
 	"github.com/tendermint/tendermint/libs/common"
 	"math/big"
 	"sync"
@@ -54,12 +55,40 @@ func RunTx(context *state.StateDB,
 			Log:  err.Error()}
 	}
 
+	return RunDecodedTx(context, isCheck, rawTx, tx, rewardPool, currentBlock, currentMempool, minGasPrice)
+}
+
+// RunDecodedTx is like RunTx for transaction which is already decoded from rawTx, e.g. by CheckStateless
+func RunDecodedTx(context *state.StateDB,
+	isCheck bool,
+	rawTx []byte,
+	tx *Transaction,
+	rewardPool *big.Int,
+	currentBlock uint64,
+	currentMempool sync.Map,
+	minGasPrice uint32) Response {
+	// profile of declare candidacy is a trailing field which was not accepted by decoder before the upgrade
+	if data, ok := tx.decodedData.(*DeclareCandidacyData); ok && len(data.Profile) > 0 &&
+		currentBlock < upgrades.UpgradeBlock2 {
+		return Response{
+			Code: code.DecodeError,
+			Log:  fmt.Sprintf("Candidate profile is not active yet")}
+	}
+
 	if tx.ChainID != types.CurrentChainID {
 		return Response{
 			Code: code.WrongChainID,
 			Log:  "Wrong chain id"}
 	}
 
+	if tx.Type > TypeEditCandidate && currentBlock < upgrades.UpgradeBlock2 {
+		return Response{
+			Code: code.DecodeError,
+			Log:  fmt.Sprintf("tx type %x is not active yet", tx.Type)}
+	}
+
+	tx.SetCommissionMultiplier(context.GetParams().CommissionMultiplier)
+
 	if !context.CoinExists(tx.GasCoin) {
 		return Response{
 			Code: code.CoinNotExists,
@@ -89,6 +118,18 @@ func RunTx(context *state.StateDB,
 			Log:  fmt.Sprintf("TX service data length is over %d bytes", maxServiceDataLength)}
 	}
 
+	if tx.SignatureType == SigTypeSingle {
+		signers, err := RecoveredSigners.recover(rawTx, tx)
+		if err != nil {
+			return Response{
+				Code: code.DecodeError,
+				Log:  err.Error()}
+		}
+
+		recovered := signers[0]
+		tx.sender = &recovered
+	}
+
 	sender, err := tx.Sender()
 	if err != nil {
 		return Response{
@@ -119,25 +160,23 @@ func RunTx(context *state.StateDB,
 
 		multisigData := multisig.Multisig()
 
-		if len(tx.multisig.Signatures) > 32 || len(multisigData.Weights) < len(tx.multisig.Signatures) {
+		if len(tx.multisig.Signatures) > maxMultisigSignatures || len(multisigData.Weights) < len(tx.multisig.Signatures) {
+			return Response{
+				Code: code.IncorrectMultiSignature,
+				Log:  "Incorrect multi-signature"}
+		}
+
+		signers, err := RecoveredSigners.recover(rawTx, tx)
+		if err != nil {
 			return Response{
 				Code: code.IncorrectMultiSignature,
 				Log:  "Incorrect multi-signature"}
 		}
 
-		txHash := tx.Hash()
 		var totalWeight uint
 		var usedAccounts = map[types.Address]bool{}
 
-		for _, sig := range tx.multisig.Signatures {
-			signer, err := RecoverPlain(txHash, sig.R, sig.S, sig.V)
-
-			if err != nil {
-				return Response{
-					Code: code.IncorrectMultiSignature,
-					Log:  "Incorrect multi-signature"}
-			}
-
+		for _, signer := range signers {
 			if usedAccounts[signer] {
 				return Response{
 					Code: code.IncorrectMultiSignature,
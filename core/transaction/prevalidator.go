@@ -0,0 +1,66 @@
+package transaction
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Prevalidator runs stateless checks of transactions in a pool of workers. It lets expensive recovery of
+// signatures and decoding be done concurrently before transactions reach serial CheckTx, which then finds their
+// signers and decoded transactions in RecoveredSigners.
+type Prevalidator struct {
+	jobs chan prevalidatorJob
+}
+
+type prevalidatorJob struct {
+	rawTx  []byte
+	result *Response
+	done   *sync.WaitGroup
+}
+
+// NewPrevalidator starts pool of given number of workers. Number of CPUs is used if workers is not positive.
+func NewPrevalidator(workers int) *Prevalidator {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	p := &Prevalidator{
+		jobs: make(chan prevalidatorJob, workers),
+	}
+
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+
+	return p
+}
+
+func (p *Prevalidator) work() {
+	for job := range p.jobs {
+		var tx *Transaction
+		tx, *job.result = CheckStateless(job.rawTx)
+		if tx != nil {
+			RecoveredSigners.keepDecoded(job.rawTx, tx)
+		}
+		job.done.Done()
+	}
+}
+
+// Check runs stateless checks of raw transaction in the pool and waits for the result
+func (p *Prevalidator) Check(rawTx []byte) Response {
+	return p.CheckAll([][]byte{rawTx})[0]
+}
+
+// CheckAll runs stateless checks of raw transactions concurrently and returns their results in the same order
+func (p *Prevalidator) CheckAll(txs [][]byte) []Response {
+	results := make([]Response, len(txs))
+
+	var done sync.WaitGroup
+	done.Add(len(txs))
+	for i, rawTx := range txs {
+		p.jobs <- prevalidatorJob{rawTx: rawTx, result: &results[i], done: &done}
+	}
+	done.Wait()
+
+	return results
+}
@@ -147,7 +147,7 @@ func (data RedeemCheckData) Run(tx *Transaction, context *state.StateDB, isCheck
 	}
 
 	commissionInBaseCoin := big.NewInt(0).Mul(big.NewInt(int64(tx.GasPrice)), big.NewInt(tx.Gas()))
-	commissionInBaseCoin.Mul(commissionInBaseCoin, CommissionMultiplier)
+	commissionInBaseCoin.Mul(commissionInBaseCoin, tx.getCommissionMultiplier())
 	commission := big.NewInt(0).Set(commissionInBaseCoin)
 
 	if !decodedCheck.Coin.IsBaseCoin() {
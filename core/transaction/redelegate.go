@@ -0,0 +1,158 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/commissions"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/formula"
+	"github.com/MinterTeam/minter-go-node/hexutil"
+	"github.com/tendermint/tendermint/libs/common"
+	"math/big"
+)
+
+type RedelegateData struct {
+	FromPubKey types.Pubkey     `json:"from_pub_key"`
+	ToPubKey   types.Pubkey     `json:"to_pub_key"`
+	Coin       types.CoinSymbol `json:"coin"`
+	Value      *big.Int         `json:"value"`
+}
+
+func (data RedelegateData) TotalSpend(tx *Transaction, context *state.StateDB) (TotalSpends, []Conversion, *big.Int, *Response) {
+	panic("implement me")
+}
+
+func (data RedelegateData) BasicCheck(tx *Transaction, context *state.StateDB) *Response {
+	if data.FromPubKey == nil || data.ToPubKey == nil || data.Value == nil {
+		return &Response{
+			Code: code.DecodeError,
+			Log:  "Incorrect tx data"}
+	}
+
+	if !context.CoinExists(data.Coin) {
+		return &Response{
+			Code: code.CoinNotExists,
+			Log:  fmt.Sprintf("Coin %s not exists", data.Coin)}
+	}
+
+	if data.Value.Cmp(types.Big0) < 1 {
+		return &Response{
+			Code: code.StakeShouldBePositive,
+			Log:  fmt.Sprintf("Stake should be positive")}
+	}
+
+	if bytes.Equal(data.FromPubKey, data.ToPubKey) {
+		return &Response{
+			Code: code.SameCandidate,
+			Log:  fmt.Sprintf("Cannot redelegate stake to the same candidate")}
+	}
+
+	fromCandidate := context.GetStateCandidate(data.FromPubKey)
+	toCandidate := context.GetStateCandidate(data.ToPubKey)
+	if fromCandidate == nil || toCandidate == nil {
+		return &Response{
+			Code: code.CandidateNotFound,
+			Log:  fmt.Sprintf("Candidate with such public key not found")}
+	}
+
+	sender, _ := tx.Sender()
+	stake := fromCandidate.GetStakeOfAddress(sender, data.Coin)
+
+	if stake == nil {
+		return &Response{
+			Code: code.StakeNotFound,
+			Log:  fmt.Sprintf("Stake of current user not found")}
+	}
+
+	if stake.Value.Cmp(data.Value) < 0 {
+		return &Response{
+			Code: code.InsufficientStake,
+			Log:  fmt.Sprintf("Insufficient stake for sender account")}
+	}
+
+	if len(toCandidate.Stakes) >= state.MaxDelegatorsPerCandidate && !context.IsDelegatorStakeSufficient(sender, data.ToPubKey, data.Coin, data.Value) {
+		return &Response{
+			Code: code.TooLowStake,
+			Log:  fmt.Sprintf("Stake is too low")}
+	}
+
+	if context.IsStakeShareExceeded(data.ToPubKey, stakeBipValue(context, data.Coin, data.Value)) {
+		return &Response{
+			Code: code.StakeShareExceeded,
+			Log:  fmt.Sprintf("Candidate's share of total stake would exceed %d%%", context.GetParams().MaxValidatorStakeShare)}
+	}
+
+	return nil
+}
+
+func (data RedelegateData) String() string {
+	return fmt.Sprintf("REDELEGATE from:%s to:%s",
+		hexutil.Encode(data.FromPubKey), hexutil.Encode(data.ToPubKey))
+}
+
+func (data RedelegateData) Gas() int64 {
+	return commissions.RedelegateTx
+}
+
+func (data RedelegateData) Run(tx *Transaction, context *state.StateDB, isCheck bool, rewardPool *big.Int, currentBlock uint64) Response {
+	sender, _ := tx.Sender()
+
+	response := data.BasicCheck(tx, context)
+	if response != nil {
+		return *response
+	}
+
+	commissionInBaseCoin := tx.CommissionInBaseCoin()
+	commission := big.NewInt(0).Set(commissionInBaseCoin)
+
+	if !tx.GasCoin.IsBaseCoin() {
+		coin := context.GetStateCoin(tx.GasCoin)
+
+		if coin.ReserveBalance().Cmp(commissionInBaseCoin) < 0 {
+			return Response{
+				Code: code.CoinReserveNotSufficient,
+				Log:  fmt.Sprintf("Coin reserve balance is not sufficient for transaction. Has: %s, required %s", coin.ReserveBalance().String(), commissionInBaseCoin.String())}
+		}
+
+		commission = formula.CalculateSaleAmount(coin.Volume(), coin.ReserveBalance(), coin.Data().Crr, commissionInBaseCoin)
+	}
+
+	if context.GetBalance(sender, tx.GasCoin).Cmp(commission) < 0 {
+		return Response{
+			Code: code.InsufficientFunds,
+			Log:  fmt.Sprintf("Insufficient funds for sender account: %s. Wanted %s %s", sender.String(), commission, tx.GasCoin)}
+	}
+
+	if !isCheck {
+		// redelegated stake stays slashable for source candidate's misbehaviour during unbond period
+		slashableUntil := currentBlock + context.GetParams().UnbondPeriod
+
+		rewardPool.Add(rewardPool, commissionInBaseCoin)
+
+		context.SubCoinReserve(tx.GasCoin, commissionInBaseCoin)
+		context.SubCoinVolume(tx.GasCoin, commission)
+
+		context.SubBalance(sender, tx.GasCoin, commission)
+		context.SubStake(sender, data.FromPubKey, data.Coin, data.Value)
+		context.Delegate(sender, data.ToPubKey, data.Coin, big.NewInt(0).Set(data.Value))
+		context.CarryRedelegations(sender, data.FromPubKey, data.Coin, data.Value, data.ToPubKey, 0)
+		context.GetOrNewStateRedelegations(slashableUntil).AddRedelegation(sender, data.FromPubKey, data.ToPubKey,
+			data.Coin, data.Value)
+		context.SetNonce(sender, tx.Nonce)
+	}
+
+	tags := common.KVPairs{
+		common.KVPair{Key: []byte("tx.type"), Value: []byte(hex.EncodeToString([]byte{byte(TypeRedelegate)}))},
+		common.KVPair{Key: []byte("tx.from"), Value: []byte(hex.EncodeToString(sender[:]))},
+	}
+
+	return Response{
+		Code:      code.OK,
+		GasUsed:   tx.Gas(),
+		GasWanted: tx.Gas(),
+		Tags:      tags,
+	}
+}
@@ -0,0 +1,234 @@
+package transaction
+
+import (
+	"bytes"
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/crypto"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/MinterTeam/minter-go-node/upgrades"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestRedelegateTx(t *testing.T) {
+	cState := getState()
+
+	fromPubkey := createTestCandidate(cState)
+	toPubkey := createTestCandidate(cState)
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	value := helpers.BipToPip(big.NewInt(100))
+	cState.Delegate(addr, fromPubkey, coin, value)
+
+	data := RedelegateData{
+		FromPubKey: fromPubkey,
+		ToPubKey:   toPubkey,
+		Coin:       coin,
+		Value:      value,
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeRedelegate,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	currentBlock := uint64(upgrades.UpgradeBlock2)
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), currentBlock, sync.Map{}, 0)
+
+	if response.Code != 0 {
+		t.Fatalf("Response code is not 0. Error %s", response.Log)
+	}
+
+	targetBalance, _ := big.NewInt(0).SetString("999999600000000000000000", 10)
+	balance := cState.GetBalance(addr, coin)
+	if balance.Cmp(targetBalance) != 0 {
+		t.Fatalf("Target %s balance is not correct. Expected %s, got %s", coin, targetBalance, balance)
+	}
+
+	fromStake := cState.GetStateCandidate(fromPubkey).GetStakeOfAddress(addr, coin)
+	if fromStake.Value.Cmp(types.Big0) != 0 {
+		t.Fatalf("Stake value at source candidate is not correct. Expected %s, got %s", types.Big0, fromStake.Value)
+	}
+
+	toStake := cState.GetStateCandidate(toPubkey).GetStakeOfAddress(addr, coin)
+	if toStake == nil || toStake.Value.Cmp(value) != 0 {
+		t.Fatalf("Stake value at destination candidate is not correct. Expected %s", value)
+	}
+
+	redelegations := cState.GetStateRedelegations(currentBlock + cState.GetParams().UnbondPeriod)
+	if redelegations == nil || len(redelegations.List()) != 1 {
+		t.Fatalf("Redelegation record not found")
+	}
+
+	record := redelegations.List()[0]
+	if record.Address != addr || record.Value.Cmp(value) != 0 {
+		t.Fatalf("Redelegation record is not correct")
+	}
+}
+
+func TestRedelegateTxPunishment(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	cState := getState()
+
+	var fromPubkey ed25519.PubKeyEd25519
+	rand.Read(fromPubkey[:])
+	toPubkey := createTestCandidate(cState)
+
+	cState.CreateCandidate(types.Address{}, types.Address{}, fromPubkey[:], 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	addr := types.Address{1}
+	coin := types.GetBaseCoin()
+	value := helpers.BipToPip(big.NewInt(100))
+
+	cState.Delegate(addr, toPubkey, coin, big.NewInt(0).Set(value))
+	cState.GetOrNewStateRedelegations(100).AddRedelegation(addr, fromPubkey[:], toPubkey, coin, value)
+
+	var address [20]byte
+	copy(address[:], fromPubkey.Address().Bytes())
+
+	cState.PunishRedelegationsWithAddress(1, 100, address, 1)
+
+	expected := helpers.BipToPip(big.NewInt(95))
+	stake := cState.GetStateCandidate(toPubkey).GetStakeOfAddress(addr, coin)
+	if stake.Value.Cmp(expected) != 0 {
+		t.Fatalf("Stake value is not correct. Expected %s, got %s", expected, stake.Value)
+	}
+
+	if cState.GetTotalSlashed().Cmp(helpers.BipToPip(big.NewInt(5))) != 0 {
+		t.Fatalf("Total slashed is not correct. Got %s", cState.GetTotalSlashed())
+	}
+}
+
+func TestRedelegateTxPunishmentAfterUnbond(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	cState := getState()
+
+	var fromPubkey ed25519.PubKeyEd25519
+	rand.Read(fromPubkey[:])
+	toPubkey := createTestCandidate(cState)
+
+	cState.CreateCandidate(types.Address{}, types.Address{}, fromPubkey[:], 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	addr := types.Address{1}
+	coin := types.GetBaseCoin()
+	value := helpers.BipToPip(big.NewInt(100))
+
+	cState.Delegate(addr, toPubkey, coin, big.NewInt(0).Set(value))
+	cState.GetOrNewStateRedelegations(100).AddRedelegation(addr, fromPubkey[:], toPubkey, coin, value)
+
+	// delegator unbonds redelegated stake before misbehaviour of source candidate is punished
+	cState.SubStake(addr, toPubkey, coin, value)
+	cState.GetOrNewStateFrozenFunds(200).AddFund(addr, toPubkey, coin, value)
+	cState.CarryRedelegations(addr, toPubkey, coin, value, nil, 200)
+
+	var address [20]byte
+	copy(address[:], fromPubkey.Address().Bytes())
+
+	cState.PunishRedelegationsWithAddress(1, 100, address, 1)
+
+	expected := helpers.BipToPip(big.NewInt(95))
+	frozenFund := cState.GetStateFrozenFunds(200).List()[0]
+	if frozenFund.Value.Cmp(expected) != 0 {
+		t.Fatalf("Frozen fund value is not correct. Expected %s, got %s", expected, frozenFund.Value)
+	}
+
+	if cState.GetTotalSlashed().Cmp(helpers.BipToPip(big.NewInt(5))) != 0 {
+		t.Fatalf("Total slashed is not correct. Got %s", cState.GetTotalSlashed())
+	}
+}
+
+func TestRedelegateTxPunishmentAfterPubKeyChange(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	cState := getState()
+
+	var fromPubkey ed25519.PubKeyEd25519
+	rand.Read(fromPubkey[:])
+	toPubkey := createTestCandidate(cState)
+
+	cState.CreateCandidate(types.Address{}, types.Address{}, fromPubkey[:], 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+
+	addr := types.Address{1}
+	coin := types.GetBaseCoin()
+	value := helpers.BipToPip(big.NewInt(100))
+	unbonded := helpers.BipToPip(big.NewInt(40))
+
+	cState.Delegate(addr, toPubkey, coin, big.NewInt(0).Set(value))
+	cState.GetOrNewStateRedelegations(100).AddRedelegation(addr, fromPubkey[:], toPubkey, coin, value)
+
+	cState.SubStake(addr, toPubkey, coin, unbonded)
+	cState.GetOrNewStateFrozenFunds(200).AddFund(addr, toPubkey, coin, unbonded)
+	cState.CarryRedelegations(addr, toPubkey, coin, unbonded, nil, 200)
+
+	// both candidates change their keys before misbehaviour of source candidate is punished
+	newFromPubkey := make([]byte, 32)
+	rand.Read(newFromPubkey)
+	newToPubkey := make([]byte, 32)
+	rand.Read(newToPubkey)
+
+	cState.SetCandidatePubKeyChange(fromPubkey[:], newFromPubkey)
+	cState.SetCandidatePubKeyChange(toPubkey, newToPubkey)
+	cState.ApplyCandidatePubKeyChanges()
+
+	var oldAddress [20]byte
+	copy(oldAddress[:], fromPubkey.Address().Bytes())
+
+	cState.PunishRedelegationsWithAddress(1, 100, cState.ResolveEvidenceAddress(oldAddress), 1)
+
+	expectedStake := helpers.BipToPip(big.NewInt(57))
+	stake := cState.GetStateCandidate(newToPubkey).GetStakeOfAddress(addr, coin)
+	if stake == nil || stake.Value.Cmp(expectedStake) != 0 {
+		t.Fatalf("Stake value is not correct. Expected %s, got %v", expectedStake, stake)
+	}
+
+	expectedFund := helpers.BipToPip(big.NewInt(38))
+	frozenFund := cState.GetStateFrozenFunds(200).List()[0]
+	if frozenFund.Value.Cmp(expectedFund) != 0 || !bytes.Equal(frozenFund.CandidateKey, newToPubkey) {
+		t.Fatalf("Frozen fund is not correct. Expected %s of %x, got %s of %x", expectedFund, newToPubkey,
+			frozenFund.Value, frozenFund.CandidateKey)
+	}
+
+	if cState.GetTotalSlashed().Cmp(helpers.BipToPip(big.NewInt(5))) != 0 {
+		t.Fatalf("Total slashed is not correct. Got %s", cState.GetTotalSlashed())
+	}
+}
@@ -0,0 +1,212 @@
+package transaction
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"sync"
+)
+
+// signatureCacheSize is a number of transactions signers of which are kept in the cache
+const signatureCacheSize = 100000
+
+// maxMultisigSignatures is a maximal number of signatures of multisig transaction
+const maxMultisigSignatures = 32
+
+// RecoveredSigners caches signers recovered from signatures of transactions. It is shared by CheckTx, recheck of
+// mempool and DeliverTx, so signatures of a transaction are usually recovered once.
+var RecoveredSigners = NewSignatureCache(signatureCacheSize)
+
+// SignatureCache keeps signers of recently seen transactions by hashes of raw transactions. Least recently used
+// entries are evicted when the cache is full.
+type SignatureCache struct {
+	lock  sync.Mutex
+	size  int
+	items map[[sha256.Size]byte]*list.Element
+	order *list.List
+}
+
+type signatureCacheEntry struct {
+	key     [sha256.Size]byte
+	signers []types.Address
+
+	// decoded is a prevalidated transaction which is not run yet. It is taken by the first CheckStateless of the
+	// same raw transaction, so it is never shared.
+	decoded *Transaction
+}
+
+func NewSignatureCache(size int) *SignatureCache {
+	return &SignatureCache{
+		size:  size,
+		items: map[[sha256.Size]byte]*list.Element{},
+		order: list.New(),
+	}
+}
+
+// Len returns number of cached transactions
+func (c *SignatureCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *SignatureCache) get(key [sha256.Size]byte) ([]types.Address, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return element.Value.(*signatureCacheEntry).signers, true
+}
+
+// keepDecoded keeps decoded transaction until it is taken by takeDecoded. Transaction is kept only while its
+// signers are cached.
+func (c *SignatureCache) keepDecoded(rawTx []byte, tx *Transaction) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.items[sha256.Sum256(rawTx)]; ok {
+		element.Value.(*signatureCacheEntry).decoded = tx
+	}
+}
+
+// takeDecoded returns decoded transaction kept for given raw transaction, if any, and forgets it
+func (c *SignatureCache) takeDecoded(rawTx []byte) *Transaction {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[sha256.Sum256(rawTx)]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*signatureCacheEntry)
+	tx := entry.decoded
+	entry.decoded = nil
+
+	return tx
+}
+
+func (c *SignatureCache) add(key [sha256.Size]byte, signers []types.Address) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&signatureCacheEntry{key: key, signers: signers})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*signatureCacheEntry).key)
+	}
+}
+
+// recover returns signers of decoded raw transaction: the sender for single signature or signers of every
+// signature for multi signature. Signers are recovered only if they are not cached. Failed recoveries are not
+// cached.
+func (c *SignatureCache) recover(rawTx []byte, tx *Transaction) ([]types.Address, error) {
+	key := sha256.Sum256(rawTx)
+	if signers, ok := c.get(key); ok {
+		return signers, nil
+	}
+
+	var signers []types.Address
+	switch tx.SignatureType {
+	case SigTypeSingle:
+		sender, err := tx.Sender()
+		if err != nil {
+			return nil, err
+		}
+
+		signers = []types.Address{sender}
+	case SigTypeMulti:
+		txHash := tx.Hash()
+		for _, sig := range tx.multisig.Signatures {
+			signer, err := RecoverPlain(txHash, sig.R, sig.S, sig.V)
+			if err != nil {
+				return nil, err
+			}
+
+			signers = append(signers, signer)
+		}
+	default:
+		return nil, errors.New("unknown signature type")
+	}
+
+	c.add(key, signers)
+	return signers, nil
+}
+
+// CheckStateless performs checks of raw transaction which do not depend on state: limits of size, payload and
+// service data, decoding, chain id and recovery of signatures. Recovered signers are cached for RunTx. Codes of
+// failed checks are the same RunTx returns. Decoded transaction is returned if checks are passed, so it can be
+// run by RunDecodedTx without decoding it again.
+func CheckStateless(rawTx []byte) (*Transaction, Response) {
+	if len(rawTx) > maxTxLength {
+		return nil, Response{
+			Code: code.TxTooLarge,
+			Log:  fmt.Sprintf("TX length is over %d bytes", maxTxLength)}
+	}
+
+	// transaction prevalidated in the worker pool has passed the checks already
+	if tx := RecoveredSigners.takeDecoded(rawTx); tx != nil {
+		return tx, Response{Code: code.OK}
+	}
+
+	tx, err := TxDecoder.DecodeFromBytes(rawTx)
+	if err != nil {
+		return nil, Response{
+			Code: code.DecodeError,
+			Log:  err.Error()}
+	}
+
+	if tx.ChainID != types.CurrentChainID {
+		return nil, Response{
+			Code: code.WrongChainID,
+			Log:  "Wrong chain id"}
+	}
+
+	if len(tx.Payload) > maxPayloadLength {
+		return nil, Response{
+			Code: code.TxPayloadTooLarge,
+			Log:  fmt.Sprintf("TX payload length is over %d bytes", maxPayloadLength)}
+	}
+
+	if len(tx.ServiceData) > maxServiceDataLength {
+		return nil, Response{
+			Code: code.TxServiceDataTooLarge,
+			Log:  fmt.Sprintf("TX service data length is over %d bytes", maxServiceDataLength)}
+	}
+
+	if tx.SignatureType == SigTypeMulti && len(tx.multisig.Signatures) > maxMultisigSignatures {
+		return nil, Response{
+			Code: code.IncorrectMultiSignature,
+			Log:  "Incorrect multi-signature"}
+	}
+
+	if _, err := RecoveredSigners.recover(rawTx, tx); err != nil {
+		if tx.SignatureType == SigTypeMulti {
+			return nil, Response{
+				Code: code.IncorrectMultiSignature,
+				Log:  "Incorrect multi-signature"}
+		}
+
+		return nil, Response{
+			Code: code.DecodeError,
+			Log:  err.Error()}
+	}
+
+	return tx, Response{Code: code.OK}
+}
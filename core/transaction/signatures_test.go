@@ -0,0 +1,137 @@
+package transaction
+
+import (
+	"crypto/sha256"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/crypto"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func makeSendTx(t *testing.T, nonce uint64, payload []byte) ([]byte, types.Address) {
+	encodedData, _ := rlp.EncodeToBytes(SendData{
+		Coin:  types.GetBaseCoin(),
+		To:    types.Address{},
+		Value: big.NewInt(1),
+	})
+
+	tx := Transaction{
+		Nonce:         nonce,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       types.GetBaseCoin(),
+		Type:          TypeSend,
+		Data:          encodedData,
+		Payload:       payload,
+		SignatureType: SigTypeSingle,
+	}
+
+	privateKey, _ := crypto.GenerateKey()
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	rawTx, _ := rlp.EncodeToBytes(tx)
+	return rawTx, crypto.PubkeyToAddress(privateKey.PublicKey)
+}
+
+func TestSignatureCache_Eviction(t *testing.T) {
+	cache := NewSignatureCache(2)
+
+	for i := byte(1); i <= 3; i++ {
+		cache.add([sha256.Size]byte{i}, []types.Address{{i}})
+	}
+
+	if cache.Len() != 2 {
+		t.Fatalf("Cache should keep 2 entries, got %d", cache.Len())
+	}
+
+	if _, ok := cache.get([sha256.Size]byte{1}); ok {
+		t.Fatal("Oldest entry should be evicted")
+	}
+
+	if signers, ok := cache.get([sha256.Size]byte{3}); !ok || signers[0] != (types.Address{3}) {
+		t.Fatal("Newest entry should be kept")
+	}
+}
+
+func TestCheckStateless(t *testing.T) {
+	rawTx, sender := makeSendTx(t, 1, nil)
+
+	tx, response := CheckStateless(rawTx)
+	if response.Code != code.OK {
+		t.Fatalf("Response code is not correct. Expected %d, got %d", code.OK, response.Code)
+	}
+
+	if tx == nil || tx.Nonce != 1 {
+		t.Fatalf("Decoded transaction should be returned, got %v", tx)
+	}
+
+	signers, ok := RecoveredSigners.get(sha256.Sum256(rawTx))
+	if !ok || len(signers) != 1 || signers[0] != sender {
+		t.Fatalf("Sender %s should be cached, got %v", sender, signers)
+	}
+
+	tooLarge, _ := makeSendTx(t, 1, make([]byte, maxPayloadLength+1))
+	if _, response := CheckStateless(tooLarge); response.Code != code.TxPayloadTooLarge {
+		t.Fatalf("Response code is not correct. Expected %d, got %d", code.TxPayloadTooLarge, response.Code)
+	}
+}
+
+func TestPrevalidator_CheckAll(t *testing.T) {
+	prevalidator := NewPrevalidator(2)
+
+	var txs [][]byte
+	for i := uint64(1); i <= 5; i++ {
+		rawTx, _ := makeSendTx(t, i, nil)
+		txs = append(txs, rawTx)
+	}
+	txs = append(txs, []byte{1, 2, 3})
+
+	results := prevalidator.CheckAll(txs)
+	for i, result := range results[:5] {
+		if result.Code != code.OK {
+			t.Fatalf("Tx %d should be valid, got %d: %s", i, result.Code, result.Log)
+		}
+	}
+
+	if results[5].Code != code.DecodeError {
+		t.Fatalf("Response code is not correct. Expected %d, got %d", code.DecodeError, results[5].Code)
+	}
+
+	// decoded transaction is passed to the first following check only
+	kept := RecoveredSigners.takeDecoded(txs[0])
+	if kept == nil || kept.Nonce != 1 {
+		t.Fatalf("Decoded transaction should be kept, got %v", kept)
+	}
+
+	RecoveredSigners.keepDecoded(txs[0], kept)
+	if tx, response := CheckStateless(txs[0]); response.Code != code.OK || tx != kept {
+		t.Fatalf("Kept transaction should be returned without decoding")
+	}
+
+	if tx, response := CheckStateless(txs[0]); response.Code != code.OK || tx == kept {
+		t.Fatalf("Kept transaction should be taken once")
+	}
+}
+
+func TestRunDecodedTx(t *testing.T) {
+	rawTx, sender := makeSendTx(t, 1, nil)
+
+	tx, response := CheckStateless(rawTx)
+	if response.Code != code.OK {
+		t.Fatalf("Response code is not correct. Expected %d, got %d", code.OK, response.Code)
+	}
+
+	cState := getState()
+	cState.AddBalance(sender, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1000000)))
+
+	response = RunDecodedTx(cState, true, rawTx, tx, nil, 0, sync.Map{}, 0)
+	if response.Code != code.OK {
+		t.Fatalf("Response code is not correct. Expected %d, got %d: %s", code.OK, response.Code, response.Log)
+	}
+}
@@ -0,0 +1,214 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/commissions"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/formula"
+	"github.com/tendermint/tendermint/libs/common"
+	"math/big"
+	"strconv"
+)
+
+const (
+	maxProposalChanges = 16
+	maxActiveProposals = 16
+
+	// maxProposerActiveProposals is a maximal number of active proposals of one proposer
+	maxProposerActiveProposals = 1
+
+	maxUpgradeNameLength = 64
+	maxUpgradeInfoLength = 1024
+)
+
+type ParamChange struct {
+	Name  string   `json:"name"`
+	Value *big.Int `json:"value"`
+}
+
+type UpgradePlan struct {
+	Name   string `json:"name"`
+	Height uint64 `json:"height"`
+	Info   string `json:"info"`
+}
+
+type SubmitProposalData struct {
+	Changes []ParamChange `json:"changes"`
+	Upgrade []UpgradePlan `json:"upgrade,omitempty" rlp:"tail"`
+}
+
+func (data SubmitProposalData) TotalSpend(tx *Transaction, context *state.StateDB) (TotalSpends, []Conversion, *big.Int, *Response) {
+	panic("implement me")
+}
+
+func (data SubmitProposalData) BasicCheck(tx *Transaction, context *state.StateDB) *Response {
+	if len(data.Changes) > maxProposalChanges {
+		return &Response{
+			Code: code.InvalidProposal,
+			Log:  fmt.Sprintf("Proposal should contain at most %d changes", maxProposalChanges)}
+	}
+
+	if len(data.Upgrade) > 1 {
+		return &Response{
+			Code: code.InvalidProposal,
+			Log:  fmt.Sprintf("Proposal should contain at most one upgrade plan")}
+	}
+
+	if len(data.Changes) == 0 && len(data.Upgrade) == 0 {
+		return &Response{
+			Code: code.InvalidProposal,
+			Log:  fmt.Sprintf("Proposal should contain changes or upgrade plan")}
+	}
+
+	for _, plan := range data.Upgrade {
+		if len(plan.Name) == 0 || len(plan.Name) > maxUpgradeNameLength {
+			return &Response{
+				Code: code.InvalidProposal,
+				Log:  fmt.Sprintf("Upgrade name should be from 1 to %d bytes long", maxUpgradeNameLength)}
+		}
+
+		if len(plan.Info) > maxUpgradeInfoLength {
+			return &Response{
+				Code: code.InvalidProposal,
+				Log:  fmt.Sprintf("Upgrade info should be at most %d bytes long", maxUpgradeInfoLength)}
+		}
+
+		if context.IsUpgradeApplied(plan.Name) {
+			return &Response{
+				Code: code.InvalidProposal,
+				Log:  fmt.Sprintf("Upgrade %s is already applied", plan.Name)}
+		}
+	}
+
+	sender, _ := tx.Sender()
+	if !context.IsVoter(sender) {
+		return &Response{
+			Code: code.NotAVoter,
+			Log:  fmt.Sprintf("Only validators and their delegators can submit proposals")}
+	}
+
+	proposals := context.GetProposals()
+	if len(proposals) >= maxActiveProposals {
+		return &Response{
+			Code: code.TooManyProposals,
+			Log:  fmt.Sprintf("Too many active proposals")}
+	}
+
+	senderProposals := 0
+	for _, proposal := range proposals {
+		if proposal.Proposer == sender {
+			senderProposals++
+		}
+	}
+
+	if senderProposals >= maxProposerActiveProposals {
+		return &Response{
+			Code: code.TooManyProposals,
+			Log:  fmt.Sprintf("Proposer should have at most %d active proposals", maxProposerActiveProposals)}
+	}
+
+	// check if changes can be applied to current parameters
+	params := context.GetParams()
+	for _, change := range data.Changes {
+		if err := params.Set(change.Name, change.Value); err != nil {
+			return &Response{
+				Code: code.InvalidProposal,
+				Log:  err.Error()}
+		}
+	}
+
+	return nil
+}
+
+func (data SubmitProposalData) String() string {
+	return fmt.Sprintf("SUBMIT PROPOSAL changes: %d upgrade: %d",
+		len(data.Changes), len(data.Upgrade))
+}
+
+func (data SubmitProposalData) Gas() int64 {
+	return commissions.SubmitProposal
+}
+
+func (data SubmitProposalData) Run(tx *Transaction, context *state.StateDB, isCheck bool, rewardPool *big.Int, currentBlock uint64) Response {
+	sender, _ := tx.Sender()
+
+	response := data.BasicCheck(tx, context)
+	if response != nil {
+		return *response
+	}
+
+	votingEndHeight := currentBlock + context.GetParams().ProposalVotingPeriod
+	for _, plan := range data.Upgrade {
+		if plan.Height <= votingEndHeight {
+			return Response{
+				Code: code.InvalidProposal,
+				Log:  fmt.Sprintf("Upgrade height should be greater than voting end height %d", votingEndHeight)}
+		}
+	}
+
+	commissionInBaseCoin := tx.CommissionInBaseCoin()
+	commission := big.NewInt(0).Set(commissionInBaseCoin)
+
+	if !tx.GasCoin.IsBaseCoin() {
+		coin := context.GetStateCoin(tx.GasCoin)
+
+		if coin.ReserveBalance().Cmp(commissionInBaseCoin) < 0 {
+			return Response{
+				Code: code.CoinReserveNotSufficient,
+				Log:  fmt.Sprintf("Coin reserve balance is not sufficient for transaction. Has: %s, required %s", coin.ReserveBalance().String(), commissionInBaseCoin.String())}
+		}
+
+		commission = formula.CalculateSaleAmount(coin.Volume(), coin.ReserveBalance(), coin.Data().Crr, commissionInBaseCoin)
+	}
+
+	if context.GetBalance(sender, tx.GasCoin).Cmp(commission) < 0 {
+		return Response{
+			Code: code.InsufficientFunds,
+			Log:  fmt.Sprintf("Insufficient funds for sender account: %s. Wanted %s %s", sender.String(), commission, tx.GasCoin)}
+	}
+
+	var proposalID uint64
+	if !isCheck {
+		rewardPool.Add(rewardPool, commissionInBaseCoin)
+
+		context.SubCoinReserve(tx.GasCoin, commissionInBaseCoin)
+		context.SubCoinVolume(tx.GasCoin, commission)
+
+		context.SubBalance(sender, tx.GasCoin, commission)
+
+		changes := make([]state.ParamChange, len(data.Changes))
+		for i, change := range data.Changes {
+			changes[i] = state.ParamChange{
+				Name:  change.Name,
+				Value: big.NewInt(0).Set(change.Value),
+			}
+		}
+		var upgrade []state.UpgradePlan
+		for _, plan := range data.Upgrade {
+			upgrade = append(upgrade, state.UpgradePlan{
+				Name:   plan.Name,
+				Height: plan.Height,
+				Info:   plan.Info,
+			})
+		}
+
+		proposalID = context.AddProposal(sender, changes, upgrade, currentBlock)
+
+		context.SetNonce(sender, tx.Nonce)
+	}
+
+	tags := common.KVPairs{
+		common.KVPair{Key: []byte("tx.type"), Value: []byte(hex.EncodeToString([]byte{byte(TypeSubmitProposal)}))},
+		common.KVPair{Key: []byte("tx.from"), Value: []byte(hex.EncodeToString(sender[:]))},
+		common.KVPair{Key: []byte("tx.proposal_id"), Value: []byte(strconv.FormatUint(proposalID, 10))},
+	}
+
+	return Response{
+		Code:      code.OK,
+		GasUsed:   tx.Gas(),
+		GasWanted: tx.Gas(),
+		Tags:      tags,
+	}
+}
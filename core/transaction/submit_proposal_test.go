@@ -0,0 +1,270 @@
+package transaction
+
+import (
+	"crypto/ecdsa"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/crypto"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/MinterTeam/minter-go-node/upgrades"
+	"math/big"
+	"sync"
+	"testing"
+)
+
+func makeVoter(cState *state.StateDB, addr types.Address) {
+	pubkey := make([]byte, 32)
+	copy(pubkey, addr[:])
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+	cState.CreateValidator(addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+}
+
+func makeSubmitProposalTx(t *testing.T, privateKey *ecdsa.PrivateKey, nonce uint64, data SubmitProposalData) []byte {
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         nonce,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       types.GetBaseCoin(),
+		Type:          TypeSubmitProposal,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return encodedTx
+}
+
+func TestSubmitProposalTx(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+	makeVoter(cState, addr)
+
+	data := SubmitProposalData{
+		Changes: []ParamChange{
+			{Name: state.ParamUnbondPeriod, Value: big.NewInt(2 * state.UnbondPeriod)},
+		},
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeSubmitProposal,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+
+	if response.Code != 0 {
+		t.Fatalf("Response code is not 0. Error %s", response.Log)
+	}
+
+	targetBalance, _ := big.NewInt(0).SetString("999900000000000000000000", 10)
+	balance := cState.GetBalance(addr, coin)
+	if balance.Cmp(targetBalance) != 0 {
+		t.Fatalf("Target %s balance is not correct. Expected %s, got %s", coin, targetBalance, balance)
+	}
+
+	proposal := cState.GetProposal(1)
+	if proposal == nil {
+		t.Fatalf("Proposal not found")
+	}
+
+	if proposal.Proposer != addr {
+		t.Fatalf("Proposer is not correct. Expected %s, got %s", addr, proposal.Proposer)
+	}
+
+	if proposal.VotingEndHeight != upgrades.UpgradeBlock2+cState.GetParams().ProposalVotingPeriod {
+		t.Fatalf("Voting end height is not correct, got %d", proposal.VotingEndHeight)
+	}
+}
+
+func TestSubmitProposalTxWithInvalidParam(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+	makeVoter(cState, addr)
+
+	data := SubmitProposalData{
+		Changes: []ParamChange{
+			{Name: "unknown_param", Value: big.NewInt(1)},
+		},
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeSubmitProposal,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+
+	if response.Code != code.InvalidProposal {
+		t.Fatalf("Response code is not %d. Got %d", code.InvalidProposal, response.Code)
+	}
+}
+
+func TestSubmitProposalTxWithUpgradeBeforeVotingEnd(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+	makeVoter(cState, addr)
+
+	data := SubmitProposalData{
+		Upgrade: []UpgradePlan{
+			{Name: "v2", Height: upgrades.UpgradeBlock2 + 1},
+		},
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       coin,
+		Type:          TypeSubmitProposal,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+
+	if response.Code != code.InvalidProposal {
+		t.Fatalf("Response code is not %d. Got %d", code.InvalidProposal, response.Code)
+	}
+}
+
+func TestSubmitProposalTxNotAVoter(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	cState.AddBalance(addr, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1000000)))
+
+	encodedTx := makeSubmitProposalTx(t, privateKey, 1, SubmitProposalData{
+		Changes: []ParamChange{
+			{Name: state.ParamJailPeriod, Value: big.NewInt(1000)},
+		},
+	})
+
+	response := RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+
+	if response.Code != code.NotAVoter {
+		t.Fatalf("Response code is not %d. Got %d", code.NotAVoter, response.Code)
+	}
+}
+
+func TestSubmitProposalTxProposerLimit(t *testing.T) {
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	cState.AddBalance(addr, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1000000)))
+	makeVoter(cState, addr)
+
+	data := SubmitProposalData{
+		Changes: []ParamChange{
+			{Name: state.ParamJailPeriod, Value: big.NewInt(1000)},
+		},
+	}
+
+	response := RunTx(cState, false, makeSubmitProposalTx(t, privateKey, 1, data), big.NewInt(0),
+		upgrades.UpgradeBlock2, sync.Map{}, 0)
+
+	if response.Code != 0 {
+		t.Fatalf("Response code is not 0. Error %s", response.Log)
+	}
+
+	response = RunTx(cState, false, makeSubmitProposalTx(t, privateKey, 2, data), big.NewInt(0),
+		upgrades.UpgradeBlock2, sync.Map{}, 0)
+
+	if response.Code != code.TooManyProposals {
+		t.Fatalf("Response code is not %d. Got %d", code.TooManyProposals, response.Code)
+	}
+}
@@ -25,7 +25,23 @@ func (data SetCandidateOnData) TotalSpend(tx *Transaction, context *state.StateD
 }
 
 func (data SetCandidateOnData) BasicCheck(tx *Transaction, context *state.StateDB) *Response {
-	return checkCandidateOwnership(data, tx, context)
+	if response := checkCandidateOwnership(data, tx, context); response != nil {
+		return response
+	}
+
+	if jail := context.GetCandidateJail(data.PubKey); jail != nil && jail.IsJailed() {
+		return &Response{
+			Code: code.CandidateJailed,
+			Log:  fmt.Sprintf("Candidate is jailed. Send unjail transaction to switch it on")}
+	}
+
+	if !context.IsSelfStakeSufficient(data.PubKey) {
+		return &Response{
+			Code: code.SelfStakeTooLow,
+			Log:  fmt.Sprintf("Self-stake should be at least %s bip", context.GetParams().MinSelfStake)}
+	}
+
+	return nil
 }
 
 func (data SetCandidateOnData) String() string {
@@ -17,20 +17,29 @@ type TxType byte
 type SigType byte
 
 const (
-	TypeSend                TxType = 0x01
-	TypeSellCoin            TxType = 0x02
-	TypeSellAllCoin         TxType = 0x03
-	TypeBuyCoin             TxType = 0x04
-	TypeCreateCoin          TxType = 0x05
-	TypeDeclareCandidacy    TxType = 0x06
-	TypeDelegate            TxType = 0x07
-	TypeUnbond              TxType = 0x08
-	TypeRedeemCheck         TxType = 0x09
-	TypeSetCandidateOnline  TxType = 0x0A
-	TypeSetCandidateOffline TxType = 0x0B
-	TypeCreateMultisig      TxType = 0x0C
-	TypeMultisend           TxType = 0x0D
-	TypeEditCandidate       TxType = 0x0E
+	TypeSend                 TxType = 0x01
+	TypeSellCoin             TxType = 0x02
+	TypeSellAllCoin          TxType = 0x03
+	TypeBuyCoin              TxType = 0x04
+	TypeCreateCoin           TxType = 0x05
+	TypeDeclareCandidacy     TxType = 0x06
+	TypeDelegate             TxType = 0x07
+	TypeUnbond               TxType = 0x08
+	TypeRedeemCheck          TxType = 0x09
+	TypeSetCandidateOnline   TxType = 0x0A
+	TypeSetCandidateOffline  TxType = 0x0B
+	TypeCreateMultisig       TxType = 0x0C
+	TypeMultisend            TxType = 0x0D
+	TypeEditCandidate        TxType = 0x0E
+	TypeEditCommission       TxType = 0x0F
+	TypeRedelegate           TxType = 0x10
+	TypeSetCompoundRewards   TxType = 0x11
+	TypeSetRewardAddress     TxType = 0x12
+	TypeEditCandidateProfile TxType = 0x13
+	TypeSubmitProposal       TxType = 0x14
+	TypeVoteProposal         TxType = 0x15
+	TypeUnjail               TxType = 0x16
+	TypeEditCandidatePubKey  TxType = 0x17
 
 	SigTypeSingle SigType = 0x01
 	SigTypeMulti  SigType = 0x02
@@ -53,10 +62,11 @@ type Transaction struct {
 	SignatureType SigType
 	SignatureData []byte
 
-	decodedData Data
-	sig         *Signature
-	multisig    *SignatureMulti
-	sender      *types.Address
+	decodedData          Data
+	sig                  *Signature
+	multisig             *SignatureMulti
+	sender               *types.Address
+	commissionMultiplier *big.Int
 }
 
 type Signature struct {
@@ -124,11 +134,24 @@ func (tx *Transaction) payloadGas() int64 {
 
 func (tx *Transaction) CommissionInBaseCoin() *big.Int {
 	commissionInBaseCoin := big.NewInt(0).Mul(big.NewInt(int64(tx.GasPrice)), big.NewInt(tx.Gas()))
-	commissionInBaseCoin.Mul(commissionInBaseCoin, CommissionMultiplier)
+	commissionInBaseCoin.Mul(commissionInBaseCoin, tx.getCommissionMultiplier())
 
 	return commissionInBaseCoin
 }
 
+// SetCommissionMultiplier sets price of a gas unit in pips, which is a governance parameter
+func (tx *Transaction) SetCommissionMultiplier(multiplier *big.Int) {
+	tx.commissionMultiplier = multiplier
+}
+
+func (tx *Transaction) getCommissionMultiplier() *big.Int {
+	if tx.commissionMultiplier != nil {
+		return tx.commissionMultiplier
+	}
+
+	return CommissionMultiplier
+}
+
 func (tx *Transaction) String() string {
 	sender, _ := tx.Sender()
 
@@ -13,8 +13,6 @@ import (
 	"math/big"
 )
 
-const unbondPeriod = 518400
-
 type UnbondData struct {
 	PubKey types.Pubkey     `json:"pub_key"`
 	Coin   types.CoinSymbol `json:"coin"`
@@ -104,7 +102,7 @@ func (data UnbondData) Run(tx *Transaction, context *state.StateDB, isCheck bool
 
 	if !isCheck {
 		// now + 30 days
-		unbondAtBlock := uint64(currentBlock + unbondPeriod)
+		unbondAtBlock := currentBlock + context.GetParams().UnbondPeriod
 
 		rewardPool.Add(rewardPool, commissionInBaseCoin)
 
@@ -114,6 +112,7 @@ func (data UnbondData) Run(tx *Transaction, context *state.StateDB, isCheck bool
 		context.SubBalance(sender, tx.GasCoin, commission)
 		context.SubStake(sender, data.PubKey, data.Coin, data.Value)
 		context.GetOrNewStateFrozenFunds(unbondAtBlock).AddFund(sender, data.PubKey, data.Coin, data.Value)
+		context.CarryRedelegations(sender, data.PubKey, data.Coin, data.Value, nil, unbondAtBlock)
 		context.SetNonce(sender, tx.Nonce)
 	}
 
@@ -0,0 +1,115 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/commissions"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/formula"
+	"github.com/tendermint/tendermint/libs/common"
+	"math/big"
+)
+
+type UnjailData struct {
+	PubKey types.Pubkey `json:"pub_key"`
+}
+
+func (data UnjailData) GetPubKey() types.Pubkey {
+	return data.PubKey
+}
+
+func (data UnjailData) TotalSpend(tx *Transaction, context *state.StateDB) (TotalSpends, []Conversion, *big.Int, *Response) {
+	panic("implement me")
+}
+
+func (data UnjailData) BasicCheck(tx *Transaction, context *state.StateDB) *Response {
+	if response := checkCandidateOwnership(data, tx, context); response != nil {
+		return response
+	}
+
+	jail := context.GetCandidateJail(data.PubKey)
+	if jail == nil || !jail.IsJailed() {
+		return &Response{
+			Code: code.CandidateNotJailed,
+			Log:  fmt.Sprintf("Candidate is not jailed")}
+	}
+
+	if jail.Tombstoned {
+		return &Response{
+			Code: code.CandidateTombstoned,
+			Log:  fmt.Sprintf("Candidate is tombstoned for double signing and can not be unjailed")}
+	}
+
+	return nil
+}
+
+func (data UnjailData) String() string {
+	return fmt.Sprintf("UNJAIL pubkey: %x",
+		data.PubKey)
+}
+
+func (data UnjailData) Gas() int64 {
+	return commissions.Unjail
+}
+
+func (data UnjailData) Run(tx *Transaction, context *state.StateDB, isCheck bool, rewardPool *big.Int, currentBlock uint64) Response {
+	sender, _ := tx.Sender()
+
+	response := data.BasicCheck(tx, context)
+	if response != nil {
+		return *response
+	}
+
+	jailedUntil := context.GetCandidateJail(data.PubKey).JailedUntil
+	if currentBlock < jailedUntil {
+		return Response{
+			Code: code.CandidateJailed,
+			Log:  fmt.Sprintf("Candidate is jailed until block %d", jailedUntil)}
+	}
+
+	commissionInBaseCoin := tx.CommissionInBaseCoin()
+	commission := big.NewInt(0).Set(commissionInBaseCoin)
+
+	if !tx.GasCoin.IsBaseCoin() {
+		coin := context.GetStateCoin(tx.GasCoin)
+
+		if coin.ReserveBalance().Cmp(commissionInBaseCoin) < 0 {
+			return Response{
+				Code: code.CoinReserveNotSufficient,
+				Log:  fmt.Sprintf("Coin reserve balance is not sufficient for transaction. Has: %s, required %s", coin.ReserveBalance().String(), commissionInBaseCoin.String())}
+		}
+
+		commission = formula.CalculateSaleAmount(coin.Volume(), coin.ReserveBalance(), coin.Data().Crr, commissionInBaseCoin)
+	}
+
+	if context.GetBalance(sender, tx.GasCoin).Cmp(commission) < 0 {
+		return Response{
+			Code: code.InsufficientFunds,
+			Log:  fmt.Sprintf("Insufficient funds for sender account: %s. Wanted %s %s", sender.String(), commission, tx.GasCoin)}
+	}
+
+	if !isCheck {
+		rewardPool.Add(rewardPool, commissionInBaseCoin)
+
+		context.SubCoinReserve(tx.GasCoin, commissionInBaseCoin)
+		context.SubCoinVolume(tx.GasCoin, commission)
+
+		context.SubBalance(sender, tx.GasCoin, commission)
+		context.UnjailCandidate(data.PubKey)
+		context.SetNonce(sender, tx.Nonce)
+	}
+
+	tags := common.KVPairs{
+		common.KVPair{Key: []byte("tx.type"), Value: []byte(hex.EncodeToString([]byte{byte(TypeUnjail)}))},
+		common.KVPair{Key: []byte("tx.from"), Value: []byte(hex.EncodeToString(sender[:]))},
+	}
+
+	return Response{
+		Code:      code.OK,
+		GasUsed:   tx.Gas(),
+		GasWanted: tx.Gas(),
+		Tags:      tags,
+	}
+}
@@ -0,0 +1,154 @@
+package transaction
+
+import (
+	"crypto/ecdsa"
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	"github.com/MinterTeam/minter-go-node/crypto"
+	"github.com/MinterTeam/minter-go-node/eventsdb"
+	"github.com/MinterTeam/minter-go-node/helpers"
+	"github.com/MinterTeam/minter-go-node/rlp"
+	"github.com/MinterTeam/minter-go-node/upgrades"
+	"math/big"
+	"math/rand"
+	"sync"
+	"testing"
+)
+
+func TestUnjailTx(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+	cState.JailCandidate(pubkey, state.JailReasonAbsent)
+
+	response := runUnjailTx(t, cState, privateKey, pubkey)
+
+	if response.Code != 0 {
+		t.Fatalf("Response code is not 0. Error %s", response.Log)
+	}
+
+	targetBalance, _ := big.NewInt(0).SetString("999999900000000000000000", 10)
+	balance := cState.GetBalance(addr, coin)
+	if balance.Cmp(targetBalance) != 0 {
+		t.Fatalf("Target %s balance is not correct. Expected %s, got %s", coin, targetBalance, balance)
+	}
+
+	if cState.GetStateCandidate(pubkey).Status != state.CandidateStatusOnline {
+		t.Fatalf("Status has not changed")
+	}
+
+	jail := cState.GetCandidateJail(pubkey)
+	if jail.IsJailed() {
+		t.Fatalf("Candidate should be released from the jail")
+	}
+
+	if len(jail.History) != 1 || jail.History[0].Reason != state.JailReasonAbsent {
+		t.Fatalf("Jail history is not correct")
+	}
+}
+
+func TestUnjailTxBeforeJailPeriodEnd(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	cState := getState()
+
+	params := cState.GetParams()
+	params.JailPeriod = 2 * upgrades.UpgradeBlock2
+	cState.SetParams(params)
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+	cState.JailCandidate(pubkey, state.JailReasonAbsent)
+
+	response := runUnjailTx(t, cState, privateKey, pubkey)
+
+	if response.Code != code.CandidateJailed {
+		t.Fatalf("Response code is not %d. Got %d", code.CandidateJailed, response.Code)
+	}
+}
+
+func TestUnjailTxTombstoned(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.ValidatorMode = true
+	eventsdb.InitDB(cfg)
+
+	cState := getState()
+
+	privateKey, _ := crypto.GenerateKey()
+	addr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	coin := types.GetBaseCoin()
+	cState.AddBalance(addr, coin, helpers.BipToPip(big.NewInt(1000000)))
+
+	pubkey := make([]byte, 32)
+	rand.Read(pubkey)
+
+	cState.CreateCandidate(addr, addr, pubkey, 10, 0, types.GetBaseCoin(), helpers.BipToPip(big.NewInt(1)))
+	cState.JailCandidate(pubkey, state.JailReasonByzantine)
+
+	response := runUnjailTx(t, cState, privateKey, pubkey)
+
+	if response.Code != code.CandidateTombstoned {
+		t.Fatalf("Response code is not %d. Got %d", code.CandidateTombstoned, response.Code)
+	}
+
+	if !cState.IsCandidateTombstoned(pubkey) {
+		t.Fatalf("Candidate should be tombstoned")
+	}
+}
+
+func runUnjailTx(t *testing.T, cState *state.StateDB, privateKey *ecdsa.PrivateKey, pubkey []byte) Response {
+	data := UnjailData{
+		PubKey: pubkey,
+	}
+
+	encodedData, err := rlp.EncodeToBytes(data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := Transaction{
+		Nonce:         1,
+		GasPrice:      1,
+		ChainID:       types.CurrentChainID,
+		GasCoin:       types.GetBaseCoin(),
+		Type:          TypeUnjail,
+		Data:          encodedData,
+		SignatureType: SigTypeSingle,
+	}
+
+	if err := tx.Sign(privateKey); err != nil {
+		t.Fatal(err)
+	}
+
+	encodedTx, err := rlp.EncodeToBytes(tx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return RunTx(cState, false, encodedTx, big.NewInt(0), upgrades.UpgradeBlock2, sync.Map{}, 0)
+}
@@ -0,0 +1,106 @@
+package transaction
+
+import (
+	"encoding/hex"
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/core/code"
+	"github.com/MinterTeam/minter-go-node/core/commissions"
+	"github.com/MinterTeam/minter-go-node/core/state"
+	"github.com/MinterTeam/minter-go-node/formula"
+	"github.com/tendermint/tendermint/libs/common"
+	"math/big"
+)
+
+type VoteProposalData struct {
+	ProposalID uint64 `json:"proposal_id"`
+	Option     byte   `json:"option"`
+}
+
+func (data VoteProposalData) TotalSpend(tx *Transaction, context *state.StateDB) (TotalSpends, []Conversion, *big.Int, *Response) {
+	panic("implement me")
+}
+
+func (data VoteProposalData) BasicCheck(tx *Transaction, context *state.StateDB) *Response {
+	if context.GetProposal(data.ProposalID) == nil {
+		return &Response{
+			Code: code.ProposalNotFound,
+			Log:  fmt.Sprintf("Proposal %d not found", data.ProposalID)}
+	}
+
+	if data.Option != state.VoteYes && data.Option != state.VoteNo && data.Option != state.VoteAbstain {
+		return &Response{
+			Code: code.WrongVoteOption,
+			Log:  fmt.Sprintf("Wrong vote option %d", data.Option)}
+	}
+
+	sender, _ := tx.Sender()
+	if !context.IsVoter(sender) {
+		return &Response{
+			Code: code.NotAVoter,
+			Log:  fmt.Sprintf("Only validators and their delegators can vote")}
+	}
+
+	return nil
+}
+
+func (data VoteProposalData) String() string {
+	return fmt.Sprintf("VOTE PROPOSAL id: %d option: %d",
+		data.ProposalID, data.Option)
+}
+
+func (data VoteProposalData) Gas() int64 {
+	return commissions.VoteProposal
+}
+
+func (data VoteProposalData) Run(tx *Transaction, context *state.StateDB, isCheck bool, rewardPool *big.Int, currentBlock uint64) Response {
+	sender, _ := tx.Sender()
+
+	response := data.BasicCheck(tx, context)
+	if response != nil {
+		return *response
+	}
+
+	commissionInBaseCoin := tx.CommissionInBaseCoin()
+	commission := big.NewInt(0).Set(commissionInBaseCoin)
+
+	if !tx.GasCoin.IsBaseCoin() {
+		coin := context.GetStateCoin(tx.GasCoin)
+
+		if coin.ReserveBalance().Cmp(commissionInBaseCoin) < 0 {
+			return Response{
+				Code: code.CoinReserveNotSufficient,
+				Log:  fmt.Sprintf("Coin reserve balance is not sufficient for transaction. Has: %s, required %s", coin.ReserveBalance().String(), commissionInBaseCoin.String())}
+		}
+
+		commission = formula.CalculateSaleAmount(coin.Volume(), coin.ReserveBalance(), coin.Data().Crr, commissionInBaseCoin)
+	}
+
+	if context.GetBalance(sender, tx.GasCoin).Cmp(commission) < 0 {
+		return Response{
+			Code: code.InsufficientFunds,
+			Log:  fmt.Sprintf("Insufficient funds for sender account: %s. Wanted %s %s", sender.String(), commission, tx.GasCoin)}
+	}
+
+	if !isCheck {
+		rewardPool.Add(rewardPool, commissionInBaseCoin)
+
+		context.SubCoinReserve(tx.GasCoin, commissionInBaseCoin)
+		context.SubCoinVolume(tx.GasCoin, commission)
+
+		context.SubBalance(sender, tx.GasCoin, commission)
+		context.VoteProposal(data.ProposalID, sender, data.Option)
+		context.SetNonce(sender, tx.Nonce)
+	}
+
+	tags := common.KVPairs{
+		common.KVPair{Key: []byte("tx.type"), Value: []byte(hex.EncodeToString([]byte{byte(TypeVoteProposal)}))},
+		common.KVPair{Key: []byte("tx.from"), Value: []byte(hex.EncodeToString(sender[:]))},
+	}
+
+	return Response{
+		Code:      code.OK,
+		GasUsed:   tx.Gas(),
+		GasWanted: tx.Gas(),
+		Tags:      tags,
+	}
+}
@@ -5,16 +5,26 @@ import (
 )
 
 type AppState struct {
-	Note         string       `json:"note"`
-	StartHeight  uint64       `json:"start_height"`
-	Validators   []Validator  `json:"validators,omitempty"`
-	Candidates   []Candidate  `json:"candidates,omitempty"`
-	Accounts     []Account    `json:"accounts,omitempty"`
-	Coins        []Coin       `json:"coins,omitempty"`
-	FrozenFunds  []FrozenFund `json:"frozen_funds,omitempty"`
-	UsedChecks   []UsedCheck  `json:"used_checks,omitempty"`
-	MaxGas       uint64       `json:"max_gas"`
-	TotalSlashed *big.Int     `json:"total_slashed"`
+	Note              string             `json:"note"`
+	StartHeight       uint64             `json:"start_height"`
+	Validators        []Validator        `json:"validators,omitempty"`
+	Candidates        []Candidate        `json:"candidates,omitempty"`
+	Accounts          []Account          `json:"accounts,omitempty"`
+	Coins             []Coin             `json:"coins,omitempty"`
+	FrozenFunds       []FrozenFund       `json:"frozen_funds,omitempty"`
+	UsedChecks        []UsedCheck        `json:"used_checks,omitempty"`
+	MaxGas            uint64             `json:"max_gas"`
+	TotalSlashed      *big.Int           `json:"total_slashed"`
+	CommissionChanges []CommissionChange `json:"commission_changes,omitempty"`
+	PubKeyChanges     []PubKeyChange     `json:"pub_key_changes,omitempty"`
+	RetiredPubKeys    []PubKeyChange     `json:"retired_pub_keys,omitempty"`
+	Redelegations     []Redelegation     `json:"redelegations,omitempty"`
+	AccountSettings   []AccountSettings  `json:"account_settings,omitempty"`
+	Params            *Params            `json:"params,omitempty"`
+	Proposals         []Proposal         `json:"proposals,omitempty"`
+	UpgradePlan       *UpgradePlan       `json:"upgrade_plan,omitempty"`
+	AppliedUpgrades   []string           `json:"applied_upgrades,omitempty"`
+	CandidateJails    []CandidateJail    `json:"candidate_jails,omitempty"`
 }
 
 type Validator struct {
@@ -27,14 +37,29 @@ type Validator struct {
 }
 
 type Candidate struct {
-	RewardAddress  Address  `json:"reward_address"`
-	OwnerAddress   Address  `json:"owner_address"`
-	TotalBipStake  *big.Int `json:"total_bip_stake"`
-	PubKey         Pubkey   `json:"pub_key"`
-	Commission     uint     `json:"commission"`
-	Stakes         []Stake  `json:"stakes"`
-	CreatedAtBlock uint     `json:"created_at_block"`
-	Status         byte     `json:"status"`
+	RewardAddress  Address           `json:"reward_address"`
+	OwnerAddress   Address           `json:"owner_address"`
+	TotalBipStake  *big.Int          `json:"total_bip_stake"`
+	PubKey         Pubkey            `json:"pub_key"`
+	Commission     uint              `json:"commission"`
+	Stakes         []Stake           `json:"stakes"`
+	CreatedAtBlock uint              `json:"created_at_block"`
+	Status         byte              `json:"status"`
+	Profile        *CandidateProfile `json:"profile,omitempty"`
+}
+
+type CandidateJail struct {
+	PubKey      Pubkey `json:"pub_key"`
+	JailedUntil uint64 `json:"jailed_until"`
+	Tombstoned  bool   `json:"tombstoned"`
+}
+
+type CandidateProfile struct {
+	Moniker         string `json:"moniker"`
+	Website         string `json:"website"`
+	Description     string `json:"description"`
+	SecurityContact string `json:"security_contact"`
+	LogoHash        string `json:"logo_hash"`
 }
 
 type Stake struct {
@@ -60,6 +85,77 @@ type FrozenFund struct {
 	Value        *big.Int   `json:"value"`
 }
 
+type Redelegation struct {
+	Height           uint64     `json:"height"`
+	Address          Address    `json:"address"`
+	FromCandidateKey Pubkey     `json:"from_candidate_key"`
+	ToCandidateKey   Pubkey     `json:"to_candidate_key"`
+	Coin             CoinSymbol `json:"coin"`
+	Value            *big.Int   `json:"value"`
+	UnbondHeight     uint64     `json:"unbond_height,omitempty"`
+}
+
+type CommissionChange struct {
+	PubKey     Pubkey `json:"pub_key"`
+	Commission uint   `json:"commission"`
+	Height     uint64 `json:"height"`
+}
+
+type PubKeyChange struct {
+	PubKey    Pubkey `json:"pub_key"`
+	NewPubKey Pubkey `json:"new_pub_key"`
+}
+
+type AccountSettings struct {
+	Address         Address `json:"address"`
+	CompoundRewards bool    `json:"compound_rewards"`
+	RewardAddress   Address `json:"reward_address"`
+}
+
+type Params struct {
+	UnbondPeriod                 uint64   `json:"unbond_period"`
+	ValidatorMaxAbsentTimes      uint64   `json:"validator_max_absent_times"`
+	AbsentSlashPercent           uint64   `json:"absent_slash_percent"`
+	ByzantineSlashPercent        uint64   `json:"byzantine_slash_percent"`
+	DAOCommission                uint64   `json:"dao_commission"`
+	ValidatorsUpdatePeriod       uint64   `json:"validators_update_period"`
+	CommissionChangeNoticePeriod uint64   `json:"commission_change_notice_period"`
+	CommissionMultiplier         *big.Int `json:"commission_multiplier"`
+	ProposalVotingPeriod         uint64   `json:"proposal_voting_period"`
+	ProposalQuorum               uint64   `json:"proposal_quorum"`
+	ProposalThreshold            uint64   `json:"proposal_threshold"`
+	JailPeriod                   uint64   `json:"jail_period"`
+	EvidenceMaxAge               uint64   `json:"evidence_max_age"`
+	MaxValidatorStakeShare       uint64   `json:"max_validator_stake_share"`
+	MinSelfStake                 *big.Int `json:"min_self_stake"`
+}
+
+type Proposal struct {
+	ID              uint64        `json:"id"`
+	Proposer        Address       `json:"proposer"`
+	Changes         []ParamChange `json:"changes"`
+	SubmitHeight    uint64        `json:"submit_height"`
+	VotingEndHeight uint64        `json:"voting_end_height"`
+	Votes           []Vote        `json:"votes,omitempty"`
+	Upgrade         *UpgradePlan  `json:"upgrade,omitempty"`
+}
+
+type UpgradePlan struct {
+	Name   string `json:"name"`
+	Height uint64 `json:"height"`
+	Info   string `json:"info"`
+}
+
+type ParamChange struct {
+	Name  string   `json:"name"`
+	Value *big.Int `json:"value"`
+}
+
+type Vote struct {
+	Voter  Address `json:"voter"`
+	Option byte    `json:"option"`
+}
+
 type UsedCheck string
 
 type Account struct {
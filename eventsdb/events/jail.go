@@ -0,0 +1,39 @@
+package events
+
+import (
+	"encoding/json"
+	"github.com/MinterTeam/minter-go-node/core/types"
+)
+
+type JailEvent struct {
+	ValidatorPubKey types.Pubkey
+	JailedUntil     uint64
+	Reason          byte
+	Tombstoned      bool
+}
+
+func (e JailEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ValidatorPubKey types.Pubkey `json:"validator_pub_key"`
+		JailedUntil     uint64       `json:"jailed_until"`
+		Reason          byte         `json:"reason"`
+		Tombstoned      bool         `json:"tombstoned"`
+	}{
+		ValidatorPubKey: e.ValidatorPubKey,
+		JailedUntil:     e.JailedUntil,
+		Reason:          e.Reason,
+		Tombstoned:      e.Tombstoned,
+	})
+}
+
+type UnjailEvent struct {
+	ValidatorPubKey types.Pubkey
+}
+
+func (e UnjailEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ValidatorPubKey types.Pubkey `json:"validator_pub_key"`
+	}{
+		ValidatorPubKey: e.ValidatorPubKey,
+	})
+}
@@ -0,0 +1,30 @@
+package events
+
+import (
+	"encoding/json"
+	"math/big"
+)
+
+type ProposalResultEvent struct {
+	ProposalID uint64
+	Passed     bool
+	Yes        []byte
+	No         []byte
+	Abstain    []byte
+}
+
+func (e ProposalResultEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ProposalID uint64 `json:"proposal_id"`
+		Passed     bool   `json:"passed"`
+		Yes        string `json:"yes"`
+		No         string `json:"no"`
+		Abstain    string `json:"abstain"`
+	}{
+		ProposalID: e.ProposalID,
+		Passed:     e.Passed,
+		Yes:        big.NewInt(0).SetBytes(e.Yes).String(),
+		No:         big.NewInt(0).SetBytes(e.No).String(),
+		Abstain:    big.NewInt(0).SetBytes(e.Abstain).String(),
+	})
+}
@@ -11,18 +11,30 @@ type RewardEvent struct {
 	Address         types.Address
 	Amount          []byte
 	ValidatorPubKey types.Pubkey
+	Compounded      bool
+	Recipient       types.Address
 }
 
 func (e RewardEvent) MarshalJSON() ([]byte, error) {
+	// recipient is shown only if rewards were paid to a separate address
+	var recipient string
+	if e.Recipient != (types.Address{}) && e.Recipient != e.Address {
+		recipient = e.Recipient.String()
+	}
+
 	return json.Marshal(struct {
 		Role            string       `json:"role"`
 		Address         string       `json:"address"`
 		Amount          string       `json:"amount"`
 		ValidatorPubKey types.Pubkey `json:"validator_pub_key"`
+		Compounded      bool         `json:"compounded,omitempty"`
+		Recipient       string       `json:"recipient,omitempty"`
 	}{
 		Role:            e.Role.String(),
 		Address:         e.Address.String(),
 		Amount:          big.NewInt(0).SetBytes(e.Amount).String(),
 		ValidatorPubKey: e.ValidatorPubKey,
+		Compounded:      e.Compounded,
+		Recipient:       recipient,
 	})
 }
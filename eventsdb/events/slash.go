@@ -6,11 +6,32 @@ import (
 	"math/big"
 )
 
+type SlashReason byte
+
+func (r SlashReason) String() string {
+	switch r {
+	case SlashReasonAbsent:
+		return "Absent"
+	case SlashReasonByzantine:
+		return "Byzantine"
+	}
+
+	return "Undefined"
+}
+
+const (
+	SlashReasonUndefined SlashReason = iota
+	SlashReasonAbsent
+	SlashReasonByzantine
+)
+
 type SlashEvent struct {
 	Address         types.Address
 	Amount          []byte
 	Coin            types.CoinSymbol
 	ValidatorPubKey types.Pubkey
+	Reason          SlashReason
+	EvidenceHeight  uint64
 }
 
 func (e SlashEvent) MarshalJSON() ([]byte, error) {
@@ -19,10 +40,14 @@ func (e SlashEvent) MarshalJSON() ([]byte, error) {
 		Amount          string       `json:"amount"`
 		Coin            string       `json:"coin"`
 		ValidatorPubKey types.Pubkey `json:"validator_pub_key"`
+		Reason          string       `json:"reason"`
+		EvidenceHeight  uint64       `json:"evidence_height"`
 	}{
 		Address:         e.Address.String(),
 		Amount:          big.NewInt(0).SetBytes(e.Amount).String(),
 		Coin:            e.Coin.String(),
 		ValidatorPubKey: e.ValidatorPubKey,
+		Reason:          e.Reason.String(),
+		EvidenceHeight:  e.EvidenceHeight,
 	})
 }
@@ -0,0 +1,45 @@
+package events
+
+import (
+	"encoding/json"
+	"github.com/MinterTeam/minter-go-node/core/types"
+)
+
+// ValidatorPowerChangeEvent is emitted when validator enters or leaves validator set or its voting power changes.
+// OldPower is 0 for added validators and NewPower is 0 for removed ones.
+type ValidatorPowerChangeEvent struct {
+	ValidatorPubKey types.Pubkey
+	OldPower        int64
+	NewPower        int64
+	ActiveSince     uint64
+}
+
+func (e ValidatorPowerChangeEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		ValidatorPubKey types.Pubkey `json:"validator_pub_key"`
+		OldPower        int64        `json:"old_power"`
+		NewPower        int64        `json:"new_power"`
+		ActiveSince     uint64       `json:"active_since"`
+	}{
+		ValidatorPubKey: e.ValidatorPubKey,
+		OldPower:        e.OldPower,
+		NewPower:        e.NewPower,
+		ActiveSince:     e.ActiveSince,
+	})
+}
+
+// PubKeyChangeEvent is emitted when candidate's consensus public key is replaced
+type PubKeyChangeEvent struct {
+	OldPubKey types.Pubkey
+	NewPubKey types.Pubkey
+}
+
+func (e PubKeyChangeEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		OldPubKey types.Pubkey `json:"old_pub_key"`
+		NewPubKey types.Pubkey `json:"new_pub_key"`
+	}{
+		OldPubKey: e.OldPubKey,
+		NewPubKey: e.NewPubKey,
+	})
+}
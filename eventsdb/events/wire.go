@@ -12,6 +12,16 @@ func RegisterAminoEvents(codec *amino.Codec) {
 		"minter/UnbondEvent", nil)
 	codec.RegisterConcrete(CoinLiquidationEvent{},
 		"minter/CoinLiquidationEvent", nil)
+	codec.RegisterConcrete(ProposalResultEvent{},
+		"minter/ProposalResultEvent", nil)
+	codec.RegisterConcrete(JailEvent{},
+		"minter/JailEvent", nil)
+	codec.RegisterConcrete(UnjailEvent{},
+		"minter/UnjailEvent", nil)
+	codec.RegisterConcrete(ValidatorPowerChangeEvent{},
+		"minter/ValidatorPowerChangeEvent", nil)
+	codec.RegisterConcrete(PubKeyChangeEvent{},
+		"minter/PubKeyChangeEvent", nil)
 }
 
 type Role byte
@@ -3,15 +3,23 @@ package eventsdb
 import (
 	"encoding/binary"
 	"github.com/MinterTeam/go-amino"
-	"github.com/MinterTeam/minter-go-node/cmd/utils"
 	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/MinterTeam/minter-go-node/core/types"
 	e "github.com/MinterTeam/minter-go-node/eventsdb/events"
+	"github.com/MinterTeam/minter-go-node/storage"
 	"github.com/tendermint/tendermint/libs/db"
+	"math"
 	"sync"
+	"time"
 )
 
 var cdc = amino.NewCodec()
 
+var (
+	validatorSlashesPrefix = []byte("sv")
+	addressSlashesPrefix   = []byte("sa")
+)
+
 var edb IEventsDB
 
 func init() {
@@ -22,7 +30,7 @@ func InitDB(cfg *config.Config) {
 	if cfg.ValidatorMode {
 		edb = NOOPEventsDB{}
 	} else {
-		edb = NewEventsDB(db.NewDB("events", db.DBBackendType(cfg.DBBackend), utils.GetMinterHome()+"/data"))
+		edb = NewEventsDB(storage.MustOpen(cfg, storage.Events))
 	}
 }
 
@@ -38,6 +46,13 @@ type IEventsDB interface {
 	AddEvent(height uint64, event e.Event)
 	LoadEvents(height uint64) e.Events
 	FlushEvents() error
+	ValidatorSlashHeights(pubkey types.Pubkey) []uint64
+	AddressSlashHeights(address types.Address) []uint64
+	SetBlockTime(height uint64, t time.Time)
+	AddressRewards(address types.Address, fromDay uint64, toDay uint64) []DayRewards
+	RecipientRewards(address types.Address, fromDay uint64, toDay uint64) []DayRewards
+	ValidatorRewards(pubkey types.Pubkey, role e.Role, fromDay uint64, toDay uint64) []DayRewards
+	RoleRewards(role e.Role, fromDay uint64, toDay uint64) []DayRewards
 }
 
 type NOOPEventsDB struct {
@@ -55,10 +70,41 @@ func (NOOPEventsDB) FlushEvents() error {
 	return nil
 }
 
+func (NOOPEventsDB) ValidatorSlashHeights(pubkey types.Pubkey) []uint64 {
+	return nil
+}
+
+func (NOOPEventsDB) AddressSlashHeights(address types.Address) []uint64 {
+	return nil
+}
+
+func (NOOPEventsDB) SetBlockTime(height uint64, t time.Time) {
+
+}
+
+func (NOOPEventsDB) AddressRewards(address types.Address, fromDay uint64, toDay uint64) []DayRewards {
+	return nil
+}
+
+func (NOOPEventsDB) RecipientRewards(address types.Address, fromDay uint64, toDay uint64) []DayRewards {
+	return nil
+}
+
+func (NOOPEventsDB) ValidatorRewards(pubkey types.Pubkey, role e.Role, fromDay uint64, toDay uint64) []DayRewards {
+	return nil
+}
+
+func (NOOPEventsDB) RoleRewards(role e.Role, fromDay uint64, toDay uint64) []DayRewards {
+	return nil
+}
+
 type EventsDB struct {
 	db    db.DB
 	cache *eventsCache
 
+	// time of the block which events are being collected
+	blockTime time.Time
+
 	lock sync.RWMutex
 }
 
@@ -120,9 +166,58 @@ func (db *EventsDB) FlushEvents() error {
 	db.cache.Clear()
 	db.db.Set(getKeyForHeight(height), bytes)
 
+	// index slashes by validator and by delegator
+	for _, event := range events {
+		slash, ok := event.(e.SlashEvent)
+		if !ok {
+			continue
+		}
+
+		db.db.Set(getSlashKey(validatorSlashesPrefix, slash.ValidatorPubKey, height), []byte{})
+		db.db.Set(getSlashKey(addressSlashesPrefix, slash.Address[:], height), []byte{})
+	}
+
+	db.aggregateRewards(height, events)
+
 	return nil
 }
 
+// ValidatorSlashHeights returns heights at which stakes of given validator were slashed
+func (db *EventsDB) ValidatorSlashHeights(pubkey types.Pubkey) []uint64 {
+	return db.slashHeights(validatorSlashesPrefix, pubkey)
+}
+
+// AddressSlashHeights returns heights at which stakes of given address were slashed
+func (db *EventsDB) AddressSlashHeights(address types.Address) []uint64 {
+	return db.slashHeights(addressSlashesPrefix, address[:])
+}
+
+func (db *EventsDB) slashHeights(prefix []byte, key []byte) []uint64 {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	start := getSlashKey(prefix, key, 0)
+	end := getSlashKey(prefix, key, math.MaxUint64)
+
+	it := db.db.Iterator(start, end)
+	defer it.Close()
+
+	var heights []uint64
+	for ; it.Valid(); it.Next() {
+		k := it.Key()
+		height := binary.BigEndian.Uint64(k[len(k)-8:])
+
+		// skip duplicates of the same height
+		if len(heights) > 0 && heights[len(heights)-1] == height {
+			continue
+		}
+
+		heights = append(heights, height)
+	}
+
+	return heights
+}
+
 func (db *EventsDB) setEvents(height uint64, events e.Events) {
 	db.cache.set(height, events)
 }
@@ -156,6 +251,14 @@ func (db *EventsDB) getEvents(height uint64) e.Events {
 	return events
 }
 
+func getSlashKey(prefix []byte, key []byte, height uint64) []byte {
+	result := make([]byte, 0, len(prefix)+len(key)+8)
+	result = append(result, prefix...)
+	result = append(result, key...)
+
+	return append(result, getKeyForHeight(height)...)
+}
+
 func getKeyForHeight(height uint64) []byte {
 	var h = make([]byte, 8)
 	binary.BigEndian.PutUint64(h, height)
@@ -0,0 +1,198 @@
+package eventsdb
+
+import (
+	"github.com/MinterTeam/minter-go-node/core/types"
+	e "github.com/MinterTeam/minter-go-node/eventsdb/events"
+	"github.com/tendermint/tendermint/libs/db"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestEventsDB_SlashHeights(t *testing.T) {
+	edb := NewEventsDB(db.NewMemDB())
+
+	pubkey := types.Pubkey(make([]byte, 32))
+	address := types.Address{1}
+
+	for _, height := range []uint64{5, 10} {
+		edb.AddEvent(height, e.SlashEvent{
+			Address:         address,
+			Amount:          []byte{1},
+			Coin:            types.GetBaseCoin(),
+			ValidatorPubKey: pubkey,
+			Reason:          e.SlashReasonByzantine,
+			EvidenceHeight:  height - 1,
+		})
+		edb.AddEvent(height, e.SlashEvent{
+			Address:         types.Address{2},
+			Amount:          []byte{1},
+			Coin:            types.GetBaseCoin(),
+			ValidatorPubKey: pubkey,
+			Reason:          e.SlashReasonByzantine,
+			EvidenceHeight:  height - 1,
+		})
+
+		if err := edb.FlushEvents(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	heights := edb.ValidatorSlashHeights(pubkey)
+	if len(heights) != 2 || heights[0] != 5 || heights[1] != 10 {
+		t.Fatalf("Validator slash heights are not correct: %v", heights)
+	}
+
+	heights = edb.AddressSlashHeights(address)
+	if len(heights) != 2 || heights[0] != 5 || heights[1] != 10 {
+		t.Fatalf("Address slash heights are not correct: %v", heights)
+	}
+
+	if len(edb.AddressSlashHeights(types.Address{3})) != 0 {
+		t.Fatalf("Address without slashes should have no slash heights")
+	}
+
+	events := edb.LoadEvents(10)
+	if len(events) != 2 || events[0].(e.SlashEvent).EvidenceHeight != 9 {
+		t.Fatalf("Events are not correct: %v", events)
+	}
+}
+
+func TestEventsDB_Rewards(t *testing.T) {
+	edb := NewEventsDB(db.NewMemDB())
+
+	pubkey := types.Pubkey(make([]byte, 32))
+	address := types.Address{1}
+	blockTime := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, height := range []uint64{12, 24, 36} {
+		edb.SetBlockTime(height, blockTime.Add(time.Duration(i)*6*time.Hour))
+		edb.AddEvent(height, e.RewardEvent{
+			Role:            e.RoleDelegator,
+			Address:         address,
+			Amount:          big.NewInt(100).Bytes(),
+			ValidatorPubKey: pubkey,
+		})
+		edb.AddEvent(height, e.RewardEvent{
+			Role:            e.RoleDAO,
+			Address:         types.Address{2},
+			Amount:          big.NewInt(10).Bytes(),
+			ValidatorPubKey: pubkey,
+		})
+
+		if err := edb.FlushEvents(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	day := DayOf(blockTime)
+
+	rewards := edb.AddressRewards(address, day, day+1)
+	if len(rewards) != 2 || rewards[0].Day != day || rewards[0].Amount.Cmp(big.NewInt(200)) != 0 ||
+		rewards[1].Day != day+1 || rewards[1].Amount.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("Address rewards are not correct: %v", rewards)
+	}
+
+	rewards = edb.ValidatorRewards(pubkey, e.RoleDelegator, day, day)
+	if len(rewards) != 1 || rewards[0].Amount.Cmp(big.NewInt(200)) != 0 {
+		t.Fatalf("Validator rewards are not correct: %v", rewards)
+	}
+
+	rewards = edb.RoleRewards(e.RoleDAO, day, day+1)
+	if len(rewards) != 2 || rewards[0].Amount.Cmp(big.NewInt(20)) != 0 {
+		t.Fatalf("Role rewards are not correct: %v", rewards)
+	}
+}
+
+func TestEventsDB_RewardsReplay(t *testing.T) {
+	edb := NewEventsDB(db.NewMemDB())
+
+	owner, recipient := types.Address{1}, types.Address{2}
+	blockTime := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	// the block is flushed again when it is replayed after restart
+	for i := 0; i < 2; i++ {
+		edb.SetBlockTime(10, blockTime)
+		edb.AddEvent(10, e.RewardEvent{
+			Role:            e.RoleDelegator,
+			Address:         owner,
+			Amount:          big.NewInt(100).Bytes(),
+			ValidatorPubKey: make([]byte, 32),
+			Recipient:       recipient,
+		})
+
+		if err := edb.FlushEvents(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	day := DayOf(blockTime)
+
+	rewards := edb.AddressRewards(owner, day, day)
+	if len(rewards) != 1 || rewards[0].Amount.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("Address rewards are not correct: %v", rewards)
+	}
+
+	rewards = edb.RecipientRewards(recipient, day, day)
+	if len(rewards) != 1 || rewards[0].Amount.Cmp(big.NewInt(100)) != 0 {
+		t.Fatalf("Recipient rewards are not correct: %v", rewards)
+	}
+
+	if len(edb.RecipientRewards(owner, day, day)) != 0 {
+		t.Fatalf("Owner should not receive rewards paid to recipient")
+	}
+}
+
+func TestEventsDB_DeleteEventsAbove(t *testing.T) {
+	edb := NewEventsDB(db.NewMemDB())
+
+	pubkey := types.Pubkey(make([]byte, 32))
+	address := types.Address{1}
+	blockTime := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	timeOf := func(height uint64) time.Time {
+		return blockTime.Add(time.Duration(height) * time.Hour)
+	}
+
+	for height := uint64(1); height <= 30; height++ {
+		edb.SetBlockTime(height, timeOf(height))
+		edb.AddEvent(height, e.RewardEvent{
+			Role:            e.RoleDelegator,
+			Address:         address,
+			Amount:          big.NewInt(100).Bytes(),
+			ValidatorPubKey: pubkey,
+		})
+
+		if height%10 == 0 {
+			edb.AddEvent(height, e.SlashEvent{
+				Address:         address,
+				Amount:          []byte{1},
+				Coin:            types.GetBaseCoin(),
+				ValidatorPubKey: pubkey,
+				Reason:          e.SlashReasonByzantine,
+				EvidenceHeight:  height - 1,
+			})
+		}
+
+		if err := edb.FlushEvents(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	edb.DeleteEventsAbove(15, timeOf)
+
+	if len(edb.LoadEvents(16)) != 0 || len(edb.LoadEvents(30)) != 0 || len(edb.LoadEvents(15)) != 1 {
+		t.Fatalf("Events above height are not deleted")
+	}
+
+	heights := edb.AddressSlashHeights(address)
+	if len(heights) != 1 || heights[0] != 10 {
+		t.Fatalf("Slash heights are not correct: %v", heights)
+	}
+
+	// blocks 1-11 are in the first day, 12-15 in the second one
+	day := DayOf(blockTime)
+	rewards := edb.AddressRewards(address, day, day+2)
+	if len(rewards) != 2 || rewards[0].Amount.Cmp(big.NewInt(1100)) != 0 || rewards[1].Amount.Cmp(big.NewInt(400)) != 0 {
+		t.Fatalf("Address rewards are not correct: %v", rewards)
+	}
+}
@@ -0,0 +1,168 @@
+package eventsdb
+
+import (
+	"encoding/binary"
+	"github.com/MinterTeam/minter-go-node/core/types"
+	e "github.com/MinterTeam/minter-go-node/eventsdb/events"
+	"math/big"
+	"time"
+)
+
+const secondsInDay = 24 * 60 * 60
+
+var (
+	addressRewardsPrefix   = []byte("ra")
+	recipientRewardsPrefix = []byte("rc")
+	validatorRewardsPrefix = []byte("rv")
+	roleRewardsPrefix      = []byte("rr")
+
+	// rewardsHeightKey holds height of the last block which rewards are added to totals
+	rewardsHeightKey = []byte("rh")
+)
+
+// DayRewards is a total amount of rewards paid during a day. Day is a number of days since unix epoch.
+type DayRewards struct {
+	Day    uint64
+	Amount *big.Int
+}
+
+// DayOf returns number of days since unix epoch for given time
+func DayOf(t time.Time) uint64 {
+	return uint64(t.Unix() / secondsInDay)
+}
+
+// SetBlockTime sets time of the block which events are being collected. It is used to aggregate rewards by days.
+func (db *EventsDB) SetBlockTime(height uint64, t time.Time) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.blockTime = t
+}
+
+// AddressRewards returns rewards earned by given address by days in given range, including rewards which were
+// paid to its reward address
+func (db *EventsDB) AddressRewards(address types.Address, fromDay uint64, toDay uint64) []DayRewards {
+	return db.rewardsRange(append(append([]byte{}, addressRewardsPrefix...), address[:]...), fromDay, toDay)
+}
+
+// RecipientRewards returns rewards received by given address by days in given range, including rewards earned by
+// other addresses which set it as reward address
+func (db *EventsDB) RecipientRewards(address types.Address, fromDay uint64, toDay uint64) []DayRewards {
+	return db.rewardsRange(append(append([]byte{}, recipientRewardsPrefix...), address[:]...), fromDay, toDay)
+}
+
+// ValidatorRewards returns rewards of given role paid for stakes of given validator by days in given range
+func (db *EventsDB) ValidatorRewards(pubkey types.Pubkey, role e.Role, fromDay uint64, toDay uint64) []DayRewards {
+	key := append(append([]byte{}, validatorRewardsPrefix...), pubkey...)
+	return db.rewardsRange(append(key, byte(role)), fromDay, toDay)
+}
+
+// RoleRewards returns rewards paid to given role by days in given range
+func (db *EventsDB) RoleRewards(role e.Role, fromDay uint64, toDay uint64) []DayRewards {
+	return db.rewardsRange(append(append([]byte{}, roleRewardsPrefix...), byte(role)), fromDay, toDay)
+}
+
+// aggregateRewards adds rewards of given events of the block to daily totals. Blocks which rewards are already
+// added, e.g. replayed after restart, are skipped.
+func (db *EventsDB) aggregateRewards(height uint64, events e.Events) {
+	if height <= db.getRewardsHeight() {
+		return
+	}
+
+	db.lock.RLock()
+	day := DayOf(db.blockTime)
+	db.lock.RUnlock()
+
+	db.updateRewards(events, day, false)
+	db.setRewardsHeight(height)
+}
+
+func (db *EventsDB) getRewardsHeight() uint64 {
+	result := db.db.Get(rewardsHeightKey)
+	if len(result) == 0 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(result)
+}
+
+func (db *EventsDB) setRewardsHeight(height uint64) {
+	db.db.Set(rewardsHeightKey, getKeyForHeight(height))
+}
+
+// updateRewards adds rewards of given events to totals of given day or subtracts them if revert is set
+func (db *EventsDB) updateRewards(events e.Events, day uint64, revert bool) {
+	totals := map[string]*big.Int{}
+	var keys []string
+
+	add := func(key []byte, amount *big.Int) {
+		k := string(getRewardsKey(key, day))
+		if _, has := totals[k]; !has {
+			totals[k] = big.NewInt(0)
+			keys = append(keys, k)
+		}
+		totals[k].Add(totals[k], amount)
+	}
+
+	for _, event := range events {
+		reward, ok := event.(e.RewardEvent)
+		if !ok {
+			continue
+		}
+
+		amount := big.NewInt(0).SetBytes(reward.Amount)
+
+		// recipient is set only if it may differ from the address
+		recipient := reward.Recipient
+		if recipient == (types.Address{}) {
+			recipient = reward.Address
+		}
+
+		add(append(append([]byte{}, addressRewardsPrefix...), reward.Address[:]...), amount)
+		add(append(append([]byte{}, recipientRewardsPrefix...), recipient[:]...), amount)
+		add(append(append(append([]byte{}, validatorRewardsPrefix...), reward.ValidatorPubKey...), byte(reward.Role)), amount)
+		add(append(append([]byte{}, roleRewardsPrefix...), byte(reward.Role)), amount)
+	}
+
+	for _, k := range keys {
+		total := big.NewInt(0).SetBytes(db.db.Get([]byte(k)))
+		if !revert {
+			total.Add(total, totals[k])
+		} else if total.Sub(total, totals[k]); total.Sign() <= 0 {
+			db.db.Delete([]byte(k))
+			continue
+		}
+
+		db.db.Set([]byte(k), total.Bytes())
+	}
+}
+
+func (db *EventsDB) rewardsRange(key []byte, fromDay uint64, toDay uint64) []DayRewards {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	it := db.db.Iterator(getRewardsKey(key, fromDay), getRewardsKey(key, toDay+1))
+	defer it.Close()
+
+	var result []DayRewards
+	for ; it.Valid(); it.Next() {
+		k := it.Key()
+		if len(k) != len(key)+8 {
+			continue
+		}
+
+		result = append(result, DayRewards{
+			Day:    binary.BigEndian.Uint64(k[len(k)-8:]),
+			Amount: big.NewInt(0).SetBytes(it.Value()),
+		})
+	}
+
+	return result
+}
+
+func getRewardsKey(key []byte, day uint64) []byte {
+	result := make([]byte, 0, len(key)+8)
+	result = append(result, key...)
+
+	return append(result, getKeyForHeight(day)...)
+}
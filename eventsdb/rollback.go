@@ -0,0 +1,50 @@
+package eventsdb
+
+import (
+	"encoding/binary"
+	e "github.com/MinterTeam/minter-go-node/eventsdb/events"
+	"math"
+	"time"
+)
+
+// DeleteEventsAbove removes events of all heights above given one together with their slash indexes and
+// rewards totals. blockTime should return time of the block of given height, rewards of the block are
+// subtracted from totals of its day.
+func (db *EventsDB) DeleteEventsAbove(height uint64, blockTime func(height uint64) time.Time) {
+	db.cache.Clear()
+
+	rewardsHeight := db.getRewardsHeight()
+
+	it := db.db.Iterator(getKeyForHeight(height+1), getKeyForHeight(math.MaxUint64))
+	var heights []uint64
+	for ; it.Valid(); it.Next() {
+		// indexes and rewards totals share the database with events
+		if key := it.Key(); len(key) == 8 {
+			heights = append(heights, binary.BigEndian.Uint64(key))
+		}
+	}
+	it.Close()
+
+	for _, h := range heights {
+		events := db.LoadEvents(h)
+
+		for _, event := range events {
+			slash, ok := event.(e.SlashEvent)
+			if !ok {
+				continue
+			}
+
+			db.db.Delete(getSlashKey(validatorSlashesPrefix, slash.ValidatorPubKey, h))
+			db.db.Delete(getSlashKey(addressSlashesPrefix, slash.Address[:], h))
+		}
+
+		if h <= rewardsHeight {
+			db.updateRewards(events, DayOf(blockTime(h)), true)
+		}
+		db.db.Delete(getKeyForHeight(h))
+	}
+
+	if rewardsHeight > height {
+		db.setRewardsHeight(height)
+	}
+}
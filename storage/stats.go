@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/tendermint/tendermint/libs/db"
+	"os"
+	"path/filepath"
+)
+
+// Stats describes database of a store
+type Stats struct {
+	Store   string
+	Backend string
+	Path    string
+	Exists  bool
+	Keys    uint64
+	Size    int64 // size of database files in bytes
+}
+
+// GetStats counts keys of store's database and size of its files. Database is not opened if it does not exist.
+// Stores kept in memory belong to a running node, so only their backend is reported.
+func GetStats(cfg *config.Config, store string) (Stats, error) {
+	stats := Stats{
+		Store:   store,
+		Backend: Backend(cfg, store),
+		Path:    Path(cfg, store),
+	}
+
+	if stats.Path == "" {
+		return stats, nil
+	}
+
+	if _, err := os.Stat(stats.Path); os.IsNotExist(err) {
+		return stats, nil
+	} else if err != nil {
+		return stats, err
+	}
+	stats.Exists = true
+
+	database, err := Open(cfg, store)
+	if err != nil {
+		return stats, err
+	}
+	stats.Keys = CountKeys(database)
+	database.Close()
+
+	stats.Size, err = dirSize(stats.Path)
+	return stats, err
+}
+
+// CountKeys returns number of keys in database
+func CountKeys(database db.DB) uint64 {
+	it := database.Iterator(nil, nil)
+	defer it.Close()
+
+	var count uint64
+	for ; it.Valid(); it.Next() {
+		count++
+	}
+
+	return count
+}
+
+// dirSize returns total size of files under path, which may be a file itself
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return size, err
+}
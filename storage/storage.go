@@ -0,0 +1,137 @@
+// Package storage opens databases of node's stores. Every store can use its own backend and directory, so e.g.
+// state can be kept on a fast disk while events are kept on a cheap one.
+package storage
+
+import (
+	"fmt"
+	"github.com/MinterTeam/minter-go-node/cmd/utils"
+	"github.com/MinterTeam/minter-go-node/config"
+	"github.com/tendermint/tendermint/libs/common"
+	"github.com/tendermint/tendermint/libs/db"
+	"path/filepath"
+)
+
+// Names of node's stores
+const (
+	State  = "state"
+	App    = "app"
+	Events = "events"
+)
+
+// MemoryBackend keeps a store in memory. Data of such store is lost when node stops, so it suits tests and
+// ephemeral nodes only.
+const MemoryBackend = string(db.MemDBBackend)
+
+// Stores are names of all node's stores
+var Stores = []string{State, App, Events}
+
+// Backend returns database backend of store set in config
+func Backend(cfg *config.Config, store string) string {
+	var backend string
+	switch store {
+	case State:
+		backend = cfg.StateDBBackend
+	case App:
+		backend = cfg.AppDBBackend
+	case Events:
+		backend = cfg.EventsDBBackend
+	}
+
+	if backend == "" {
+		return cfg.DBBackend
+	}
+
+	return backend
+}
+
+// Dir returns directory of store's database set in config
+func Dir(cfg *config.Config, store string) string {
+	var dir string
+	switch store {
+	case State:
+		dir = cfg.StateDBPath
+	case App:
+		dir = cfg.AppDBPath
+	case Events:
+		dir = cfg.EventsDBPath
+	}
+
+	if dir == "" {
+		return utils.GetMinterHome() + "/data"
+	}
+
+	if filepath.IsAbs(dir) {
+		return dir
+	}
+
+	return filepath.Join(utils.GetMinterHome(), dir)
+}
+
+// Path returns path of store's database files, it is empty for stores kept in memory
+func Path(cfg *config.Config, store string) string {
+	if Backend(cfg, store) == MemoryBackend {
+		return ""
+	}
+
+	return filepath.Join(Dir(cfg, store), store+".db")
+}
+
+// CheckEphemeral checks that state, app and Tendermint databases are either all kept in memory or all kept on
+// disk. State, app and Tendermint data should be at the same height on start, which is not the case if only some
+// of them are lost when node stops. Events may be kept in memory on their own.
+func CheckEphemeral(cfg *config.Config) error {
+	backends := map[string]string{
+		State:        Backend(cfg, State),
+		App:          Backend(cfg, App),
+		"tendermint": cfg.DBBackend,
+	}
+
+	memory := 0
+	for _, backend := range backends {
+		if backend == MemoryBackend {
+			memory++
+		}
+	}
+
+	if memory != 0 && memory != len(backends) {
+		return fmt.Errorf("%s backend should be used for state (%s), app (%s) and tendermint (%s) databases "+
+			"together", MemoryBackend, backends[State], backends[App], backends["tendermint"])
+	}
+
+	return nil
+}
+
+// Open opens database of store with backend and directory set in config. Directory is created if it does not
+// exist. Config is rejected if only some of state, app and Tendermint databases are kept in memory.
+func Open(cfg *config.Config, store string) (database db.DB, err error) {
+	if err := CheckEphemeral(cfg); err != nil {
+		return nil, err
+	}
+
+	backend, dir := Backend(cfg, store), Dir(cfg, store)
+
+	if backend != MemoryBackend {
+		if err := common.EnsureDir(dir, 0700); err != nil {
+			return nil, err
+		}
+	}
+
+	// db.NewDB panics on unknown backends and on errors of opening
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("can't open %s database: %v", store, r)
+		}
+	}()
+
+	return db.NewDB(store, db.DBBackendType(backend), dir), nil
+}
+
+// MustOpen is like Open but panics if database can't be opened
+func MustOpen(cfg *config.Config, store string) db.DB {
+	database, err := Open(cfg, store)
+	if err != nil {
+		panic(err)
+	}
+
+	return database
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"github.com/MinterTeam/minter-go-node/cmd/utils"
+	"github.com/MinterTeam/minter-go-node/config"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDir(t *testing.T) {
+	utils.MinterHome = "/minter"
+	defer func() { utils.MinterHome = "" }()
+
+	cfg := config.DefaultConfig()
+	cfg.StateDBPath = "/nvme/minter"
+	cfg.EventsDBPath = "events"
+
+	if dir := Dir(cfg, State); dir != "/nvme/minter" {
+		t.Fatalf("Absolute directory should be kept, got %s", dir)
+	}
+
+	if dir := Dir(cfg, Events); dir != filepath.Join("/minter", "events") {
+		t.Fatalf("Relative directory should be resolved against home, got %s", dir)
+	}
+
+	if dir := Dir(cfg, App); dir != "/minter/data" {
+		t.Fatalf("Default directory should be used, got %s", dir)
+	}
+}
+
+func TestOpen_Memory(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.EventsDBBackend = MemoryBackend
+
+	if backend := Backend(cfg, State); backend != cfg.DBBackend {
+		t.Fatalf("Default backend should be used, got %s", backend)
+	}
+
+	if path := Path(cfg, Events); path != "" {
+		t.Fatalf("Store kept in memory should have no path, got %s", path)
+	}
+
+	database, err := Open(cfg, Events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	database.Set([]byte("a"), []byte("1"))
+	database.Set([]byte("b"), []byte("2"))
+
+	if count := CountKeys(database); count != 2 {
+		t.Fatalf("Expected 2 keys, got %d", count)
+	}
+
+	home, err := ioutil.TempDir("", "minter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(home)
+
+	utils.MinterHome = home
+	defer func() { utils.MinterHome = "" }()
+
+	cfg.EventsDBBackend = "unknown"
+	if _, err := Open(cfg, Events); err == nil {
+		t.Fatal("Unknown backend should not be opened")
+	}
+}
+
+func TestCheckEphemeral(t *testing.T) {
+	cfg := config.DefaultConfig()
+	cfg.StateDBBackend = MemoryBackend
+
+	if _, err := Open(cfg, Events); err == nil {
+		t.Fatal("State should not be kept in memory while app and tendermint data are kept on disk")
+	}
+
+	cfg.AppDBBackend = MemoryBackend
+	if err := CheckEphemeral(cfg); err == nil {
+		t.Fatal("State and app should not be kept in memory while tendermint data is kept on disk")
+	}
+
+	cfg.DBBackend = MemoryBackend
+	if err := CheckEphemeral(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg.AppDBBackend = "goleveldb"
+	if err := CheckEphemeral(cfg); err == nil {
+		t.Fatal("App should not be kept on disk while state and tendermint data are kept in memory")
+	}
+}
@@ -1,4 +1,7 @@
 package upgrades
 
+// Heights of upgrades which are compiled into the binary. New upgrades are scheduled by governance proposals
+// and applied by handlers registered with minter.RegisterUpgradeHandler.
 const UpgradeBlock0 = 5760
 const UpgradeBlock1 = 250000
+const UpgradeBlock2 = 1000000